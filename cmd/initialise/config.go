@@ -12,6 +12,13 @@ type Config struct {
 	Database database.Config
 	Machine  *id.Config
 	Log      *logging.Config
+	Init     InitConfig
+}
+
+type InitConfig struct {
+	ConnectRetry ConnectRetryConfig
+	// GrantPrivileges is the set of privileges granted to the database user, ALL if empty
+	GrantPrivileges GrantPrivileges
 }
 
 func MustNewConfig(v *viper.Viper) *Config {
@@ -24,7 +31,8 @@ func MustNewConfig(v *viper.Viper) *Config {
 	err = config.Log.SetLogger()
 	logging.OnError(err).Fatal("unable to set logger")
 
-	id.Configure(config.Machine)
+	err = id.Configure(config.Machine)
+	logging.OnError(err).Fatal("unable to configure id generator")
 
 	return config
 }
@@ -0,0 +1,48 @@
+package initialise
+
+import (
+	"errors"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/database"
+)
+
+// cannotConnectNowCode is returned by postgres while the database is starting up
+const cannotConnectNowCode = "57P03"
+
+// ConnectRetryConfig defines how often connecting to the database is retried
+// if the database is not yet reachable
+type ConnectRetryConfig struct {
+	// Attempts is the maximum amount of connection attempts
+	Attempts int
+	// Backoff is the wait duration after the first failed attempt
+	// it is doubled after each further attempt
+	Backoff time.Duration
+}
+
+// connectWithRetry retries connect as long as the database refuses the connection
+// other errors like authentication failures are returned immediately
+func connectWithRetry(config ConnectRetryConfig, connect func() (*database.DB, error)) (db *database.DB, err error) {
+	backoff := config.Backoff
+	for attempt := 1; ; attempt++ {
+		db, err = connect()
+		if err == nil || !isRetriableConnectErr(err) || attempt >= config.Attempts {
+			return db, err
+		}
+		logging.WithFields("attempt", attempt, "attempts", config.Attempts, "backoff", backoff).WithError(err).Info("database not reachable, retry connect")
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func isRetriableConnectErr(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	pgErr := new(pgconn.PgError)
+	return errors.As(err, &pgErr) && pgErr.Code == cannotConnectNowCode
+}
@@ -0,0 +1,113 @@
+package initialise
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// stubConnector fails with err for the first refusals calls
+type stubConnector struct {
+	refusals int
+	err      error
+	calls    int
+}
+
+func (c *stubConnector) connect() (*database.DB, error) {
+	c.calls++
+	if c.calls <= c.refusals {
+		return nil, c.err
+	}
+	return new(database.DB), nil
+}
+
+func Test_connectWithRetry(t *testing.T) {
+	refused := zerrors.ThrowPreconditionFailed(
+		fmt.Errorf("failed to connect: %w", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}),
+		"DATAB-0pIWD", "Errors.Database.Connection.Failed",
+	)
+	authFailed := &pgconn.PgError{Code: "28P01"}
+
+	type args struct {
+		refusals int
+		err      error
+		attempts int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantCalls int
+		targetErr error
+	}{
+		{
+			name: "connected",
+			args: args{
+				attempts: 3,
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "refused, then connected",
+			args: args{
+				refusals: 2,
+				err:      refused,
+				attempts: 3,
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "database starting, then connected",
+			args: args{
+				refusals: 1,
+				err:      &pgconn.PgError{Code: cannotConnectNowCode},
+				attempts: 3,
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "refused, attempts exceeded",
+			args: args{
+				refusals: 3,
+				err:      refused,
+				attempts: 3,
+			},
+			wantCalls: 3,
+			targetErr: syscall.ECONNREFUSED,
+		},
+		{
+			name: "authentication failed",
+			args: args{
+				refusals: 1,
+				err:      authFailed,
+				attempts: 3,
+			},
+			wantCalls: 1,
+			targetErr: authFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &stubConnector{refusals: tt.args.refusals, err: tt.args.err}
+
+			db, err := connectWithRetry(ConnectRetryConfig{Attempts: tt.args.attempts, Backoff: time.Millisecond}, connector.connect)
+			if !errors.Is(err, tt.targetErr) {
+				t.Errorf("connectWithRetry() error = %v, want: %v", err, tt.targetErr)
+			}
+			if tt.targetErr == nil && db == nil {
+				t.Error("connectWithRetry() expected db")
+			}
+			if connector.calls != tt.wantCalls {
+				t.Errorf("connectWithRetry() calls = %d, want: %d", connector.calls, tt.wantCalls)
+			}
+		})
+	}
+}
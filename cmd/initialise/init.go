@@ -3,6 +3,7 @@ package initialise
 import (
 	"context"
 	"embed"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -19,6 +20,8 @@ var (
 
 	createUserStmt           string
 	grantStmt                string
+	grantDMLStmt             string
+	grantDMLSchemasStmt      string
 	databaseStmt             string
 	createEventstoreStmt     string
 	createProjectionsStmt    string
@@ -27,9 +30,30 @@ var (
 	createEventsStmt         string
 	createSystemSequenceStmt string
 	createUniqueConstraints  string
+	createIndexesStmt        string
 
 	roleAlreadyExistsCode = "42710"
 	dbAlreadyExistsCode   = "42P04"
+
+	stepLogFormat string
+	steps         []string
+)
+
+// names of the statements in sql/<dialect>/
+const (
+	userStmtName              = "01_user"
+	databaseStmtName          = "02_database"
+	grantStmtName             = "03_grant_user"
+	grantDMLStmtName          = "03_grant_user_dml"
+	grantDMLSchemasStmtName   = "03_grant_user_dml_schemas"
+	eventstoreStmtName        = "04_eventstore"
+	projectionsStmtName       = "05_projections"
+	systemStmtName            = "06_system"
+	encryptionKeysStmtName    = "07_encryption_keys_table"
+	eventsStmtName            = "08_events_table"
+	systemSequenceStmtName    = "09_system_sequence"
+	uniqueConstraintsStmtName = "10_unique_constraints_table"
+	indexesStmtName           = "11_indexes"
 )
 
 func New() *cobra.Command {
@@ -46,38 +70,58 @@ The user provided by flags needs privileges to
 - see other users and create a new one if the user does not exist
 - grant all rights of the ZITADEL database to the user created if not yet set
 `,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			setStepLogFormat(stepLogFormat, os.Stdout)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			config := MustNewConfig(viper.GetViper())
+			selection, err := parseStepSelection(steps)
+			logging.OnError(err).Fatal("invalid steps")
 
-			InitAll(cmd.Context(), config)
+			initSelected(cmd.Context(), config, selection)
 		},
 	}
 
-	cmd.AddCommand(newZitadel(), newDatabase(), newUser(), newGrant())
+	cmd.PersistentFlags().StringVar(&stepLogFormat, "step-log-format", stepLogFormatText, "format of the results of the init steps (text, json), json writes one line per step to stdout")
+	cmd.Flags().StringSliceVar(&steps, "steps", nil, "comma-separated list of the init steps to execute (e.g. 03_grant_user,04_eventstore), all steps are executed if empty")
+
+	cmd.AddCommand(newZitadel(), newDatabase(), newUser(), newGrant(), newVerify())
 	return cmd
 }
 
 func InitAll(ctx context.Context, config *Config) {
-	err := initialise(config.Database,
-		VerifyUser(config.Database.Username(), config.Database.Password()),
-		VerifyDatabase(config.Database.DatabaseName()),
-		VerifyGrant(config.Database.DatabaseName(), config.Database.Username()),
-	)
-	logging.OnError(err).Fatal("unable to initialize the database")
-
-	err = verifyZitadel(ctx, config.Database)
+	initSelected(ctx, config, nil)
+}
+
+// initSelected executes the init steps included in the selection
+func initSelected(ctx context.Context, config *Config, selection stepSelection) {
+	if steps := databaseSteps(config, selection); len(steps) > 0 {
+		err := initialise(config, steps...)
+		logging.OnError(err).Fatal("unable to initialize the database")
+	}
+
+	// the privileges on the schemas are granted after the schemas were created
+	grantSchemas := config.Init.GrantPrivileges == GrantPrivilegesDML && selection.includes(grantStmtName)
+	if !grantSchemas && !selection.includesAny(zitadelStmtNames) {
+		return
+	}
+	err := runStep("VerifyZitadel", func() error {
+		return verifyZitadel(ctx, config.Database, config.Init.GrantPrivileges, selection)
+	})
 	logging.OnError(err).Fatal("unable to initialize ZITADEL")
 }
 
-func initialise(config database.Config, steps ...func(*database.DB) error) error {
+func initialise(config *Config, steps ...func(*database.DB) error) error {
 	logging.Info("initialization started")
 
-	err := ReadStmts(config.Type())
+	err := ReadStmts(config.Database.Type())
 	if err != nil {
 		return err
 	}
 
-	db, err := database.Connect(config, true, dialect.DBPurposeQuery)
+	db, err := connectWithRetry(config.Init.ConnectRetry, func() (*database.DB, error) {
+		return database.Connect(config.Database, true, dialect.DBPurposeQuery)
+	})
 	if err != nil {
 		return err
 	}
@@ -88,7 +132,10 @@ func initialise(config database.Config, steps ...func(*database.DB) error) error
 
 func Init(db *database.DB, steps ...func(*database.DB) error) error {
 	for _, step := range steps {
-		if err := step(db); err != nil {
+		err := runStep(stepName(step), func() error {
+			return step(db)
+		})
+		if err != nil {
 			return err
 		}
 	}
@@ -97,52 +144,67 @@ func Init(db *database.DB, steps ...func(*database.DB) error) error {
 }
 
 func ReadStmts(typ string) (err error) {
-	createUserStmt, err = readStmt(typ, "01_user")
+	createUserStmt, err = readStmt(typ, userStmtName)
+	if err != nil {
+		return err
+	}
+
+	databaseStmt, err = readStmt(typ, databaseStmtName)
+	if err != nil {
+		return err
+	}
+
+	grantStmt, err = readStmt(typ, grantStmtName)
+	if err != nil {
+		return err
+	}
+
+	grantDMLStmt, err = readStmt(typ, grantDMLStmtName)
 	if err != nil {
 		return err
 	}
 
-	databaseStmt, err = readStmt(typ, "02_database")
+	grantDMLSchemasStmt, err = readStmt(typ, grantDMLSchemasStmtName)
 	if err != nil {
 		return err
 	}
 
-	grantStmt, err = readStmt(typ, "03_grant_user")
+	createEventstoreStmt, err = readStmt(typ, eventstoreStmtName)
 	if err != nil {
 		return err
 	}
 
-	createEventstoreStmt, err = readStmt(typ, "04_eventstore")
+	createProjectionsStmt, err = readStmt(typ, projectionsStmtName)
 	if err != nil {
 		return err
 	}
 
-	createProjectionsStmt, err = readStmt(typ, "05_projections")
+	createSystemStmt, err = readStmt(typ, systemStmtName)
 	if err != nil {
 		return err
 	}
 
-	createSystemStmt, err = readStmt(typ, "06_system")
+	createEncryptionKeysStmt, err = readStmt(typ, encryptionKeysStmtName)
 	if err != nil {
 		return err
 	}
 
-	createEncryptionKeysStmt, err = readStmt(typ, "07_encryption_keys_table")
+	createEventsStmt, err = readStmt(typ, eventsStmtName)
 	if err != nil {
 		return err
 	}
 
-	createEventsStmt, err = readStmt(typ, "08_events_table")
+	createSystemSequenceStmt, err = readStmt(typ, systemSequenceStmtName)
 	if err != nil {
 		return err
 	}
 
-	createSystemSequenceStmt, err = readStmt(typ, "09_system_sequence")
+	createUniqueConstraints, err = readStmt(typ, uniqueConstraintsStmtName)
 	if err != nil {
 		return err
 	}
 
-	createUniqueConstraints, err = readStmt(typ, "10_unique_constraints_table")
+	createIndexesStmt, err = readStmt(typ, indexesStmtName)
 	if err != nil {
 		return err
 	}
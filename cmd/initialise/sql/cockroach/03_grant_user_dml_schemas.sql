@@ -0,0 +1,5 @@
+-- replace %[1]s with the user
+-- executed in the ZITADEL database by the owner of the schemas
+GRANT USAGE ON SCHEMA eventstore, projections, system TO "%[1]s";
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA eventstore, projections, system TO "%[1]s";
+ALTER DEFAULT PRIVILEGES IN SCHEMA eventstore, projections, system GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO "%[1]s";
\ No newline at end of file
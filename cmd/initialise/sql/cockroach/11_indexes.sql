@@ -0,0 +1,2 @@
+-- supports filters by event type across the aggregates of an instance
+CREATE INDEX IF NOT EXISTS es_instance_event_type ON eventstore.events2 (instance_id, event_type, "position" DESC);
@@ -0,0 +1,4 @@
+-- replace the first %[1]s with the database
+-- replace the second \%[2]s with the user
+-- privileges on the tables are granted by the owner of the schemas
+GRANT CONNECT, TEMPORARY ON DATABASE "%[1]s" TO "%[2]s";
\ No newline at end of file
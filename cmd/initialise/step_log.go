@@ -0,0 +1,99 @@
+package initialise
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/database"
+)
+
+const (
+	stepLogFormatText = "text"
+	stepLogFormatJSON = "json"
+
+	stepOutcomeSucceeded = "succeeded"
+	stepOutcomeFailed    = "failed"
+)
+
+// StepResult describes the outcome of an initialization step
+type StepResult struct {
+	Step     string `json:"step"`
+	Duration string `json:"duration"`
+	Outcome  string `json:"outcome"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newStepResult(step string, took time.Duration, err error) *StepResult {
+	result := &StepResult{
+		Step:     step,
+		Duration: took.String(),
+		Outcome:  stepOutcomeSucceeded,
+	}
+	if err != nil {
+		result.Outcome = stepOutcomeFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// logStep is called after each step of the initialization
+var logStep = logStepText
+
+func logStepText(result *StepResult) {
+	logging.WithFields("step", result.Step, "took", result.Duration, "outcome", result.Outcome, "error", result.Error).Debug("init step done")
+}
+
+// jsonStepLogger writes each step result as a json line to w
+func jsonStepLogger(w io.Writer) func(*StepResult) {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(result *StepResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		err := encoder.Encode(result)
+		logging.OnError(err).Warn("unable to write step log")
+	}
+}
+
+// setStepLogFormat defines how the step results are logged
+func setStepLogFormat(format string, w io.Writer) {
+	switch format {
+	case stepLogFormatJSON:
+		logStep = jsonStepLogger(w)
+	case stepLogFormatText, "":
+		logStep = logStepText
+	default:
+		logging.WithFields("format", format).Fatal("unknown step log format")
+	}
+}
+
+// runStep executes the step and logs its result
+func runStep(name string, step func() error) error {
+	start := time.Now()
+	err := step()
+	logStep(newStepResult(name, time.Since(start), err))
+	return err
+}
+
+// stepName returns the name of the function which created the step
+// e.g. "VerifyUser" for the step returned by [VerifyUser]
+func stepName(step func(*database.DB) error) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(step).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return name
+	}
+	return parts[1]
+}
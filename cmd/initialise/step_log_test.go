@@ -0,0 +1,76 @@
+package initialise
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+)
+
+func TestInit_jsonStepLog(t *testing.T) {
+	errStep := errors.New("step failed")
+	tests := []struct {
+		name    string
+		steps   []func(*database.DB) error
+		wantErr error
+		want    []StepResult
+	}{
+		{
+			name: "all steps succeed",
+			steps: []func(*database.DB) error{
+				VerifyUser("zitadel", ""),
+				VerifyDatabase("zitadel"),
+			},
+			want: []StepResult{
+				{Step: "VerifyUser", Outcome: stepOutcomeSucceeded},
+				{Step: "VerifyDatabase", Outcome: stepOutcomeSucceeded},
+			},
+		},
+		{
+			name: "step fails",
+			steps: []func(*database.DB) error{
+				VerifyUser("zitadel", ""),
+				func(*database.DB) error { return errStep },
+				VerifyDatabase("zitadel"),
+			},
+			wantErr: errStep,
+			want: []StepResult{
+				{Step: "VerifyUser", Outcome: stepOutcomeSucceeded},
+				{Step: "TestInit_jsonStepLog", Outcome: stepOutcomeFailed, Error: errStep.Error()},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			createUserStmt = "CREATE USER %s"
+			databaseStmt = "CREATE DATABASE %s"
+			db := prepareDB(t,
+				expectExec("CREATE USER zitadel", nil),
+				expectExec("CREATE DATABASE zitadel", nil),
+			)
+
+			out := new(bytes.Buffer)
+			setStepLogFormat(stepLogFormatJSON, out)
+			defer setStepLogFormat(stepLogFormatText, nil)
+
+			err := Init(db.db, tt.steps...)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			decoder := json.NewDecoder(out)
+			got := make([]StepResult, 0, len(tt.want))
+			for decoder.More() {
+				var result StepResult
+				require.NoError(t, decoder.Decode(&result))
+				assert.NotEmpty(t, result.Duration)
+				result.Duration = ""
+				got = append(got, result)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package initialise
+
+import (
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// stmtNames are the names of all init statements in the order they are executed
+var stmtNames = []string{
+	userStmtName,
+	databaseStmtName,
+	grantStmtName,
+	systemStmtName,
+	encryptionKeysStmtName,
+	projectionsStmtName,
+	eventstoreStmtName,
+	eventsStmtName,
+	systemSequenceStmtName,
+	uniqueConstraintsStmtName,
+	indexesStmtName,
+}
+
+// zitadelStmtNames are the statements executed by [VerifyZitadel]
+var zitadelStmtNames = stmtNames[3:]
+
+// stepSelection defines which init statements are executed
+// a nil selection includes all statements
+type stepSelection map[string]bool
+
+// parseStepSelection returns the selection of the given statement names
+// if no names are given all statements are selected
+func parseStepSelection(names []string) (stepSelection, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	selection := make(stepSelection, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !isStmtName(name) {
+			return nil, zerrors.ThrowInvalidArgumentf(nil, "INIT-Wb3rl", "unknown step %q, possible steps are %s", name, strings.Join(stmtNames, ", "))
+		}
+		selection[name] = true
+	}
+	return selection, nil
+}
+
+func isStmtName(name string) bool {
+	for _, stmtName := range stmtNames {
+		if stmtName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s stepSelection) includes(stmt string) bool {
+	return s == nil || s[stmt]
+}
+
+func (s stepSelection) includesAny(stmts []string) bool {
+	for _, stmt := range stmts {
+		if s.includes(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// databaseSteps returns the selected steps which are executed by the admin user
+func databaseSteps(config *Config, selection stepSelection) []func(*database.DB) error {
+	steps := make([]func(*database.DB) error, 0, 3)
+	if selection.includes(userStmtName) {
+		steps = append(steps, VerifyUser(config.Database.Username(), config.Database.Password()))
+	}
+	if selection.includes(databaseStmtName) {
+		steps = append(steps, VerifyDatabase(config.Database.DatabaseName()))
+	}
+	if selection.includes(grantStmtName) {
+		steps = append(steps, VerifyGrant(config.Database.DatabaseName(), config.Database.Username(), config.Init.GrantPrivileges))
+	}
+	return steps
+}
@@ -0,0 +1,152 @@
+package initialise
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func Test_parseStepSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    stepSelection
+		wantErr bool
+	}{
+		{
+			name: "empty selects all",
+		},
+		{
+			name:  "selected steps",
+			names: []string{"03_grant_user", " 04_eventstore"},
+			want: stepSelection{
+				grantStmtName:      true,
+				eventstoreStmtName: true,
+			},
+		},
+		{
+			name:    "unknown step",
+			names:   []string{"03_grant_user", "11_unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStepSelection(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStepSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !zerrors.IsErrorInvalidArgument(err) {
+				t.Errorf("parseStepSelection() error = %v, want invalid argument", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStepSelection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_databaseSteps(t *testing.T) {
+	config := new(Config)
+	config.Database.SetConnector(&cockroach.Config{
+		Database: "zitadel",
+		User:     cockroach.User{Username: "zitadel-user"},
+	})
+
+	tests := []struct {
+		name      string
+		selection stepSelection
+		want      []string
+	}{
+		{
+			name: "all",
+			want: []string{"VerifyUser", "VerifyDatabase", "VerifyGrant"},
+		},
+		{
+			name: "order preserved",
+			selection: stepSelection{
+				grantStmtName: true,
+				userStmtName:  true,
+			},
+			want: []string{"VerifyUser", "VerifyGrant"},
+		},
+		{
+			name: "only zitadel steps",
+			selection: stepSelection{
+				eventstoreStmtName: true,
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := databaseSteps(config, tt.selection)
+			got := make([]string, len(steps))
+			for i, step := range steps {
+				got[i] = stepName(step)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("databaseSteps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_verifyZitadelStmts(t *testing.T) {
+	err := ReadStmts("cockroach") //TODO: check all dialects
+	if err != nil {
+		t.Errorf("unable to read stmts: %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name      string
+		selection stepSelection
+		db        db
+	}{
+		{
+			name: "only selected steps in order",
+			selection: stepSelection{
+				uniqueConstraintsStmtName: true,
+				eventstoreStmtName:        true,
+				systemSequenceStmtName:    true,
+			},
+			db: prepareDB(t,
+				expectExec("CREATE SCHEMA IF NOT EXISTS eventstore;\n\nGRANT ALL ON ALL TABLES IN SCHEMA eventstore TO \"zitadel-user\";", nil),
+				expectExec(createSystemSequenceStmt, nil),
+				expectExec(createUniqueConstraints, nil),
+			),
+		},
+		{
+			name: "indexes",
+			selection: stepSelection{
+				indexesStmtName: true,
+			},
+			db: prepareDB(t,
+				expectQuery(events2ExistsQuery, nil, []string{"exists"}, [][]driver.Value{{true}}),
+				expectExec(createIndexesStmt, nil),
+			),
+		},
+		{
+			name: "no zitadel steps",
+			selection: stepSelection{
+				grantStmtName: true,
+			},
+			db: prepareDB(t),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyZitadelStmts(context.Background(), tt.db.db, "zitadel-user", tt.selection); err != nil {
+				t.Errorf("verifyZitadelStmts() error = %v", err)
+			}
+			if err := tt.db.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
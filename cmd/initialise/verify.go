@@ -0,0 +1,184 @@
+package initialise
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/dialect"
+)
+
+var errObjectMissing = errors.New("object missing")
+
+func newVerify() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "verify the database is initialized",
+		Long: `Verifies that all objects created by the init steps exist.
+No statement which changes the database is executed.
+
+Prerequisites:
+- cockroachDB or postgreSQL
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			config := MustNewConfig(viper.GetViper())
+
+			err := verifyOnly(cmd.Context(), config.Database, config.Init.GrantPrivileges, os.Stdout)
+			logging.OnError(err).Fatal("database is not initialized")
+		},
+	}
+}
+
+// objectCheck verifies that the object created by the statement exists
+type objectCheck struct {
+	// stmt is the name of the statement which creates the object
+	stmt   string
+	object string
+	// query must return a single boolean row
+	query string
+	args  []any
+}
+
+// adminChecks verify the objects which are checked by the admin user
+func adminChecks(databaseName, username string, privileges GrantPrivileges) []*objectCheck {
+	return []*objectCheck{
+		{
+			stmt:   userStmtName,
+			object: "user " + username,
+			query:  "SELECT EXISTS(SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)",
+			args:   []any{username},
+		},
+		{
+			stmt:   databaseStmtName,
+			object: "database " + databaseName,
+			query:  "SELECT EXISTS(SELECT 1 FROM pg_catalog.pg_database WHERE datname = $1)",
+			args:   []any{databaseName},
+		},
+		{
+			stmt:   grantStmtName,
+			object: "grant on database " + databaseName + " to " + username,
+			query:  "SELECT has_database_privilege($1, $2, $3)",
+			args:   []any{username, databaseName, privileges.databasePrivilege()},
+		},
+	}
+}
+
+// zitadelChecks verify the objects inside the ZITADEL database
+func zitadelChecks() []*objectCheck {
+	return []*objectCheck{
+		schemaCheck(systemStmtName, "system"),
+		tableCheck(encryptionKeysStmtName, "system", "encryption_keys"),
+		schemaCheck(projectionsStmtName, "projections"),
+		schemaCheck(eventstoreStmtName, "eventstore"),
+		{
+			stmt:   eventsStmtName,
+			object: "table eventstore.events",
+			// if events already exists events2 is created during a setup job
+			query: "SELECT EXISTS(SELECT 1 FROM information_schema.tables WHERE table_schema = 'eventstore' AND table_name like 'events%')",
+		},
+		{
+			stmt:   systemSequenceStmtName,
+			object: "sequence eventstore.system_seq",
+			query:  "SELECT EXISTS(SELECT 1 FROM information_schema.sequences WHERE sequence_schema = $1 AND sequence_name = $2)",
+			args:   []any{"eventstore", "system_seq"},
+		},
+		tableCheck(uniqueConstraintsStmtName, "eventstore", "unique_constraints"),
+		{
+			stmt:   indexesStmtName,
+			object: "index eventstore.events2@es_instance_event_type",
+			// the index is created as soon as events2 exists
+			query: "SELECT NOT EXISTS(SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2) OR EXISTS(SELECT 1 FROM pg_catalog.pg_indexes WHERE schemaname = $1 AND tablename = $2 AND indexname = $3)",
+			args:  []any{"eventstore", "events2", "es_instance_event_type"},
+		},
+	}
+}
+
+// dmlChecks verify the privileges on the schemas granted if the ZITADEL user only has DML privileges
+func dmlChecks(username string) []*objectCheck {
+	checks := make([]*objectCheck, 0, 3)
+	for _, schema := range []string{"eventstore", "projections", "system"} {
+		checks = append(checks, &objectCheck{
+			stmt:   grantStmtName,
+			object: "usage on schema " + schema + " to " + username,
+			query:  "SELECT has_schema_privilege($1, $2, 'USAGE')",
+			args:   []any{username, schema},
+		})
+	}
+	return checks
+}
+
+func schemaCheck(stmt, schema string) *objectCheck {
+	return &objectCheck{
+		stmt:   stmt,
+		object: "schema " + schema,
+		query:  "SELECT EXISTS(SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)",
+		args:   []any{schema},
+	}
+}
+
+func tableCheck(stmt, schema, table string) *objectCheck {
+	return &objectCheck{
+		stmt:   stmt,
+		object: "table " + schema + "." + table,
+		query:  "SELECT EXISTS(SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2)",
+		args:   []any{schema, table},
+	}
+}
+
+// verifyOnly checks the objects of all init steps without executing any write
+// the first missing object is reported
+func verifyOnly(ctx context.Context, config database.Config, privileges GrantPrivileges, w io.Writer) (err error) {
+	defer func() {
+		if err != nil {
+			fmt.Fprintf(w, "FAIL: %v\n", err)
+			return
+		}
+		fmt.Fprintln(w, "PASS: database is initialized")
+	}()
+
+	adminDB, err := database.Connect(config, true, dialect.DBPurposeQuery)
+	if err != nil {
+		return err
+	}
+	defer adminDB.Close()
+	if err = verifyObjects(ctx, adminDB, w, adminChecks(config.DatabaseName(), config.Username(), privileges)...); err != nil {
+		return err
+	}
+
+	db, err := database.Connect(config, false, dialect.DBPurposeQuery)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	checks := zitadelChecks()
+	if privileges == GrantPrivilegesDML {
+		checks = append(checks, dmlChecks(config.Username())...)
+	}
+	return verifyObjects(ctx, db, w, checks...)
+}
+
+// verifyObjects executes the checks in order and stops at the first missing object
+func verifyObjects(ctx context.Context, db *database.DB, w io.Writer, checks ...*objectCheck) error {
+	for _, check := range checks {
+		var exists bool
+		err := db.QueryRowContext(ctx, func(row *sql.Row) error {
+			return row.Scan(&exists)
+		}, check.query, check.args...)
+		if err != nil {
+			return fmt.Errorf("%s: unable to check %s: %w", check.stmt, check.object, err)
+		}
+		if !exists {
+			return fmt.Errorf("%s: %s: %w", check.stmt, check.object, errObjectMissing)
+		}
+		fmt.Fprintf(w, "ok   %s: %s\n", check.stmt, check.object)
+	}
+	return nil
+}
@@ -11,11 +11,45 @@ import (
 	"github.com/zitadel/zitadel/internal/database"
 )
 
+// GrantPrivileges is the set of privileges granted to the database user
+type GrantPrivileges string
+
+const (
+	// GrantPrivilegesAll grants all privileges on the database, including DDL
+	GrantPrivilegesAll GrantPrivileges = "ALL"
+	// GrantPrivilegesDML allows the user to connect to the database and to read and write the tables of the ZITADEL schemas.
+	// The schemas and tables are created and owned by the admin user instead of the ZITADEL user.
+	// It's meant for setups where the schema is managed by a separate DDL user.
+	GrantPrivilegesDML GrantPrivileges = "DML"
+)
+
+// grantStatement returns the statement granting the privileges
+func grantStatement(privileges GrantPrivileges) (string, error) {
+	switch privileges {
+	case GrantPrivilegesAll, "":
+		return grantStmt, nil
+	case GrantPrivilegesDML:
+		return grantDMLStmt, nil
+	default:
+		return "", fmt.Errorf("unknown grant privileges %q, allowed are %q and %q", privileges, GrantPrivilegesAll, GrantPrivilegesDML)
+	}
+}
+
+// databasePrivilege returns the privilege on the database which is granted by the privileges
+func (p GrantPrivileges) databasePrivilege() string {
+	if p == GrantPrivilegesDML {
+		return "CONNECT"
+	}
+	return "CREATE"
+}
+
 func newGrant() *cobra.Command {
 	return &cobra.Command{
 		Use:   "grant",
-		Short: "set ALL grant to user",
-		Long: `Sets ALL grant to the database user.
+		Short: "set grant to user",
+		Long: `Sets the grant configured in Init.GrantPrivileges to the database user.
+ALL (default) grants all privileges, DML only allows the user to connect to the database.
+The DML privileges on the tables are granted by "zitadel init" after the schemas were created by the admin user.
 
 Prerequisites:
 - cockroachDB or postgreSQL
@@ -23,16 +57,28 @@ Prerequisites:
 		Run: func(cmd *cobra.Command, args []string) {
 			config := MustNewConfig(viper.GetViper())
 
-			err := initialise(config.Database, VerifyGrant(config.Database.DatabaseName(), config.Database.Username()))
+			err := initialise(config, VerifyGrant(config.Database.DatabaseName(), config.Database.Username(), config.Init.GrantPrivileges))
 			logging.OnError(err).Fatal("unable to set grant")
 		},
 	}
 }
 
-func VerifyGrant(databaseName, username string) func(*database.DB) error {
+func VerifyGrant(databaseName, username string, privileges GrantPrivileges) func(*database.DB) error {
 	return func(db *database.DB) error {
-		logging.WithFields("user", username, "database", databaseName).Info("verify grant")
+		logging.WithFields("user", username, "database", databaseName, "privileges", privileges).Info("verify grant")
 
-		return exec(db, fmt.Sprintf(grantStmt, databaseName, username), nil)
+		stmt, err := grantStatement(privileges)
+		if err != nil {
+			return err
+		}
+		return exec(db, fmt.Sprintf(stmt, databaseName, username), nil)
 	}
 }
+
+// grantDMLSchemas grants the DML privileges on the tables of the ZITADEL schemas,
+// including tables created later by the owner of the schemas
+func grantDMLSchemas(db *database.DB, username string) error {
+	logging.WithFields("user", username).Info("grant dml on schemas")
+
+	return exec(db, fmt.Sprintf(grantDMLSchemasStmt, username), nil)
+}
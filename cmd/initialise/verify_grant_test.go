@@ -3,19 +3,22 @@ package initialise
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 )
 
 func Test_verifyGrant(t *testing.T) {
 	type args struct {
-		db       db
-		database string
-		username string
+		db         db
+		database   string
+		username   string
+		privileges GrantPrivileges
 	}
 	tests := []struct {
 		name      string
 		args      args
 		targetErr error
+		wantErr   bool
 	}{
 		{
 			name: "doesn't exists, create fails",
@@ -23,8 +26,9 @@ func Test_verifyGrant(t *testing.T) {
 				db: prepareDB(t,
 					expectExec("GRANT ALL ON DATABASE \"zitadel\" TO \"zitadel-user\"", sql.ErrTxDone),
 				),
-				database: "zitadel",
-				username: "zitadel-user",
+				database:   "zitadel",
+				username:   "zitadel-user",
+				privileges: GrantPrivilegesAll,
 			},
 			targetErr: sql.ErrTxDone,
 		},
@@ -34,13 +38,26 @@ func Test_verifyGrant(t *testing.T) {
 				db: prepareDB(t,
 					expectExec("GRANT ALL ON DATABASE \"zitadel\" TO \"zitadel-user\"", nil),
 				),
-				database: "zitadel",
-				username: "zitadel-user",
+				database:   "zitadel",
+				username:   "zitadel-user",
+				privileges: GrantPrivilegesAll,
 			},
 			targetErr: nil,
 		},
 		{
 			name: "already exists",
+			args: args{
+				db: prepareDB(t,
+					expectExec("GRANT ALL ON DATABASE \"zitadel\" TO \"zitadel-user\"", nil),
+				),
+				database:   "zitadel",
+				username:   "zitadel-user",
+				privileges: GrantPrivilegesAll,
+			},
+			targetErr: nil,
+		},
+		{
+			name: "default privileges",
 			args: args{
 				db: prepareDB(t,
 					expectExec("GRANT ALL ON DATABASE \"zitadel\" TO \"zitadel-user\"", nil),
@@ -50,10 +67,37 @@ func Test_verifyGrant(t *testing.T) {
 			},
 			targetErr: nil,
 		},
+		{
+			name: "dml privileges",
+			args: args{
+				db: prepareDB(t,
+					expectExec("GRANT CONNECT ON DATABASE \"zitadel\" TO \"zitadel-user\"", nil),
+				),
+				database:   "zitadel",
+				username:   "zitadel-user",
+				privileges: GrantPrivilegesDML,
+			},
+			targetErr: nil,
+		},
+		{
+			name: "unknown privileges",
+			args: args{
+				db:         prepareDB(t),
+				database:   "zitadel",
+				username:   "zitadel-user",
+				privileges: "DDL",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := VerifyGrant(tt.args.database, tt.args.username)(tt.args.db.db); !errors.Is(err, tt.targetErr) {
+			err := VerifyGrant(tt.args.database, tt.args.username, tt.args.privileges)(tt.args.db.db)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("VerifyGrant() expected error")
+				}
+			} else if !errors.Is(err, tt.targetErr) {
 				t.Errorf("VerifyGrant() error = %v, want: %v", err, tt.targetErr)
 			}
 			if err := tt.args.db.mock.ExpectationsWereMet(); err != nil {
@@ -62,3 +106,41 @@ func Test_verifyGrant(t *testing.T) {
 		})
 	}
 }
+
+func Test_grantDMLSchemas(t *testing.T) {
+	err := ReadStmts("cockroach") //TODO: check all dialects
+	if err != nil {
+		t.Errorf("unable to read stmts: %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name      string
+		db        db
+		targetErr error
+	}{
+		{
+			name: "grant fails",
+			db: prepareDB(t,
+				expectExec(fmt.Sprintf(grantDMLSchemasStmt, "zitadel-user"), sql.ErrTxDone),
+			),
+			targetErr: sql.ErrTxDone,
+		},
+		{
+			name: "correct",
+			db: prepareDB(t,
+				expectExec(fmt.Sprintf(grantDMLSchemasStmt, "zitadel-user"), nil),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := grantDMLSchemas(tt.db.db, "zitadel-user"); !errors.Is(err, tt.targetErr) {
+				t.Errorf("grantDMLSchemas() error = %v, want: %v", err, tt.targetErr)
+			}
+			if err := tt.db.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
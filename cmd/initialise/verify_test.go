@@ -0,0 +1,79 @@
+package initialise
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func expectCheck(check *objectCheck, exists bool) []expectation {
+	args := make([]driver.Value, len(check.args))
+	for i, arg := range check.args {
+		args[i] = arg
+	}
+	return []expectation{
+		expectBegin(nil),
+		expectQuery(check.query, nil, []string{"exists"}, [][]driver.Value{{exists}}, args...),
+		expectCommit(nil),
+	}
+}
+
+func Test_verifyObjects(t *testing.T) {
+	checks := append(adminChecks("zitadel", "zitadel-user", GrantPrivilegesAll), zitadelChecks()...)
+
+	type args struct {
+		missing int
+	}
+	tests := []struct {
+		name       string
+		args       args
+		targetErr  error
+		wantOutput string
+	}{
+		{
+			name: "complete",
+			args: args{
+				missing: -1,
+			},
+			wantOutput: "ok   11_indexes: index eventstore.events2@es_instance_event_type\n",
+		},
+		{
+			name: "sequence missing",
+			args: args{
+				missing: 8,
+			},
+			targetErr:  errObjectMissing,
+			wantOutput: "ok   08_events_table: table eventstore.events\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectations := make([]expectation, 0, len(checks)*3)
+			for i, check := range checks {
+				expectations = append(expectations, expectCheck(check, i != tt.args.missing)...)
+				if i == tt.args.missing {
+					break
+				}
+			}
+			db := prepareDB(t, expectations...)
+			output := new(bytes.Buffer)
+
+			err := verifyObjects(context.Background(), db.db, output, checks...)
+			if !errors.Is(err, tt.targetErr) {
+				t.Errorf("verifyObjects() error = %v, want: %v", err, tt.targetErr)
+			}
+			if tt.targetErr != nil && !strings.HasPrefix(err.Error(), systemSequenceStmtName) {
+				t.Errorf("verifyObjects() error = %v, must report %s", err, systemSequenceStmtName)
+			}
+			if !strings.HasSuffix(output.String(), tt.wantOutput) {
+				t.Errorf("verifyObjects() output = %q, want suffix: %q", output.String(), tt.wantOutput)
+			}
+			if err := db.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/zitadel/zitadel/internal/database/dialect"
 )
 
+const events2ExistsQuery = "SELECT EXISTS(SELECT 1 FROM information_schema.tables WHERE table_schema = 'eventstore' AND table_name = 'events2')"
+
 func newZitadel() *cobra.Command {
 	return &cobra.Command{
 		Use:   "zitadel",
@@ -24,7 +26,7 @@ Prerequisites:
 `,
 		Run: func(cmd *cobra.Command, args []string) {
 			config := MustNewConfig(viper.GetViper())
-			err := verifyZitadel(cmd.Context(), config.Database)
+			err := verifyZitadel(cmd.Context(), config.Database, config.Init.GrantPrivileges, nil)
 			logging.OnError(err).Fatal("unable to init zitadel")
 		},
 	}
@@ -36,55 +38,99 @@ func VerifyZitadel(ctx context.Context, db *database.DB, config database.Config)
 		return err
 	}
 
-	logging.WithFields().Info("verify system")
-	if err := exec(db, fmt.Sprintf(createSystemStmt, config.Username()), nil); err != nil {
-		return err
+	return verifyZitadelStmts(ctx, db, config.Username(), nil)
+}
+
+// verifyZitadelStmts executes the statements of ZITADEL internals included in the selection
+func verifyZitadelStmts(ctx context.Context, db *database.DB, username string, selection stepSelection) error {
+	if selection.includes(systemStmtName) {
+		logging.WithFields().Info("verify system")
+		if err := exec(db, fmt.Sprintf(createSystemStmt, username), nil); err != nil {
+			return err
+		}
 	}
 
-	logging.WithFields().Info("verify encryption keys")
-	if err := createEncryptionKeys(ctx, db); err != nil {
-		return err
+	if selection.includes(encryptionKeysStmtName) {
+		logging.WithFields().Info("verify encryption keys")
+		if err := createEncryptionKeys(ctx, db); err != nil {
+			return err
+		}
 	}
 
-	logging.WithFields().Info("verify projections")
-	if err := exec(db, fmt.Sprintf(createProjectionsStmt, config.Username()), nil); err != nil {
-		return err
+	if selection.includes(projectionsStmtName) {
+		logging.WithFields().Info("verify projections")
+		if err := exec(db, fmt.Sprintf(createProjectionsStmt, username), nil); err != nil {
+			return err
+		}
 	}
 
-	logging.WithFields().Info("verify eventstore")
-	if err := exec(db, fmt.Sprintf(createEventstoreStmt, config.Username()), nil); err != nil {
-		return err
+	if selection.includes(eventstoreStmtName) {
+		logging.WithFields().Info("verify eventstore")
+		if err := exec(db, fmt.Sprintf(createEventstoreStmt, username), nil); err != nil {
+			return err
+		}
 	}
 
-	logging.WithFields().Info("verify events tables")
-	if err := createEvents(ctx, db); err != nil {
-		return err
+	if selection.includes(eventsStmtName) {
+		logging.WithFields().Info("verify events tables")
+		if err := createEvents(ctx, db); err != nil {
+			return err
+		}
 	}
 
-	logging.WithFields().Info("verify system sequence")
-	if err := exec(db, createSystemSequenceStmt, nil); err != nil {
-		return err
+	if selection.includes(systemSequenceStmtName) {
+		logging.WithFields().Info("verify system sequence")
+		if err := exec(db, createSystemSequenceStmt, nil); err != nil {
+			return err
+		}
 	}
 
-	logging.WithFields().Info("verify unique constraints")
-	if err := exec(db, createUniqueConstraints, nil); err != nil {
-		return err
+	if selection.includes(uniqueConstraintsStmtName) {
+		logging.WithFields().Info("verify unique constraints")
+		if err := exec(db, createUniqueConstraints, nil); err != nil {
+			return err
+		}
+	}
+
+	if selection.includes(indexesStmtName) {
+		logging.WithFields().Info("verify indexes")
+		if err := createIndexes(ctx, db); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func verifyZitadel(ctx context.Context, config database.Config) error {
-	logging.WithFields("database", config.DatabaseName()).Info("verify zitadel")
+// verifyZitadel executes the selected statements of ZITADEL internals.
+// If the DML privileges are granted, the ZITADEL user isn't allowed to create objects,
+// so the objects are created and owned by the admin user, who grants the DML privileges on the schemas afterwards.
+func verifyZitadel(ctx context.Context, config database.Config, privileges GrantPrivileges, selection stepSelection) (err error) {
+	logging.WithFields("database", config.DatabaseName(), "privileges", privileges).Info("verify zitadel")
+
+	err = ReadStmts(config.Type())
+	if err != nil {
+		return err
+	}
 
-	db, err := database.Connect(config, false, dialect.DBPurposeQuery)
+	var db *database.DB
+	if privileges == GrantPrivilegesDML {
+		db, err = database.ConnectAdminToDatabase(config, dialect.DBPurposeQuery)
+	} else {
+		db, err = database.Connect(config, false, dialect.DBPurposeQuery)
+	}
 	if err != nil {
 		return err
 	}
 
-	if err := VerifyZitadel(ctx, db, config); err != nil {
+	if err := verifyZitadelStmts(ctx, db, config.Username(), selection); err != nil {
 		return err
 	}
+	if privileges == GrantPrivilegesDML && selection.includes(grantStmtName) {
+		if err := grantDMLSchemas(db, config.Username()); err != nil {
+			return err
+		}
+	}
 
 	return db.Close()
 }
@@ -128,3 +174,15 @@ func createEvents(ctx context.Context, db *database.DB) (err error) {
 	_, err = tx.Exec(createEventsStmt)
 	return err
 }
+
+// createIndexes creates the indexes of the eventstore tables.
+// If events2 doesn't exist yet it's created during a setup job, the indexes are created by the next init.
+func createIndexes(ctx context.Context, db *database.DB) error {
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, events2ExistsQuery).Scan(&exists)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = db.ExecContext(ctx, createIndexesStmt)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -170,3 +171,69 @@ func Test_verifyEncryptionKeys(t *testing.T) {
 		})
 	}
 }
+
+func Test_readIndexesStmt(t *testing.T) {
+	for _, typ := range []string{"cockroach", "postgres"} {
+		t.Run(typ, func(t *testing.T) {
+			if err := ReadStmts(typ); err != nil {
+				t.Fatalf("unable to read stmts: %v", err)
+			}
+			if !strings.Contains(createIndexesStmt, "CREATE INDEX IF NOT EXISTS es_instance_event_type ON eventstore.events2") {
+				t.Errorf("unexpected indexes stmt: %q", createIndexesStmt)
+			}
+		})
+	}
+}
+
+func Test_createIndexes(t *testing.T) {
+	err := ReadStmts("cockroach") //TODO: check all dialects
+	if err != nil {
+		t.Errorf("unable to read stmts: %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name      string
+		db        db
+		targetErr error
+	}{
+		{
+			name: "events2 missing",
+			db: prepareDB(t,
+				expectQuery(events2ExistsQuery, nil, []string{"exists"}, [][]driver.Value{{false}}),
+			),
+		},
+		{
+			name: "query fails",
+			db: prepareDB(t,
+				expectQuery(events2ExistsQuery, sql.ErrConnDone, []string{"exists"}, nil),
+			),
+			targetErr: sql.ErrConnDone,
+		},
+		{
+			name: "create fails",
+			db: prepareDB(t,
+				expectQuery(events2ExistsQuery, nil, []string{"exists"}, [][]driver.Value{{true}}),
+				expectExec(createIndexesStmt, sql.ErrConnDone),
+			),
+			targetErr: sql.ErrConnDone,
+		},
+		{
+			name: "correct",
+			db: prepareDB(t,
+				expectQuery(events2ExistsQuery, nil, []string{"exists"}, [][]driver.Value{{true}}),
+				expectExec(createIndexesStmt, nil),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := createIndexes(context.Background(), tt.db.db); !errors.Is(err, tt.targetErr) {
+				t.Errorf("createIndexes() error = %v, want: %v", err, tt.targetErr)
+			}
+			if err := tt.db.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
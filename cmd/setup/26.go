@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 26.sql
+	addParentIDToOrgs string
+)
+
+type Orgs1AddParentID struct {
+	dbClient *database.DB
+}
+
+func (mig *Orgs1AddParentID) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addParentIDToOrgs)
+	return err
+}
+
+func (mig *Orgs1AddParentID) String() string {
+	return "26_orgs1_add_parent_id"
+}
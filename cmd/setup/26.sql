@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS projections.orgs1 ADD COLUMN IF NOT EXISTS parent_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS orgs1_parent_idx ON projections.orgs1 (parent_id);
@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 27.sql
+	addEnabledToExecutions string
+)
+
+type Executions1AddEnabled struct {
+	dbClient *database.DB
+}
+
+func (mig *Executions1AddEnabled) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addEnabledToExecutions)
+	return err
+}
+
+func (mig *Executions1AddEnabled) String() string {
+	return "27_executions1_add_enabled"
+}
@@ -0,0 +1,124 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 28.sql
+	addNormalizedNameToOrgs string
+)
+
+const (
+	// orgNamesBatchSize is the amount of orgs normalized per transaction
+	orgNamesBatchSize = 1000
+	// orgNamesQuery returns the next batch of orgs which are not normalized yet,
+	// the orgs are paginated by their primary key so each org is read at most once per execution
+	orgNamesQuery = "SELECT instance_id, id, name FROM projections.orgs1" +
+		" WHERE normalized_name = '' AND (instance_id, id) > ($1, $2)" +
+		" ORDER BY instance_id, id LIMIT $3"
+	setNormalizedNamesStmt = "UPDATE projections.orgs1 SET normalized_name = v.normalized_name" +
+		" FROM (VALUES %s) AS v (instance_id, id, normalized_name)" +
+		" WHERE projections.orgs1.instance_id = v.instance_id AND projections.orgs1.id = v.id"
+)
+
+type Orgs1AddNormalizedName struct {
+	dbClient *database.DB
+}
+
+type orgName struct {
+	instanceID, id, name string
+}
+
+// Execute adds the column and normalizes the existing names by [domain.NormalizeOrgName] like the projection does for new events.
+// The names are normalized in batches, each batch is committed in its own transaction.
+// If the step fails it continues with the orgs not normalized yet when it is executed again.
+func (mig *Orgs1AddNormalizedName) Execute(ctx context.Context, _ eventstore.Event) error {
+	if _, err := mig.dbClient.ExecContext(ctx, addNormalizedNameToOrgs); err != nil {
+		return err
+	}
+	var last orgName
+	for {
+		count, err := mig.normalizeBatch(ctx, &last)
+		if err != nil {
+			return err
+		}
+		logging.WithFields("migration", mig.String(), "count", count).Info("org names normalized")
+		if count < orgNamesBatchSize {
+			return nil
+		}
+	}
+}
+
+// normalizeBatch normalizes the names of the orgs following last and sets last to the last org of the batch
+func (mig *Orgs1AddNormalizedName) normalizeBatch(ctx context.Context, last *orgName) (_ int, err error) {
+	tx, err := mig.dbClient.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			logging.OnError(rollbackErr).Debug("rollback failed")
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	rows, err := tx.QueryContext(ctx, orgNamesQuery, last.instanceID, last.id, orgNamesBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var orgs []*orgName
+	for rows.Next() {
+		org := new(orgName)
+		if err = rows.Scan(&org.instanceID, &org.id, &org.name); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		orgs = append(orgs, org)
+	}
+	if err = errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, err
+	}
+	if len(orgs) == 0 {
+		return 0, nil
+	}
+
+	values := make([]string, len(orgs))
+	args := make([]any, 0, len(orgs)*3)
+	for i, org := range orgs {
+		values[i] = fmt.Sprintf("($%d::TEXT, $%d::TEXT, $%d::TEXT)", i*3+1, i*3+2, i*3+3)
+		args = append(args, org.instanceID, org.id, domain.NormalizeOrgName(org.name))
+	}
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf(setNormalizedNamesStmt, strings.Join(values, ", ")), args...); err != nil {
+		return 0, err
+	}
+	*last = *orgs[len(orgs)-1]
+	return len(orgs), nil
+}
+
+func (mig *Orgs1AddNormalizedName) String() string {
+	return "28_orgs1_add_normalized_name"
+}
+
+// ContinueOnErr skips the step if the org projection doesn't exist yet,
+// the projection creates the column itself.
+func (mig *Orgs1AddNormalizedName) ContinueOnErr(err error) bool {
+	pgErr := new(pgconn.PgError)
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "42P01"
+	}
+	return false
+}
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS projections.orgs1 ADD COLUMN IF NOT EXISTS normalized_name TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS orgs1_normalized_name_idx ON projections.orgs1 (instance_id, normalized_name);
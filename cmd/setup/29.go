@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 29.sql
+	addPreviousNameToOrgs string
+)
+
+type Orgs1AddPreviousName struct {
+	dbClient *database.DB
+}
+
+func (mig *Orgs1AddPreviousName) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addPreviousNameToOrgs)
+	return err
+}
+
+func (mig *Orgs1AddPreviousName) String() string {
+	return "29_orgs1_add_previous_name"
+}
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS projections.orgs1 ADD COLUMN IF NOT EXISTS previous_name TEXT;
+ALTER TABLE IF EXISTS projections.orgs1 ADD COLUMN IF NOT EXISTS previous_name_change_date TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS orgs1_previous_name_idx ON projections.orgs1 (previous_name);
@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 30.sql
+	addTerminationToSessions string
+)
+
+type Sessions9AddTermination struct {
+	dbClient *database.DB
+}
+
+func (mig *Sessions9AddTermination) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addTerminationToSessions)
+	return err
+}
+
+func (mig *Sessions9AddTermination) String() string {
+	return "30_sessions9_add_termination"
+}
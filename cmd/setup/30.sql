@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS projections.sessions9 ADD COLUMN IF NOT EXISTS terminated_at TIMESTAMPTZ;
+ALTER TABLE IF EXISTS projections.sessions9 ADD COLUMN IF NOT EXISTS termination_reason TEXT;
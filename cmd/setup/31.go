@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 31.sql
+	createActiveTables string
+)
+
+type ActiveTables struct {
+	dbClient *database.DB
+}
+
+func (mig *ActiveTables) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, createActiveTables)
+	return err
+}
+
+func (mig *ActiveTables) String() string {
+	return "31_active_tables"
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS projections.active_tables (
+    projection_name TEXT NOT NULL
+    , active_table TEXT NOT NULL
+    , change_date TIMESTAMPTZ NOT NULL
+
+    , PRIMARY KEY (projection_name)
+);
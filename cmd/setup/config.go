@@ -76,7 +76,8 @@ func MustNewConfig(v *viper.Viper) *Config {
 	err = config.Log.SetLogger()
 	logging.OnError(err).Fatal("unable to set logger")
 
-	id.Configure(config.Machine)
+	err = id.Configure(config.Machine)
+	logging.OnError(err).Fatal("unable to configure id generator")
 
 	return config
 }
@@ -104,6 +105,12 @@ type Steps struct {
 	s23CorrectGlobalUniqueConstraints      *CorrectGlobalUniqueConstraints
 	s24AddActorToAuthTokens                *AddActorToAuthTokens
 	s25User11AddLowerFieldsToVerifiedEmail *User11AddLowerFieldsToVerifiedEmail
+	s26Orgs1AddParentID                    *Orgs1AddParentID
+	s27Executions1AddEnabled               *Executions1AddEnabled
+	s28Orgs1AddNormalizedName              *Orgs1AddNormalizedName
+	s29Orgs1AddPreviousName                *Orgs1AddPreviousName
+	s30Sessions9AddTermination             *Sessions9AddTermination
+	s31ActiveTables                        *ActiveTables
 }
 
 func MustNewSteps(v *viper.Viper) *Steps {
@@ -138,6 +138,12 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 	steps.s23CorrectGlobalUniqueConstraints = &CorrectGlobalUniqueConstraints{dbClient: esPusherDBClient}
 	steps.s24AddActorToAuthTokens = &AddActorToAuthTokens{dbClient: queryDBClient}
 	steps.s25User11AddLowerFieldsToVerifiedEmail = &User11AddLowerFieldsToVerifiedEmail{dbClient: esPusherDBClient}
+	steps.s26Orgs1AddParentID = &Orgs1AddParentID{dbClient: queryDBClient}
+	steps.s27Executions1AddEnabled = &Executions1AddEnabled{dbClient: queryDBClient}
+	steps.s28Orgs1AddNormalizedName = &Orgs1AddNormalizedName{dbClient: queryDBClient}
+	steps.s29Orgs1AddPreviousName = &Orgs1AddPreviousName{dbClient: queryDBClient}
+	steps.s30Sessions9AddTermination = &Sessions9AddTermination{dbClient: queryDBClient}
+	steps.s31ActiveTables = &ActiveTables{dbClient: queryDBClient}
 
 	err = projection.Create(ctx, projectionDBClient, eventstoreClient, config.Projections, nil, nil, nil)
 	logging.OnError(err).Fatal("unable to start projections")
@@ -175,6 +181,7 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 		steps.s22ActiveInstancesIndex,
 		steps.s23CorrectGlobalUniqueConstraints,
 		steps.s24AddActorToAuthTokens,
+		steps.s31ActiveTables,
 	} {
 		mustExecuteMigration(ctx, eventstoreClient, step, "migration failed")
 	}
@@ -188,6 +195,11 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 		steps.s18AddLowerFieldsToLoginNames,
 		steps.s21AddBlockFieldToLimits,
 		steps.s25User11AddLowerFieldsToVerifiedEmail,
+		steps.s26Orgs1AddParentID,
+		steps.s27Executions1AddEnabled,
+		steps.s28Orgs1AddNormalizedName,
+		steps.s29Orgs1AddPreviousName,
+		steps.s30Sessions9AddTermination,
 	} {
 		mustExecuteMigration(ctx, eventstoreClient, step, "migration failed")
 	}
@@ -360,6 +372,9 @@ func initProjections(
 		eventstoreClient,
 		config.Login.DefaultOTPEmailURLV2,
 		config.SystemDefaults.Notifications.FileSystemPath,
+		config.SystemDefaults.Notifications.ObjectStore,
+		config.SystemDefaults.Notifications.EmailDryRun,
+		config.SystemDefaults.Notifications.SuppressedEmails,
 		keys.User,
 		keys.SMTP,
 		keys.SMS,
@@ -69,6 +69,12 @@ type Config struct {
 	LogStore          *logstore.Configs
 	Quotas            *QuotasConfig
 	Telemetry         *handlers.TelemetryPusherConfig
+	OrgDomainCache    OrgDomainCacheConfig
+}
+
+type OrgDomainCacheConfig struct {
+	TTL         time.Duration
+	NotFoundTTL time.Duration
 }
 
 type QuotasConfig struct {
@@ -111,7 +117,8 @@ func MustNewConfig(v *viper.Viper) *Config {
 	err = config.Metrics.NewMeter()
 	logging.OnError(err).Fatal("unable to set meter")
 
-	id.Configure(config.Machine)
+	err = id.Configure(config.Machine)
+	logging.OnError(err).Fatal("unable to configure id generator")
 	actions.SetHTTPConfig(&config.Actions.HTTP)
 
 	return config
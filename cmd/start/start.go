@@ -76,6 +76,7 @@ import (
 	"github.com/zitadel/zitadel/internal/logstore/record"
 	"github.com/zitadel/zitadel/internal/net"
 	"github.com/zitadel/zitadel/internal/notification"
+	"github.com/zitadel/zitadel/internal/notification/senders"
 	"github.com/zitadel/zitadel/internal/query"
 	"github.com/zitadel/zitadel/internal/static"
 	"github.com/zitadel/zitadel/internal/webauthn"
@@ -150,8 +151,13 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 		return err
 	}
 
-	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient)
-	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient)
+	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient,
+		new_es.WithCreationDate(config.Eventstore.EventCreationDate),
+		new_es.WithPayloadCompression(config.Eventstore.PayloadCompressionThreshold),
+	)
+	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient,
+		old_es.WithSlowQueryThreshold(config.Eventstore.FilterSlowQueryThreshold),
+	)
 	eventstoreClient := eventstore.NewEventstore(config.Eventstore)
 
 	sessionTokenVerifier := internal_authz.SessionTokenVerifier(keys.OIDC)
@@ -177,6 +183,7 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 		config.AuditLogRetention,
 		config.SystemAPIUsers,
 		true,
+		query.WithOrgDomainCache(config.OrgDomainCache.TTL, config.OrgDomainCache.NotFoundTTL),
 	)
 	if err != nil {
 		return fmt.Errorf("cannot start queries: %w", err)
@@ -255,11 +262,15 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 		eventstoreClient,
 		config.Login.DefaultOTPEmailURLV2,
 		config.SystemDefaults.Notifications.FileSystemPath,
+		config.SystemDefaults.Notifications.ObjectStore,
+		config.SystemDefaults.Notifications.EmailDryRun,
+		config.SystemDefaults.Notifications.SuppressedEmails,
 		keys.User,
 		keys.SMTP,
 		keys.SMS,
 	)
 	notification.Start(ctx)
+	notification.StartFailedEventsCleaner(ctx, queryDBClient, config.SystemDefaults.Notifications)
 
 	router := mux.NewRouter()
 	tlsConfig, err := config.TLS.Config()
@@ -365,6 +376,9 @@ func startAPIs(
 	if err != nil {
 		return nil, fmt.Errorf("error creating api %w", err)
 	}
+	if config.SystemDefaults.Notifications.ProbeSMTP {
+		apis.AddReadinessCheck(senders.ProbeEmailChannels(config.DefaultInstance.SMTPConfiguration))
+	}
 
 	config.Auth.Spooler.Client = dbClient
 	config.Auth.Spooler.Eventstore = eventstore
@@ -416,11 +430,11 @@ func startAPIs(
 	}
 	instanceInterceptor := middleware.InstanceInterceptor(queries, config.HTTP1HostHeader, config.ExternalDomain, login.IgnoreInstanceEndpoints...)
 	assetsCache := middleware.AssetsCacheInterceptor(config.AssetStorage.Cache.MaxAge, config.AssetStorage.Cache.SharedMaxAge)
-	apis.RegisterHandlerOnPrefix(assets.HandlerPrefix, assets.NewHandler(commands, verifier, config.InternalAuthZ, id.SonyFlakeGenerator(), store, queries, middleware.CallDurationHandler, instanceInterceptor.Handler, assetsCache.Handler, limitingAccessInterceptor.Handle))
+	apis.RegisterHandlerOnPrefix(assets.HandlerPrefix, assets.NewHandler(commands, verifier, config.InternalAuthZ, id.DefaultGenerator(), store, queries, middleware.CallDurationHandler, instanceInterceptor.Handler, assetsCache.Handler, limitingAccessInterceptor.Handle))
 
 	apis.RegisterHandlerOnPrefix(idp.HandlerPrefix, idp.NewHandler(commands, queries, keys.IDPConfig, config.ExternalSecure, instanceInterceptor.Handler))
 
-	userAgentInterceptor, err := middleware.NewUserAgentHandler(config.UserAgentCookie, keys.UserAgentCookieKey, id.SonyFlakeGenerator(), config.ExternalSecure, login.EndpointResources, login.EndpointExternalLoginCallbackFormPost, login.EndpointSAMLACS)
+	userAgentInterceptor, err := middleware.NewUserAgentHandler(config.UserAgentCookie, keys.UserAgentCookieKey, id.DefaultGenerator(), config.ExternalSecure, login.EndpointResources, login.EndpointExternalLoginCallbackFormPost, login.EndpointSAMLACS)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +37,8 @@ type API struct {
 	healthServer      *health.Server
 	accessInterceptor *http_mw.AccessInterceptor
 	queries           *query.Queries
+	// checks are validated by the readiness and validate endpoints
+	checks []ValidationFunction
 }
 
 func (a *API) ListGrpcServices() []string {
@@ -218,19 +220,29 @@ func (a *API) routeGRPCWeb() {
 		Name("grpc-web")
 }
 
+// AddReadinessCheck adds a check to the readiness and validate endpoints.
+// It must be called before the api is started.
+func (a *API) AddReadinessCheck(check ValidationFunction) {
+	a.checks = append(a.checks, check)
+}
+
 func (a *API) healthHandler() http.Handler {
-	checks := []ValidationFunction{
+	a.checks = append(a.checks,
 		func(ctx context.Context) error {
 			if err := a.health.Health(ctx); err != nil {
 				return zerrors.ThrowInternal(err, "API-F24h2", "DB CONNECTION ERROR")
 			}
 			return nil
 		},
-	}
+	)
 	handler := http.NewServeMux()
 	handler.HandleFunc("/healthz", handleHealth)
-	handler.HandleFunc("/ready", handleReadiness(checks))
-	handler.HandleFunc("/validate", handleValidate(checks))
+	handler.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		handleReadiness(a.checks)(w, r)
+	})
+	handler.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) {
+		handleValidate(a.checks)(w, r)
+	})
 	handler.Handle("/metrics", metricsExporter())
 
 	return handler
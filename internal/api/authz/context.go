@@ -160,6 +160,18 @@ func GetCtxData(ctx context.Context) CtxData {
 	return ctxData
 }
 
+// SystemEditorID is the editor of events which are not created by an authenticated user
+const SystemEditorID = "SYSTEM"
+
+// GetEditorUserID returns the id of the authenticated user
+// or [SystemEditorID] if no user is authenticated
+func GetEditorUserID(ctx context.Context) string {
+	if userID := GetCtxData(ctx).UserID; userID != "" {
+		return userID
+	}
+	return SystemEditorID
+}
+
 func GetRequestPermissionsFromCtx(ctx context.Context) []string {
 	ctxPermission, _ := ctx.Value(requestPermissionsKey).([]string)
 	return ctxPermission
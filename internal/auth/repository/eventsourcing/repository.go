@@ -76,7 +76,7 @@ func Start(ctx context.Context, conf Config, systemDefaults sd.SystemDefaults, c
 			ProjectProvider:           queryView,
 			ApplicationProvider:       queries,
 			CustomTextProvider:        queries,
-			IdGenerator:               id.SonyFlakeGenerator(),
+			IdGenerator:               id.DefaultGenerator(),
 		},
 		eventstore.TokenRepo{
 			View:       view,
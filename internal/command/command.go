@@ -103,7 +103,7 @@ func StartCommands(
 	if externalDomain == "" {
 		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Df21s", "no external domain specified")
 	}
-	idGenerator := id.SonyFlakeGenerator()
+	idGenerator := id.DefaultGenerator()
 	// reuse the oidcEncryption to be able to handle both tokens in the interceptor later on
 	sessionAlg := oidcEncryption
 	repo = &Commands{
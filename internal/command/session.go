@@ -152,7 +152,8 @@ func CheckTOTP(code string) SessionCommand {
 		if err != nil {
 			return err
 		}
-		cmd.TOTPChecked(ctx, cmd.now())
+		// a valid code proves that the user supplied the current code of the registered authenticator
+		cmd.TOTPChecked(ctx, cmd.now(), true)
 		return nil
 	}
 }
@@ -206,8 +207,8 @@ func (s *SessionCommands) WebAuthNChecked(ctx context.Context, checkedAt time.Ti
 	}
 }
 
-func (s *SessionCommands) TOTPChecked(ctx context.Context, checkedAt time.Time) {
-	s.eventCommands = append(s.eventCommands, session.NewTOTPCheckedEvent(ctx, s.sessionWriteModel.aggregate, checkedAt))
+func (s *SessionCommands) TOTPChecked(ctx context.Context, checkedAt time.Time, userVerified bool) {
+	s.eventCommands = append(s.eventCommands, session.NewTOTPCheckedEvent(ctx, s.sessionWriteModel.aggregate, checkedAt, userVerified))
 }
 
 func (s *SessionCommands) OTPSMSChallenged(ctx context.Context, code *crypto.CryptoValue, expiry time.Duration, returnCode bool) {
@@ -324,14 +325,15 @@ func (c *Commands) UpdateSession(ctx context.Context, sessionID, sessionToken st
 }
 
 func (c *Commands) TerminateSession(ctx context.Context, sessionID string, sessionToken string) (*domain.ObjectDetails, error) {
-	return c.terminateSession(ctx, sessionID, sessionToken, true)
+	return c.terminateSession(ctx, sessionID, sessionToken, true, domain.SessionTerminationReasonRequested)
 }
 
+// TerminateSessionWithoutTokenCheck terminates the session on an end session request of a relying party
 func (c *Commands) TerminateSessionWithoutTokenCheck(ctx context.Context, sessionID string) (*domain.ObjectDetails, error) {
-	return c.terminateSession(ctx, sessionID, "", false)
+	return c.terminateSession(ctx, sessionID, "", false, domain.SessionTerminationReasonLogout)
 }
 
-func (c *Commands) terminateSession(ctx context.Context, sessionID, sessionToken string, mustCheckToken bool) (*domain.ObjectDetails, error) {
+func (c *Commands) terminateSession(ctx context.Context, sessionID, sessionToken string, mustCheckToken bool, reason domain.SessionTerminationReason) (*domain.ObjectDetails, error) {
 	sessionWriteModel := NewSessionWriteModel(sessionID, authz.GetInstance(ctx).InstanceID())
 	if err := c.eventstore.FilterToQueryReducer(ctx, sessionWriteModel); err != nil {
 		return nil, err
@@ -344,7 +346,7 @@ func (c *Commands) terminateSession(ctx context.Context, sessionID, sessionToken
 	if sessionWriteModel.CheckIsActive() != nil {
 		return writeModelToObjectDetails(&sessionWriteModel.WriteModel), nil
 	}
-	terminate := session.NewTerminateEvent(ctx, &session.NewAggregate(sessionWriteModel.AggregateID, sessionWriteModel.ResourceOwner).Aggregate)
+	terminate := session.NewTerminateEvent(ctx, &session.NewAggregate(sessionWriteModel.AggregateID, sessionWriteModel.ResourceOwner).Aggregate, reason)
 	pushedEvents, err := c.eventstore.Push(ctx, terminate)
 	if err != nil {
 		return nil, err
@@ -936,7 +936,7 @@ func TestCheckTOTP(t *testing.T) {
 				),
 			},
 			wantEventCommands: []eventstore.Command{
-				session.NewTOTPCheckedEvent(ctx, sessAgg, testNow),
+				session.NewTOTPCheckedEvent(ctx, sessAgg, testNow, true),
 			},
 		},
 	}
@@ -1071,7 +1071,7 @@ func TestCommands_TerminateSession(t *testing.T) {
 							session.NewTokenSetEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate,
 								"tokenID")),
 						eventFromEventPusher(
-							session.NewTerminateEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate)),
+							session.NewTerminateEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate, domain.SessionTerminationReasonRequested)),
 					),
 				),
 				tokenVerifier: func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error) {
@@ -1111,7 +1111,7 @@ func TestCommands_TerminateSession(t *testing.T) {
 					),
 					expectPushFailed(
 						zerrors.ThrowInternal(nil, "id", "pushed failed"),
-						session.NewTerminateEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate),
+						session.NewTerminateEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate, domain.SessionTerminationReasonRequested),
 					),
 				),
 				tokenVerifier: func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error) {
@@ -1148,7 +1148,7 @@ func TestCommands_TerminateSession(t *testing.T) {
 						),
 					),
 					expectPush(
-						session.NewTerminateEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate),
+						session.NewTerminateEvent(context.Background(), &session.NewAggregate("sessionID", "instance1").Aggregate, domain.SessionTerminationReasonRequested),
 					),
 				),
 				tokenVerifier: func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error) {
@@ -1192,7 +1192,7 @@ func TestCommands_TerminateSession(t *testing.T) {
 						),
 					),
 					expectPush(
-						session.NewTerminateEvent(authz.NewMockContext("instance1", "org1", "user1"), &session.NewAggregate("sessionID", "instance1").Aggregate),
+						session.NewTerminateEvent(authz.NewMockContext("instance1", "org1", "user1"), &session.NewAggregate("sessionID", "instance1").Aggregate, domain.SessionTerminationReasonRequested),
 					),
 				),
 			},
@@ -1233,7 +1233,7 @@ func TestCommands_TerminateSession(t *testing.T) {
 						),
 					),
 					expectPush(
-						session.NewTerminateEvent(authz.NewMockContext("instance1", "org1", "admin1"), &session.NewAggregate("sessionID", "instance1").Aggregate),
+						session.NewTerminateEvent(authz.NewMockContext("instance1", "org1", "admin1"), &session.NewAggregate("sessionID", "instance1").Aggregate, domain.SessionTerminationReasonRequested),
 					),
 				),
 				checkPermission: newMockPermissionCheckAllowed(),
@@ -1279,7 +1279,7 @@ func TestCommands_TerminateSession(t *testing.T) {
 							"username", "firstname", "lastname", "nickname", "displayname", language.English, domain.GenderUnspecified, "email", false),
 					),
 					expectPush(
-						session.NewTerminateEvent(authz.NewMockContext("instance1", "org1", "admin1"), &session.NewAggregate("sessionID", "instance1").Aggregate),
+						session.NewTerminateEvent(authz.NewMockContext("instance1", "org1", "admin1"), &session.NewAggregate("sessionID", "instance1").Aggregate, domain.SessionTerminationReasonRequested),
 					),
 				),
 				checkPermission: newMockPermissionCheckAllowed(),
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/notification/channels/objectstore"
 )
 
 type SystemDefaults struct {
@@ -35,6 +36,19 @@ type DomainVerification struct {
 
 type Notifications struct {
 	FileSystemPath string
+	// EmailDryRun sends emails only to the log and file system channels, SMTP is never used
+	EmailDryRun bool
+	// ProbeSMTP adds a readiness check connecting to the smtp server of the default instance
+	ProbeSMTP bool
+	// FailedEventsRetention defines how long failed events of the notification handlers are kept.
+	// 0 disables the cleanup.
+	FailedEventsRetention time.Duration
+	// FailedEventsCleanupInterval defines how often failed events older than the retention are removed.
+	FailedEventsCleanupInterval time.Duration
+	// ObjectStore archives a copy of each email in a bucket of an S3 compatible object storage
+	ObjectStore objectstore.Config
+	// SuppressedEmails are the addresses which never receive emails by SMTP, e.g. because of hard bounces or unsubscribes
+	SuppressedEmails []string
 }
 
 type KeyConfig struct {
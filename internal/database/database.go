@@ -117,14 +117,31 @@ func Connect(config Config, useAdmin bool, purpose dialect.DBPurpose) (*DB, erro
 	if err != nil {
 		return nil, err
 	}
+	return newDB(client, config.connector)
+}
+
+// ConnectAdminToDatabase connects the admin user to the database of ZITADEL,
+// e.g. to create objects which are owned by the admin user instead of the ZITADEL user.
+func ConnectAdminToDatabase(config Config, purpose dialect.DBPurpose) (*DB, error) {
+	connector, ok := config.connector.(dialect.AdminDatabaseConnector)
+	if !ok {
+		return Connect(config, true, purpose)
+	}
+	client, err := connector.ConnectAdminToDatabase(purpose)
+	if err != nil {
+		return nil, err
+	}
+	return newDB(client, config.connector)
+}
 
+func newDB(client *sql.DB, connector dialect.Connector) (*DB, error) {
 	if err := client.Ping(); err != nil {
 		return nil, zerrors.ThrowPreconditionFailed(err, "DATAB-0pIWD", "Errors.Database.Connection.Failed")
 	}
 
 	return &DB{
 		DB:       client,
-		Database: config.connector,
+		Database: connector,
 	}, nil
 }
 
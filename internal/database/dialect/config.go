@@ -58,6 +58,12 @@ type Connector interface {
 	Database
 }
 
+// AdminDatabaseConnector is implemented by connectors whose admin user doesn't connect to the database of ZITADEL by default
+type AdminDatabaseConnector interface {
+	// ConnectAdminToDatabase connects the admin user to the database of ZITADEL
+	ConnectAdminToDatabase(purpose DBPurpose) (*sql.DB, error)
+}
+
 type Database interface {
 	DatabaseName() string
 	Username() string
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// Codes of postgres errors handled by zitadel,
+// see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	PGCodeUniqueViolation      = "23505"
+	PGCodeSerializationFailure = "40001"
+)
+
+// ClassifyPGError returns the code of the postgres error in the chain of err.
+// The errors of the pgx and the pq driver are supported.
+// ok is false if the chain doesn't contain a postgres error.
+func ClassifyPGError(err error) (code string, ok bool) {
+	var pgxErr *pgconn.PgError
+	if errors.As(err, &pgxErr) {
+		return pgxErr.Code, true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code), true
+	}
+	return "", false
+}
+
+// IsPGErrorCode returns true if the chain of err contains a postgres error with the code
+func IsPGErrorCode(err error, code string) bool {
+	errCode, ok := ClassifyPGError(err)
+	return ok && errCode == code
+}
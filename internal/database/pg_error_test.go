@@ -0,0 +1,68 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestClassifyPGError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantOK   bool
+	}{
+		{
+			name:     "pgconn error",
+			err:      &pgconn.PgError{Code: PGCodeUniqueViolation},
+			wantCode: PGCodeUniqueViolation,
+			wantOK:   true,
+		},
+		{
+			name:     "pq error",
+			err:      &pq.Error{Code: PGCodeSerializationFailure},
+			wantCode: PGCodeSerializationFailure,
+			wantOK:   true,
+		},
+		{
+			name:     "wrapped pgconn error",
+			err:      fmt.Errorf("push failed: %w", &pgconn.PgError{Code: PGCodeSerializationFailure}),
+			wantCode: PGCodeSerializationFailure,
+			wantOK:   true,
+		},
+		{
+			name:     "pq error wrapped by zerrors",
+			err:      zerrors.ThrowInternal(&pq.Error{Code: PGCodeUniqueViolation}, "TEST-Pq3rr", "Errors.Internal"),
+			wantCode: PGCodeUniqueViolation,
+			wantOK:   true,
+		},
+		{
+			name: "non pg error",
+			err:  errors.New("not a postgres error"),
+		},
+		{
+			name: "nil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := ClassifyPGError(tt.err)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestIsPGErrorCode(t *testing.T) {
+	assert.True(t, IsPGErrorCode(&pgconn.PgError{Code: PGCodeUniqueViolation}, PGCodeUniqueViolation))
+	assert.True(t, IsPGErrorCode(&pq.Error{Code: PGCodeUniqueViolation}, PGCodeUniqueViolation))
+	assert.False(t, IsPGErrorCode(&pgconn.PgError{Code: PGCodeSerializationFailure}, PGCodeUniqueViolation))
+	assert.False(t, IsPGErrorCode(errors.New(PGCodeUniqueViolation), PGCodeUniqueViolation))
+}
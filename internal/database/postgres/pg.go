@@ -75,8 +75,9 @@ func (c *Config) Connect(useAdmin bool, pusherRatio, spoolerRatio float64, purpo
 		return nil, err
 	}
 
-	connConfig, err := dialect.NewConnectionConfig(c.MaxOpenConns, c.MaxIdleConns, spoolerRatio, pusherRatio, purpose)
+	connConfig, err := dialect.NewConnectionConfig(c.MaxOpenConns, c.MaxIdleConns, pusherRatio, spoolerRatio, purpose)
 	if err != nil {
+		client.Close()
 		return nil, err
 	}
 
@@ -88,6 +89,13 @@ func (c *Config) Connect(useAdmin bool, pusherRatio, spoolerRatio float64, purpo
 	return client, nil
 }
 
+// ConnectAdminToDatabase connects the admin user to the database of ZITADEL instead of the postgres database
+func (c *Config) ConnectAdminToDatabase(purpose dialect.DBPurpose) (*sql.DB, error) {
+	admin := *c
+	admin.User = c.Admin
+	return admin.Connect(false, 0, 0, purpose)
+}
+
 func (c *Config) DatabaseName() string {
 	return c.Database
 }
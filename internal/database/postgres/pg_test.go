@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database/dialect"
+)
+
+func TestConfig_Connect_pool(t *testing.T) {
+	config := &Config{
+		Host:            "localhost",
+		Port:            5432,
+		Database:        "zitadel",
+		MaxOpenConns:    40,
+		MaxIdleConns:    20,
+		MaxConnLifetime: 30 * time.Minute,
+		MaxConnIdleTime: 5 * time.Minute,
+		User:            User{Username: "zitadel"},
+	}
+	tests := []struct {
+		name         string
+		purpose      dialect.DBPurpose
+		wantMaxConns int
+	}{
+		{
+			name:         "event pusher",
+			purpose:      dialect.DBPurposeEventPusher,
+			wantMaxConns: 8,
+		},
+		{
+			name:         "projection spooler",
+			purpose:      dialect.DBPurposeProjectionSpooler,
+			wantMaxConns: 4,
+		},
+		{
+			name:         "queries",
+			purpose:      dialect.DBPurposeQuery,
+			wantMaxConns: 28,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the connections are opened lazily, so no database is required
+			client, err := config.Connect(false, 0.2, 0.1, tt.purpose)
+			require.NoError(t, err)
+			defer client.Close()
+
+			assert.Equal(t, tt.wantMaxConns, client.Stats().MaxOpenConnections)
+		})
+	}
+}
+
+func TestConfig_Connect_invalidPool(t *testing.T) {
+	config := &Config{
+		MaxOpenConns: 2,
+	}
+	_, err := config.Connect(false, 0.2, 0.1, dialect.DBPurposeEventPusher)
+	assert.ErrorIs(t, err, dialect.ErrIllegalMaxOpenConns)
+}
@@ -31,3 +31,11 @@ func (e ExecutionType) String() string {
 	}
 	return ""
 }
+
+type ExecutionTargetType uint
+
+const (
+	ExecutionTargetTypeUnspecified ExecutionTargetType = iota
+	ExecutionTargetTypeTarget
+	ExecutionTargetTypeInclude
+)
@@ -24,6 +24,34 @@ func (o *Org) IsValid() bool {
 	return o.Name != ""
 }
 
+const (
+	// OrgNameDiacritics are the characters replaced by [OrgNameDiacriticsReplacement] when normalizing org names.
+	// Both strings must have the same amount of characters.
+	OrgNameDiacritics            = "ÀÁÂÃÄÅàáâãäåÈÉÊËèéêëÌÍÎÏìíîïÒÓÔÕÖØòóôõöøÙÚÛÜùúûüÝýÿÑñÇçŠšŽžČčĆćĐđŁłŃńŚśŹźŻżĄąĘęŘřĚěŮůŤťĎďŇň"
+	OrgNameDiacriticsReplacement = "AAAAAAaaaaaaEEEEeeeeIIIIiiiiOOOOOOooooooUUUUuuuuYyyNnCcSsZzCcCcDdLlNnSsZzZzAaEeRrEeUuTtDdNn"
+)
+
+var orgNameDiacritics = func() map[rune]rune {
+	replacements := []rune(OrgNameDiacriticsReplacement)
+	diacritics := make(map[rune]rune, len(replacements))
+	for i, diacritic := range []rune(OrgNameDiacritics) {
+		diacritics[diacritic] = replacements[i]
+	}
+	return diacritics
+}()
+
+// NormalizeOrgName returns the name used to check the uniqueness of org names.
+// Diacritics are replaced by their base letter, whitespace is collapsed to single spaces and the name is lower cased.
+func NormalizeOrgName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if replacement, ok := orgNameDiacritics[r]; ok {
+			return replacement
+		}
+		return r
+	}, name)
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
 func (o *Org) AddIAMDomain(iamDomain string) {
 	orgDomain, _ := NewIAMDomainName(o.Name, iamDomain)
 	o.Domains = append(o.Domains, &OrgDomain{Domain: orgDomain, Verified: true, Primary: true})
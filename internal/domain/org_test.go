@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOrgName(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "unchanged",
+			args: "acme inc",
+			want: "acme inc",
+		},
+		{
+			name: "case",
+			args: "ACME Inc",
+			want: "acme inc",
+		},
+		{
+			name: "whitespace",
+			args: " Acme \t Inc\n",
+			want: "acme inc",
+		},
+		{
+			name: "diacritics",
+			args: "Zürich Äpfel Crème Brûlée",
+			want: "zurich apfel creme brulee",
+		},
+		{
+			name: "other characters are kept",
+			args: "株式会社 Straße",
+			want: "株式会社 straße",
+		},
+		{
+			name: "empty",
+			args: "   ",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeOrgName(tt.args))
+		})
+	}
+}
+
+func TestOrgNameDiacritics(t *testing.T) {
+	// the generated column of the org projection translates the characters pairwise
+	assert.Equal(t, utf8.RuneCountInString(OrgNameDiacritics), utf8.RuneCountInString(OrgNameDiacriticsReplacement))
+}
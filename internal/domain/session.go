@@ -14,6 +14,18 @@ const (
 	SessionStateTerminated
 )
 
+// SessionTerminationReason describes why a session was terminated
+type SessionTerminationReason string
+
+const (
+	// SessionTerminationReasonUnspecified is the reason of sessions terminated before the reason was recorded
+	SessionTerminationReasonUnspecified SessionTerminationReason = ""
+	// SessionTerminationReasonRequested is the reason of sessions terminated using their token or with the permission to delete sessions
+	SessionTerminationReasonRequested SessionTerminationReason = "requested"
+	// SessionTerminationReasonLogout is the reason of sessions terminated by an end session request of a relying party
+	SessionTerminationReasonLogout SessionTerminationReason = "logout"
+)
+
 type OTPEmailURLData struct {
 	Code              string
 	UserID            string
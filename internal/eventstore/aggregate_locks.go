@@ -0,0 +1,120 @@
+package eventstore
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// aggregateLocks limits the concurrent push transactions per aggregate
+type aggregateLocks struct {
+	width int
+	mu    sync.Mutex
+	slots map[aggregateKey]*aggregateSlot
+}
+
+type aggregateSlot struct {
+	sem chan struct{}
+	// users counts the pushes holding or waiting for the slot,
+	// the slot is removed if it's not used anymore
+	users int
+}
+
+func newAggregateLocks(width int) *aggregateLocks {
+	return &aggregateLocks{
+		width: width,
+		slots: make(map[aggregateKey]*aggregateSlot),
+	}
+}
+
+// acquire waits until all aggregates have a free slot.
+// The slots are acquired in a fixed order, so pushes of overlapping aggregates don't deadlock.
+// The returned function releases the slots and must be called exactly once if err is nil.
+func (l *aggregateLocks) acquire(ctx context.Context, keys []aggregateKey) (release func(), err error) {
+	keys = slices.Clone(keys)
+	slices.SortFunc(keys, compareAggregateKeys)
+	keys = slices.Compact(keys)
+
+	acquired := make([]aggregateKey, 0, len(keys))
+	release = func() {
+		for _, key := range acquired {
+			l.release(key)
+		}
+	}
+	for _, key := range keys {
+		slot := l.slot(key)
+		select {
+		case slot.sem <- struct{}{}:
+			acquired = append(acquired, key)
+		case <-ctx.Done():
+			l.leave(key)
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+func (l *aggregateLocks) slot(key aggregateKey) *aggregateSlot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slot, ok := l.slots[key]
+	if !ok {
+		slot = &aggregateSlot{sem: make(chan struct{}, l.width)}
+		l.slots[key] = slot
+	}
+	slot.users++
+	return slot
+}
+
+func (l *aggregateLocks) release(key aggregateKey) {
+	l.mu.Lock()
+	<-l.slots[key].sem
+	l.mu.Unlock()
+	l.leave(key)
+}
+
+func (l *aggregateLocks) leave(key aggregateKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slot := l.slots[key]
+	slot.users--
+	if slot.users == 0 {
+		delete(l.slots, key)
+	}
+}
+
+// aggregateKey identifies an aggregate across instances
+type aggregateKey struct {
+	instanceID    string
+	aggregateType AggregateType
+	aggregateID   string
+}
+
+// pushedAggregates returns the keys of the aggregates of the commands
+func pushedAggregates(ctx context.Context, commands []Command) ([]aggregateKey, error) {
+	keys := make([]aggregateKey, len(commands))
+	for i, command := range commands {
+		instanceID, err := PushInstanceID(ctx, command)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = aggregateKey{
+			instanceID:    instanceID,
+			aggregateType: command.Aggregate().Type,
+			aggregateID:   command.Aggregate().ID,
+		}
+	}
+	return keys, nil
+}
+
+func compareAggregateKeys(a, b aggregateKey) int {
+	if c := cmp.Compare(a.instanceID, b.instanceID); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.aggregateType, b.aggregateType); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.aggregateID, b.aggregateID)
+}
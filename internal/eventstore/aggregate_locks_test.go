@@ -0,0 +1,125 @@
+package eventstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_aggregateLocks_sameAggregate(t *testing.T) {
+	for _, width := range []int{1, 2} {
+		locks := newAggregateLocks(width)
+		key := aggregateKey{instanceID: "instance", aggregateType: "user", aggregateID: "1"}
+
+		var active, maxActive atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := locks.acquire(context.Background(), []aggregateKey{key})
+				require.NoError(t, err)
+				defer release()
+
+				current := active.Add(1)
+				for {
+					max := maxActive.Load()
+					if current <= max || maxActive.CompareAndSwap(max, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				active.Add(-1)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(width), maxActive.Load(), "pushes of the same aggregate exceed width %d", width)
+		assert.Empty(t, locks.slots, "unused slots must be removed")
+	}
+}
+
+func Test_aggregateLocks_differentAggregates(t *testing.T) {
+	locks := newAggregateLocks(1)
+
+	// both pushes hold their slot until the other one acquired its slot
+	var inside sync.WaitGroup
+	inside.Add(2)
+	done := make(chan struct{})
+	for _, id := range []string{"1", "2"} {
+		go func(id string) {
+			release, err := locks.acquire(context.Background(), []aggregateKey{{instanceID: "instance", aggregateType: "user", aggregateID: id}})
+			if err != nil {
+				return
+			}
+			defer release()
+			inside.Done()
+			inside.Wait()
+			done <- struct{}{}
+		}(id)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("pushes of different aggregates did not overlap")
+		}
+	}
+}
+
+func Test_aggregateLocks_overlappingAggregates(t *testing.T) {
+	locks := newAggregateLocks(1)
+	a := aggregateKey{instanceID: "instance", aggregateType: "user", aggregateID: "a"}
+	b := aggregateKey{instanceID: "instance", aggregateType: "user", aggregateID: "b"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		keys := []aggregateKey{a, b, a}
+		if i%2 == 0 {
+			keys = []aggregateKey{b, a}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := locks.acquire(context.Background(), keys)
+			require.NoError(t, err)
+			release()
+		}()
+	}
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pushes of overlapping aggregates deadlocked")
+	}
+	assert.Empty(t, locks.slots, "unused slots must be removed")
+}
+
+func Test_aggregateLocks_cancelled(t *testing.T) {
+	locks := newAggregateLocks(1)
+	a := aggregateKey{instanceID: "instance", aggregateType: "user", aggregateID: "a"}
+	b := aggregateKey{instanceID: "instance", aggregateType: "user", aggregateID: "b"}
+
+	release, err := locks.acquire(context.Background(), []aggregateKey{b})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = locks.acquire(ctx, []aggregateKey{a, b})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the slot of a was released by the cancelled push
+	releaseA, err := locks.acquire(context.Background(), []aggregateKey{a})
+	require.NoError(t, err)
+	releaseA()
+	release()
+	assert.Empty(t, locks.slots, "unused slots must be removed")
+}
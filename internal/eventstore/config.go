@@ -4,9 +4,35 @@ import (
 	"time"
 )
 
+const (
+	// CreationDateDefault sets the creation date of the events to the default of the pusher,
+	// which is the commit timestamp of the cluster on cockroach and the start of the insert statement on postgres
+	CreationDateDefault = ""
+	// CreationDateStatement sets the creation date of the events to the start of the insert statement
+	CreationDateStatement = "statement"
+	// CreationDateTransaction sets the creation date of the events to the start of the transaction,
+	// all events pushed in the same transaction share the same creation date
+	CreationDateTransaction = "transaction"
+)
+
 type Config struct {
 	PushTimeout time.Duration
 	MaxRetries  uint32
+	// EventCreationDate defines the timestamp used as creation date of pushed events,
+	// see [CreationDateDefault], [CreationDateStatement] and [CreationDateTransaction]
+	EventCreationDate string
+	// FilterSlowQueryThreshold defines the duration after which filter queries are logged, logging is disabled if 0
+	FilterSlowQueryThreshold time.Duration
+	// PayloadCompressionThreshold defines the size in bytes above which payloads of pushed events are stored gzipped,
+	// compression is disabled if 0
+	PayloadCompressionThreshold int
+	// PushConcurrencyPerAggregate limits the concurrent push transactions per aggregate within the process,
+	// the limit is disabled if 0.
+	// Pushes wait for the limit before the pusher takes a connection of its pool,
+	// the pool of the pusher bounds the concurrent push transactions of all aggregates only if its MaxOpenConns are limited.
+	PushConcurrencyPerAggregate int
+	// MaxPushCommands limits the amount of commands pushed in a single transaction, the limit is disabled if 0
+	MaxPushCommands int
 
 	Pusher  Pusher
 	Querier Querier
@@ -98,6 +98,44 @@ func (e *BaseEvent) Unmarshal(ptr any) error {
 
 const defaultService = "zitadel"
 
+// EditorUser returns the creator of the command,
+// the authenticated user of the context if the command has no creator
+// or [authz.SystemEditorID] if the context has no user.
+func EditorUser(ctx context.Context, command Command) string {
+	if creator := command.Creator(); creator != "" {
+		return creator
+	}
+	return authz.GetEditorUserID(ctx)
+}
+
+// EditorServicer is implemented by commands and events which declare the service they originate from.
+// The service of pushed commands is not stored, only legacy events of eventstore.events provide it.
+type EditorServicer interface {
+	EditorService() string
+}
+
+// EditorService returns the service declared by the command or event.
+// The default service is returned if none is declared.
+func EditorService(v any) string {
+	if servicer, ok := v.(EditorServicer); ok && servicer.EditorService() != "" {
+		return servicer.EditorService()
+	}
+	return defaultService
+}
+
+// AggregateRequirer is implemented by commands which must only be pushed
+// if at least one event of their aggregate exists
+type AggregateRequirer interface {
+	RequiresExistingAggregate() bool
+}
+
+// RequiresExistingAggregate returns true if the command declares that its aggregate must exist.
+// Commands don't require an existing aggregate by default.
+func RequiresExistingAggregate(command Command) bool {
+	requirer, ok := command.(AggregateRequirer)
+	return ok && requirer.RequiresExistingAggregate()
+}
+
 // BaseEventFromRepo maps a stored event to a BaseEvent
 func BaseEventFromRepo(event Event) *BaseEvent {
 	return &BaseEvent{
@@ -105,7 +143,7 @@ func BaseEventFromRepo(event Event) *BaseEvent {
 		EventType: event.Type(),
 		Creation:  event.CreatedAt(),
 		Seq:       event.Sequence(),
-		Service:   defaultService,
+		Service:   EditorService(event),
 		User:      event.Creator(),
 		Data:      event.DataAsBytes(),
 		Pos:       event.Position(),
@@ -3,6 +3,7 @@ package eventstore
 import (
 	"context"
 	"errors"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 // Eventstore abstracts all functions needed to store valid events
@@ -21,6 +23,10 @@ type Eventstore struct {
 
 	pusher  Pusher
 	querier Querier
+	// aggregateLocks limits the concurrent pushes per aggregate, disabled if nil
+	aggregateLocks *aggregateLocks
+	// maxPushCommands is the maximum amount of commands pushed in a single transaction, unlimited if not positive
+	maxPushCommands int
 
 	instances         []string
 	lastInstanceQuery time.Time
@@ -53,8 +59,37 @@ func RegisterFilterEventMapper(aggregateType AggregateType, eventType EventType,
 	eventTypeMapping[eventType] = aggregateType
 }
 
+// RegisterPayloadValidator registers a function validating the marshalled payload of an event before it is pushed
+func RegisterPayloadValidator(eventType EventType, validator func(payload []byte) error) {
+	if validator == nil || eventType == "" {
+		return
+	}
+
+	if eventInterceptors == nil {
+		eventInterceptors = make(map[EventType]eventTypeInterceptors)
+	}
+
+	interceptor := eventInterceptors[eventType]
+	interceptor.payloadValidator = validator
+	eventInterceptors[eventType] = interceptor
+}
+
+// ValidatePayload validates the payload using the validator registered for the event type.
+// The payload is valid if no validator is registered.
+func ValidatePayload(eventType EventType, payload []byte) error {
+	validator := eventInterceptors[eventType].payloadValidator
+	if validator == nil {
+		return nil
+	}
+	if err := validator(payload); err != nil {
+		return zerrors.ThrowInvalidArgumentf(err, "V2-Gq0tk", "invalid payload of event %s", eventType)
+	}
+	return nil
+}
+
 type eventTypeInterceptors struct {
-	eventMapper func(Event) (Event, error)
+	eventMapper      func(Event) (Event, error)
+	payloadValidator func(payload []byte) error
 }
 
 func NewEventstore(config *Config) *Eventstore {
@@ -65,10 +100,22 @@ func NewEventstore(config *Config) *Eventstore {
 		pusher:  config.Pusher,
 		querier: config.Querier,
 
+		aggregateLocks:  newAggregatePushLimit(config.PushConcurrencyPerAggregate),
+		maxPushCommands: config.MaxPushCommands,
+
 		instancesMu: sync.Mutex{},
 	}
 }
 
+// newAggregatePushLimit returns the locks limiting the concurrent pushes per aggregate to width,
+// nil if width is not positive
+func newAggregatePushLimit(width int) *aggregateLocks {
+	if width <= 0 {
+		return nil
+	}
+	return newAggregateLocks(width)
+}
+
 // Health checks if the eventstore can properly work
 // It checks if the repository can serve load
 func (es *Eventstore) Health(ctx context.Context) error {
@@ -80,12 +127,29 @@ func (es *Eventstore) Health(ctx context.Context) error {
 
 // Push pushes the events in a single transaction
 // an event needs at least an aggregate
+// If the concurrent pushes per aggregate are limited, the push waits for a free slot of each of its aggregates
+// before the pusher is called, so waiting pushes don't occupy database connections.
+// More commands than allowed by [Config.MaxPushCommands] are rejected before the pusher is called.
 func (es *Eventstore) Push(ctx context.Context, cmds ...Command) ([]Event, error) {
+	if es.maxPushCommands > 0 && len(cmds) > es.maxPushCommands {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "V2-Mx9pc", "too many commands in a single push: %d exceeds the maximum of %d", len(cmds), es.maxPushCommands)
+	}
 	if es.PushTimeout > 0 {
 		var cancel func()
 		ctx, cancel = context.WithTimeout(ctx, es.PushTimeout)
 		defer cancel()
 	}
+	if es.aggregateLocks != nil {
+		keys, err := pushedAggregates(ctx, cmds)
+		if err != nil {
+			return nil, err
+		}
+		release, err := es.aggregateLocks.acquire(ctx, keys)
+		if err != nil {
+			return nil, zerrors.ThrowDeadlineExceeded(err, "V2-Cq7Vz", "push cancelled")
+		}
+		defer release()
+	}
 	var (
 		events []Event
 		err    error
@@ -207,14 +271,22 @@ func (es *Eventstore) LatestSequence(ctx context.Context, queryFactory *SearchQu
 	return es.querier.LatestSequence(ctx, queryFactory)
 }
 
+// Exists returns if at least one event matches the search query
+func (es *Eventstore) Exists(ctx context.Context, queryFactory *SearchQueryBuilder) (bool, error) {
+	queryFactory.ensureInstanceID(ctx)
+	queryFactory.Columns(ColumnsExists)
+	return es.querier.Exists(ctx, queryFactory)
+}
+
 // InstanceIDs returns the instance ids found by the search query
 // forceDBCall forces to query the database, the instance ids are not cached
+// the returned slice is a copy, so callers can't modify the cached instance ids
 func (es *Eventstore) InstanceIDs(ctx context.Context, maxAge time.Duration, forceDBCall bool, queryFactory *SearchQueryBuilder) ([]string, error) {
 	es.instancesMu.Lock()
 	defer es.instancesMu.Unlock()
 
 	if !forceDBCall && time.Since(es.lastInstanceQuery) <= maxAge {
-		return es.instances, nil
+		return slices.Clone(es.instances), nil
 	}
 
 	instances, err := es.querier.InstanceIDs(ctx, queryFactory)
@@ -223,7 +295,7 @@ func (es *Eventstore) InstanceIDs(ctx context.Context, maxAge time.Duration, for
 	}
 
 	if !forceDBCall {
-		es.instances = instances
+		es.instances = slices.Clone(instances)
 		es.lastInstanceQuery = time.Now()
 	}
 
@@ -253,6 +325,8 @@ type Querier interface {
 	LatestSequence(ctx context.Context, queryFactory *SearchQueryBuilder) (float64, error)
 	// InstanceIDs returns the instance ids found by the search query
 	InstanceIDs(ctx context.Context, queryFactory *SearchQueryBuilder) ([]string, error)
+	// Exists returns if at least one event matches the search query
+	Exists(ctx context.Context, queryFactory *SearchQueryBuilder) (bool, error)
 }
 
 type Pusher interface {
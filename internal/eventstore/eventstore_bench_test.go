@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/eventstore"
 )
 
@@ -14,7 +15,7 @@ import (
 var text string
 
 func Benchmark_Push_SameAggregate(b *testing.B) {
-	ctx := context.Background()
+	ctx := eventstore.WithoutInstance(context.Background())
 
 	smallPayload := struct {
 		Username  string
@@ -141,7 +142,7 @@ func Benchmark_Push_MultipleAggregate_Parallel(b *testing.B) {
 				b.StopTimer()
 				cleanupEventstore(clients[pusherKey])
 
-				ctx, cancel := context.WithCancel(context.Background())
+				ctx, cancel := context.WithCancel(eventstore.WithoutInstance(context.Background()))
 				b.StartTimer()
 
 				i := 0
@@ -160,3 +161,70 @@ func Benchmark_Push_MultipleAggregate_Parallel(b *testing.B) {
 		}
 	}
 }
+
+func Benchmark_Filter_EventType(b *testing.B) {
+	ctx := authz.WithInstanceID(context.Background(), "bench-instance")
+	client := clients["v2(inmemory)"]
+	querier := queriers["v2(inmemory)"]
+	cleanupEventstore(client)()
+
+	// only a few events of the filtered type are spread over many aggregates
+	commands := make([]eventstore.Command, 0, 10)
+	for i := 0; i < 1000; i++ {
+		eventType := eventstore.EventType("bench.other")
+		if i%100 == 0 {
+			eventType = "bench.filtered"
+		}
+		commands = append(commands, generateCommand("bench", strconv.Itoa(i), func(e *testEvent) {
+			e.EventType = eventType
+		}))
+		if len(commands) == cap(commands) {
+			if _, err := pushers["v3(inmemory)"].Push(ctx, commands...); err != nil {
+				b.Fatal(err)
+			}
+			commands = commands[:0]
+		}
+	}
+
+	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID("bench-instance").
+		AddQuery().
+		EventTypes("bench.filtered").
+		Builder()
+
+	indexes := []struct {
+		name string
+		stmt string
+	}{
+		{
+			name: "without index",
+			stmt: "DROP INDEX IF EXISTS eventstore.events2@es_instance_event_type",
+		},
+		{
+			name: "with index",
+			stmt: `CREATE INDEX IF NOT EXISTS es_instance_event_type ON eventstore.events2 (instance_id, event_type, "position" DESC)`,
+		},
+	}
+	for _, index := range indexes {
+		b.Run(index.name, func(b *testing.B) {
+			if _, err := client.Exec(index.stmt); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+
+			for n := 0; n < b.N; n++ {
+				count := 0
+				err := querier.FilterToReducer(ctx, query, func(eventstore.Event) error {
+					count++
+					return nil
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if count != 10 {
+					b.Fatalf("unexpected count of filtered events: %d", count)
+				}
+			}
+		})
+	}
+}
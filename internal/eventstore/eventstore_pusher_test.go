@@ -201,7 +201,7 @@ func TestCRDB_Push_OneAggregate(t *testing.T) {
 						return
 					}
 				}
-				if _, err := db.Push(tt.args.ctx, tt.args.commands...); (err != nil) != tt.res.wantErr {
+				if _, err := db.Push(eventstore.WithoutInstance(tt.args.ctx), tt.args.commands...); (err != nil) != tt.res.wantErr {
 					t.Errorf("CRDB.Push() error = %v, wantErr %v", err, tt.res.wantErr)
 				}
 
@@ -311,7 +311,7 @@ func TestCRDB_Push_MultipleAggregate(t *testing.T) {
 						Pusher:  pusher,
 					},
 				)
-				if _, err := db.Push(context.Background(), tt.args.commands...); (err != nil) != tt.res.wantErr {
+				if _, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.args.commands...); (err != nil) != tt.res.wantErr {
 					t.Errorf("CRDB.Push() error = %v, wantErr %v", err, tt.res.wantErr)
 				}
 
@@ -585,7 +585,7 @@ func TestCRDB_Push_ResourceOwner(t *testing.T) {
 					},
 				)
 
-				events, err := db.Push(context.Background(), tt.args.commands...)
+				events, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.args.commands...)
 				if err != nil {
 					t.Errorf("CRDB.Push() error = %v", err)
 				}
@@ -619,7 +619,7 @@ func pushAggregates(pusher eventstore.Pusher, aggregateCommands [][]eventstore.C
 		go func(events []eventstore.Command) {
 			<-ctx.Done()
 
-			_, err := pusher.Push(context.Background(), events...) //nolint:contextcheck
+			_, err := pusher.Push(eventstore.WithoutInstance(context.Background()), events...) //nolint:contextcheck
 			if err != nil {
 				errsMu.Lock()
 				errs = append(errs, err)
@@ -2,9 +2,11 @@ package eventstore_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
+	es_sql "github.com/zitadel/zitadel/internal/eventstore/repository/sql"
 )
 
 func TestCRDB_Filter(t *testing.T) {
@@ -80,7 +82,7 @@ func TestCRDB_Filter(t *testing.T) {
 				)
 
 				// setup initial data for query
-				if _, err := db.Push(context.Background(), tt.fields.existingEvents...); err != nil {
+				if _, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.fields.existingEvents...); err != nil {
 					t.Errorf("error in setup = %v", err)
 					return
 				}
@@ -163,7 +165,7 @@ func TestCRDB_LatestSequence(t *testing.T) {
 				)
 
 				// setup initial data for query
-				_, err := db.Push(context.Background(), tt.fields.existingEvents...)
+				_, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.fields.existingEvents...)
 				if err != nil {
 					t.Errorf("error in setup = %v", err)
 					return
@@ -180,3 +182,46 @@ func TestCRDB_LatestSequence(t *testing.T) {
 		}
 	}
 }
+
+func TestCRDB_ListUniqueConstraints(t *testing.T) {
+	for pusherName, pusher := range pushers {
+		t.Run(pusherName, func(t *testing.T) {
+			t.Cleanup(cleanupEventstore(clients[pusherName]))
+			querier := &es_sql.CRDB{DB: clients[pusherName]}
+			db := eventstore.NewEventstore(
+				&eventstore.Config{
+					Querier: querier,
+					Pusher:  pusher,
+				},
+			)
+			ctx := eventstore.WithoutInstance(context.Background())
+			aggregateType := eventstore.AggregateType(t.Name())
+
+			_, err := db.Push(ctx,
+				generateCommand(aggregateType, "1", generateAddUniqueConstraint("usernames", "gigi")),
+				generateCommand(aggregateType, "2", generateAddUniqueConstraint("usernames", "rocky")),
+			)
+			if err != nil {
+				t.Fatalf("Eventstore.Push() error = %v", err)
+			}
+			_, err = db.Push(ctx,
+				generateCommand(aggregateType, "2", generateRemoveUniqueConstraint("usernames", "rocky")),
+			)
+			if err != nil {
+				t.Fatalf("Eventstore.Push() error = %v", err)
+			}
+
+			constraints, err := querier.ListUniqueConstraints(ctx, "", "usernames")
+			if err != nil {
+				t.Fatalf("CRDB.ListUniqueConstraints() error = %v", err)
+			}
+			// the removed constraint is not listed
+			want := []*eventstore.UniqueConstraint{
+				{UniqueType: "usernames", UniqueField: "gigi", Action: eventstore.UniqueConstraintAdd},
+			}
+			if !reflect.DeepEqual(constraints, want) {
+				t.Errorf("CRDB.ListUniqueConstraints() = %v, want %v", constraints, want)
+			}
+		})
+	}
+}
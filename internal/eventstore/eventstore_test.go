@@ -392,6 +392,7 @@ type testQuerier struct {
 	events    []Event
 	sequence  float64
 	instances []string
+	exists    bool
 	err       error
 	t         *testing.T
 }
@@ -437,6 +438,13 @@ func (repo *testQuerier) InstanceIDs(ctx context.Context, queryFactory *SearchQu
 	return repo.instances, nil
 }
 
+func (repo *testQuerier) Exists(ctx context.Context, queryFactory *SearchQueryBuilder) (bool, error) {
+	if repo.err != nil {
+		return false, repo.err
+	}
+	return repo.exists, nil
+}
+
 func TestEventstore_Push(t *testing.T) {
 	type args struct {
 		events []Command
@@ -1474,3 +1482,111 @@ func TestEventstore_mapEvents(t *testing.T) {
 		})
 	}
 }
+
+// countingPusher counts the pushes and returns no events
+type countingPusher struct {
+	testPusher
+	pushes int
+}
+
+func (repo *countingPusher) Push(context.Context, ...Command) ([]Event, error) {
+	repo.pushes++
+	return nil, nil
+}
+
+func TestEventstore_Push_aggregatePushLimit(t *testing.T) {
+	pusher := new(countingPusher)
+	es := NewEventstore(&Config{
+		Pusher:                      pusher,
+		Querier:                     &testQuerier{},
+		PushConcurrencyPerAggregate: 1,
+	})
+
+	// another push of aggregate 1 is running
+	release, err := es.aggregateLocks.acquire(context.Background(), []aggregateKey{{
+		instanceID:    "zitadel",
+		aggregateType: "test.aggregate",
+		aggregateID:   "1",
+	}})
+	if err != nil {
+		t.Fatalf("unable to acquire slot: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = es.Push(ctx, newTestEvent("1", "", func() interface{} { return nil }, false))
+	if !zerrors.IsDeadlineExceeded(err) {
+		t.Errorf("Eventstore.Push() of the same aggregate error = %v, want deadline exceeded", err)
+	}
+	if pusher.pushes != 0 {
+		t.Errorf("pusher called %d times while the aggregate was locked", pusher.pushes)
+	}
+
+	if _, err = es.Push(context.Background(), newTestEvent("2", "", func() interface{} { return nil }, false)); err != nil {
+		t.Errorf("Eventstore.Push() of another aggregate unexpected error = %v", err)
+	}
+	if pusher.pushes != 1 {
+		t.Errorf("pusher called %d times, want 1", pusher.pushes)
+	}
+}
+
+func TestEventstore_Push_maxPushCommands(t *testing.T) {
+	pusher := new(countingPusher)
+	es := NewEventstore(&Config{
+		Pusher:          pusher,
+		Querier:         &testQuerier{},
+		MaxPushCommands: 2,
+	})
+
+	_, err := es.Push(context.Background(),
+		newTestEvent("1", "", func() interface{} { return nil }, false),
+		newTestEvent("2", "", func() interface{} { return nil }, false),
+		newTestEvent("3", "", func() interface{} { return nil }, false),
+	)
+	if !zerrors.IsErrorInvalidArgument(err) {
+		t.Errorf("Eventstore.Push() error = %v, want invalid argument", err)
+	}
+	if pusher.pushes != 0 {
+		t.Errorf("pusher called %d times for a rejected push", pusher.pushes)
+	}
+
+	if _, err = es.Push(context.Background(),
+		newTestEvent("1", "", func() interface{} { return nil }, false),
+		newTestEvent("2", "", func() interface{} { return nil }, false),
+	); err != nil {
+		t.Errorf("Eventstore.Push() unexpected error = %v", err)
+	}
+	if pusher.pushes != 1 {
+		t.Errorf("pusher called %d times, want 1", pusher.pushes)
+	}
+}
+
+func TestEventstore_InstanceIDs(t *testing.T) {
+	querier := &testQuerier{instances: []string{"instance1"}}
+	es := NewEventstore(&Config{Querier: querier})
+
+	ids, err := es.InstanceIDs(context.Background(), time.Minute, false, nil)
+	if err != nil {
+		t.Fatalf("Eventstore.InstanceIDs() unexpected error = %v", err)
+	}
+	// modifying the result must not change the cached instance ids
+	ids[0] = "modified"
+
+	querier.instances = []string{"instance1", "instance2"}
+	ids, err = es.InstanceIDs(context.Background(), time.Minute, false, nil)
+	if err != nil {
+		t.Fatalf("Eventstore.InstanceIDs() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"instance1"}) {
+		t.Errorf("Eventstore.InstanceIDs() cached = %v, want [instance1]", ids)
+	}
+
+	ids, err = es.InstanceIDs(context.Background(), time.Minute, true, nil)
+	if err != nil {
+		t.Fatalf("Eventstore.InstanceIDs() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"instance1", "instance2"}) {
+		t.Errorf("Eventstore.InstanceIDs() forced = %v, want [instance1 instance2]", ids)
+	}
+}
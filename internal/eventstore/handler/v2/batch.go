@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+// maxBatchSize limits the amount of statements coalesced into a single statement
+const maxBatchSize = 100
+
+// errBatchFailed is returned if the coalesced statement failed and was rolled back,
+// the statements must be executed one by one
+var errBatchFailed = errors.New("batch execution failed")
+
+// batchableDelete describes a delete of the rows matching the values of the key columns.
+// Consecutive batchable deletes of the same table are coalesced into a single statement,
+// which is safe because the result of deletes doesn't depend on their order.
+// Only deletes are coalesced, upserts are executed one by one:
+// a multi-row upsert fails if two of its rows hit the same key,
+// and the upserts of multi statements depend on the statements executed before them.
+type batchableDelete struct {
+	keys []Column
+	opts []execOption
+}
+
+// NewBatchableDeleteStatement deletes the rows matching the values of the key columns.
+// The statement behaves like [NewDeleteStatement] with an equality condition per key column,
+// but consecutive batchable deletes of the same table and key columns are executed as a single statement.
+// The values of the keys must be plain values.
+func NewBatchableDeleteStatement(event eventstore.Event, keys []Column, opts ...execOption) *Statement {
+	conditions := make([]Condition, len(keys))
+	for i, key := range keys {
+		conditions[i] = NewCond(key.Name, key.Value)
+	}
+	statement := NewDeleteStatement(event, conditions, opts...)
+	if len(keys) > 0 {
+		statement.batch = &batchableDelete{
+			keys: keys,
+			opts: opts,
+		}
+	}
+	return statement
+}
+
+func (b *batchableDelete) tableName(projectionName string) string {
+	config := execConfig{tableName: projectionName}
+	for _, opt := range b.opts {
+		opt(&config)
+	}
+	return config.tableName
+}
+
+// canBatch returns if the other delete can be executed in the same statement
+func (b *batchableDelete) canBatch(other *batchableDelete, projectionName string) bool {
+	if other == nil || len(b.keys) != len(other.keys) {
+		return false
+	}
+	for i, key := range b.keys {
+		if key.Name != other.keys[i].Name {
+			return false
+		}
+	}
+	return b.tableName(projectionName) == other.tableName(projectionName)
+}
+
+// batchSize returns the amount of statements at the beginning of statements
+// which can be executed as a single statement
+func batchSize(statements []*Statement, projectionName string) int {
+	if len(statements) == 0 || statements[0].batch == nil {
+		return 0
+	}
+	size := 1
+	for ; size < len(statements) && size < maxBatchSize; size++ {
+		if !statements[0].batch.canBatch(statements[size].batch, projectionName) {
+			break
+		}
+	}
+	return size
+}
+
+// batchDeleteQuery coalesces the deletes into a single statement
+func batchDeleteQuery(projectionName string, statements []*Statement) (string, []any) {
+	first := statements[0].batch
+	keyNames := make([]string, len(first.keys))
+	for i, key := range first.keys {
+		keyNames[i] = key.Name
+	}
+
+	rows := make([]string, len(statements))
+	args := make([]any, 0, len(statements)*len(first.keys))
+	for i, statement := range statements {
+		params := make([]string, len(statement.batch.keys))
+		for j, key := range statement.batch.keys {
+			args = append(args, key.Value)
+			params[j] = "$" + strconv.Itoa(len(args))
+		}
+		rows[i] = "(" + strings.Join(params, ", ") + ")"
+	}
+
+	return "DELETE FROM " + first.tableName(projectionName) +
+		" WHERE (" + strings.Join(keyNames, ", ") + ") IN (" + strings.Join(rows, ", ") + ")", args
+}
+
+// executeBatch executes the statements as a single statement.
+// If the statement fails nothing is changed and [errBatchFailed] is returned.
+func (h *Handler) executeBatch(tx *sql.Tx, statements []*Statement) (err error) {
+	stmt, args := batchDeleteQuery(h.projection.Name(), statements)
+
+	_, err = tx.Exec("SAVEPOINT exec")
+	if err != nil {
+		h.log().WithError(err).Debug("create savepoint failed")
+		return err
+	}
+	if _, err = tx.Exec(stmt, args...); err != nil {
+		h.log().WithError(err).Debug("batch execution failed")
+		if _, rollbackErr := tx.Exec("ROLLBACK TO SAVEPOINT exec"); rollbackErr != nil {
+			return rollbackErr
+		}
+		if _, releaseErr := tx.Exec("RELEASE SAVEPOINT exec"); releaseErr != nil {
+			return releaseErr
+		}
+		return errBatchFailed
+	}
+	_, err = tx.Exec("RELEASE SAVEPOINT exec")
+	return err
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database/mock"
+)
+
+func batchTestRemove(id string, opts ...execOption) *Statement {
+	return NewBatchableDeleteStatement(
+		&testEvent{aggregateType: "agg", instanceID: "instance"},
+		[]Column{
+			NewCol("instance_id", "instance"),
+			NewCol("id", id),
+		},
+		opts...,
+	)
+}
+
+func batchTestSet(id string) *Statement {
+	return NewUpdateStatement(
+		&testEvent{aggregateType: "agg", instanceID: "instance"},
+		[]Column{
+			NewCol("enabled", true),
+		},
+		[]Condition{
+			NewCond("instance_id", "instance"),
+			NewCond("id", id),
+		},
+	)
+}
+
+func TestHandler_executeStatements_batch(t *testing.T) {
+	tests := []struct {
+		name       string
+		statements []*Statement
+		mock       func(t *testing.T) *mock.SQLMock
+	}{
+		{
+			name: "consecutive removes coalesced, order preserved",
+			statements: []*Statement{
+				batchTestRemove("1"),
+				batchTestRemove("2"),
+				batchTestSet("2"),
+				batchTestRemove("2"),
+			},
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch WHERE (instance_id, id) IN (($1, $2), ($3, $4))",
+						mock.WithExecArgs("instance", "1", "instance", "2"),
+						mock.WithExecRowsAffected(2),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					// the set is executed after the removes
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("UPDATE projections.batch SET enabled = $1 WHERE (instance_id = $2) AND (id = $3)",
+						mock.WithExecArgs(true, "instance", "2"),
+						mock.WithExecRowsAffected(1),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					// the remove after the set is not coalesced with the removes before the set
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch WHERE (instance_id = $1) AND (id = $2)",
+						mock.WithExecArgs("instance", "2"),
+						mock.WithExecRowsAffected(1),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+				)
+			},
+		},
+		{
+			name: "different tables not coalesced",
+			statements: []*Statement{
+				batchTestRemove("1"),
+				batchTestRemove("1", WithTableSuffix("targets")),
+			},
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch WHERE (instance_id = $1) AND (id = $2)",
+						mock.WithExecArgs("instance", "1"),
+						mock.WithExecRowsAffected(1),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch_targets WHERE (instance_id = $1) AND (id = $2)",
+						mock.WithExecArgs("instance", "1"),
+						mock.WithExecRowsAffected(1),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+				)
+			},
+		},
+		{
+			name: "failed batch executed one by one",
+			statements: []*Statement{
+				batchTestRemove("1"),
+				batchTestRemove("2"),
+			},
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch WHERE (instance_id, id) IN (($1, $2), ($3, $4))",
+						mock.WithExecArgs("instance", "1", "instance", "2"),
+						mock.WithExecErr(sql.ErrConnDone),
+					),
+					mock.ExcpectExec("ROLLBACK TO SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch WHERE (instance_id = $1) AND (id = $2)",
+						mock.WithExecArgs("instance", "1"),
+						mock.WithExecRowsAffected(1),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("DELETE FROM projections.batch WHERE (instance_id = $1) AND (id = $2)",
+						mock.WithExecArgs("instance", "2"),
+						mock.WithExecRowsAffected(1),
+					),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT exec", mock.WithExecNoRowsAffected()),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := tt.mock(t)
+			h := &Handler{
+				projection: &projection{name: "projections.batch"},
+			}
+			tx, err := client.DB.Begin()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			lastProcessedIndex, err := h.executeStatements(context.Background(), tx, new(state), tt.statements)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if lastProcessedIndex != len(tt.statements)-1 {
+				t.Errorf("unexpected last processed index, want: %d, got: %d", len(tt.statements)-1, lastProcessedIndex)
+			}
+			client.Assert(t)
+		})
+	}
+}
+
+func Test_batchSize(t *testing.T) {
+	remove := batchTestRemove("1")
+	tests := []struct {
+		name       string
+		statements []*Statement
+		want       int
+	}{
+		{
+			name: "no statements",
+			want: 0,
+		},
+		{
+			name:       "not batchable",
+			statements: []*Statement{batchTestSet("1"), remove, remove},
+			want:       0,
+		},
+		{
+			name:       "stops at other statement",
+			statements: []*Statement{remove, remove, batchTestSet("1"), remove},
+			want:       2,
+		},
+		{
+			name: "other key columns",
+			statements: []*Statement{remove, NewBatchableDeleteStatement(
+				&testEvent{aggregateType: "agg", instanceID: "instance"},
+				[]Column{NewCol("instance_id", "instance")},
+			)},
+			want: 1,
+		},
+		{
+			name:       "limited",
+			statements: repeatStatement(remove, maxBatchSize+1),
+			want:       maxBatchSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batchSize(tt.statements, "projections.batch"); got != tt.want {
+				t.Errorf("batchSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func repeatStatement(statement *Statement, count int) []*Statement {
+	statements := make([]*Statement, count)
+	for i := range statements {
+		statements[i] = statement
+	}
+	return statements
+}
@@ -42,6 +42,8 @@ type Config struct {
 	MaxFailureCount       uint8
 
 	TriggerWithoutEvents Reduce
+	// ReportProgress is called with the progress of catch ups if set
+	ReportProgress ReportProgress
 }
 
 type Handler struct {
@@ -62,6 +64,7 @@ type Handler struct {
 	triggeredInstancesSync sync.Map
 
 	triggerWithoutEvents Reduce
+	reportProgress       ReportProgress
 }
 
 var _ migration.Migration = (*Handler)(nil)
@@ -154,6 +157,8 @@ func NewHandler(
 		aggregates[reducer.Aggregate] = eventTypes
 	}
 
+	registerReduceCounters()
+
 	handler := &Handler{
 		projection:             projection,
 		client:                 config.Client,
@@ -168,6 +173,7 @@ func NewHandler(
 		triggeredInstancesSync: sync.Map{},
 		triggerWithoutEvents:   config.TriggerWithoutEvents,
 		txDuration:             config.TransactionDuration,
+		reportProgress:         config.ReportProgress,
 	}
 
 	return handler
@@ -383,6 +389,7 @@ func (h *Handler) existingInstances(ctx context.Context) ([]string, error) {
 type triggerConfig struct {
 	awaitRunning bool
 	maxPosition  float64
+	progress     *catchUpProgress
 }
 
 type TriggerOpt func(conf *triggerConfig)
@@ -411,9 +418,13 @@ func (h *Handler) Trigger(ctx context.Context, opts ...TriggerOpt) (_ context.Co
 	}
 	defer cancel()
 
+	config.progress = h.newCatchUpProgress(authz.GetInstance(ctx).InstanceID(), config.maxPosition)
 	for i := 0; ; i++ {
 		additionalIteration, err := h.processEvents(ctx, config)
 		h.log().OnError(err).Info("process events failed")
+		if err == nil {
+			config.progress.reportChanges(ctx)
+		}
 		h.log().WithField("iteration", i).Debug("trigger iteration")
 		if !additionalIteration || err != nil {
 			return call.ResetTimestamp(ctx), err
@@ -506,6 +517,7 @@ func (h *Handler) processEvents(ctx context.Context, config *triggerConfig) (add
 	if config.maxPosition != 0 && currentState.position >= config.maxPosition {
 		return false, nil
 	}
+	config.progress.begin(currentState.position)
 
 	var statements []*Statement
 	statements, additionalIteration, err = h.generateStatements(ctx, tx, currentState)
@@ -530,6 +542,9 @@ func (h *Handler) processEvents(ctx context.Context, config *triggerConfig) (add
 	currentState.sequence = statements[lastProcessedIndex].Sequence
 	currentState.eventTimestamp = statements[lastProcessedIndex].CreationDate
 	err = h.setState(tx, currentState)
+	if err == nil {
+		config.progress.processed(currentState, lastProcessedIndex+1)
+	}
 
 	return additionalIteration, err
 }
@@ -550,7 +565,7 @@ func (h *Handler) generateStatements(ctx context.Context, tx *sql.Tx, currentSta
 	}
 	eventAmount := len(events)
 
-	statements, err := h.eventsToStatements(tx, events, currentState)
+	statements, err := h.eventsToStatements(ctx, tx, events, currentState)
 	if err != nil || len(statements) == 0 {
 		return nil, false, err
 	}
@@ -592,12 +607,24 @@ func skipPreviouslyReduced(statements []*Statement, currentState *state) int {
 func (h *Handler) executeStatements(ctx context.Context, tx *sql.Tx, currentState *state, statements []*Statement) (lastProcessedIndex int, err error) {
 	lastProcessedIndex = -1
 
-	for i, statement := range statements {
+	for i := 0; i < len(statements); i++ {
 		select {
 		case <-ctx.Done():
 			break
 		default:
-			err := h.executeStatement(ctx, tx, currentState, statement)
+			if size := batchSize(statements[i:], h.projection.Name()); size > 1 {
+				err := h.executeBatch(tx, statements[i:i+size])
+				if err == nil {
+					i += size - 1
+					lastProcessedIndex = i
+					continue
+				}
+				// execute the statements one by one to handle the failed statement
+				if !errors.Is(err, errBatchFailed) {
+					return lastProcessedIndex, err
+				}
+			}
+			err := h.executeStatement(ctx, tx, currentState, statements[i])
 			if err != nil {
 				return lastProcessedIndex, err
 			}
@@ -645,7 +672,8 @@ func (h *Handler) eventQuery(currentState *state) *eventstore.SearchQueryBuilder
 		Limit(uint64(h.bulkLimit)).
 		AllowTimeTravel().
 		OrderAsc().
-		InstanceID(currentState.instanceID)
+		InstanceID(currentState.instanceID).
+		AggregateEventTypes(h.reducedEventTypes()...)
 
 	if currentState.position > 0 {
 		// decrease position by 10 because builder.PositionAfter filters for position > and we need position >=
@@ -656,17 +684,21 @@ func (h *Handler) eventQuery(currentState *state) *eventstore.SearchQueryBuilder
 	}
 
 	for aggregateType, eventTypes := range h.eventTypes {
-		query := builder.
-			AddQuery().
-			AggregateTypes(aggregateType).
-			EventTypes(eventTypes...)
-
-		builder = query.Builder()
+		builder = builder.ForEventTypes(aggregateType, eventTypes...).Builder()
 	}
 
 	return builder
 }
 
+// reducedEventTypes returns the event types of all aggregates the projection reduces
+func (h *Handler) reducedEventTypes() []eventstore.EventType {
+	eventTypes := make([]eventstore.EventType, 0, len(h.eventTypes))
+	for _, types := range h.eventTypes {
+		eventTypes = append(eventTypes, types...)
+	}
+	return eventTypes
+}
+
 // ProjectionName returns the name of the underlying projection.
 func (h *Handler) ProjectionName() string {
 	return h.projection.Name()
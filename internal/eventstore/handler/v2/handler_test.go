@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestHandler_eventQuery_reducedEventTypes(t *testing.T) {
+	h := &Handler{
+		bulkLimit: 10,
+		eventTypes: map[eventstore.AggregateType][]eventstore.EventType{
+			"session":  {"session.added", "session.terminated"},
+			"instance": {"instance.removed"},
+		},
+	}
+
+	builder := h.eventQuery(&state{instanceID: "instance"})
+
+	assert.ElementsMatch(t,
+		[]eventstore.EventType{"session.added", "session.terminated", "instance.removed"},
+		builder.GetEventTypes(),
+	)
+}
@@ -213,21 +213,26 @@ func (h *Handler) Init(ctx context.Context) error {
 	return tx.Commit()
 }
 
+// NewTableCheck creates the table and its indices if they don't exist.
+// Nullable columns missing in an existing table are added, see [addColumnsCheck].
 func NewTableCheck(table *Table, opts ...execOption) *handler.Check {
 	config := execConfig{}
 	create := func(config execConfig) string {
 		return createTableStatement(table, config.tableName, "")
 	}
-	executes := make([]func(handler.Executer, string) (bool, error), len(table.indices)+1)
-	executes[0] = execNextIfExists(config, create, opts, true)
+	executes := make([]func(handler.Executer, string) (bool, error), len(table.indices)+2)
+	executes[0] = addColumnsCheck(&SuffixedTable{Table: *table})
+	executes[1] = execNextIfExists(config, create, opts, true)
 	for i, index := range table.indices {
-		executes[i+1] = execNextIfExists(config, createIndexCheck(index), opts, true)
+		executes[i+2] = execNextIfExists(config, createIndexCheck(index), opts, true)
 	}
 	return &handler.Check{
 		Executes: executes,
 	}
 }
 
+// NewMultiTableCheck creates the tables if they don't exist.
+// Nullable columns missing in the existing tables are added, see [addColumnsCheck].
 func NewMultiTableCheck(primaryTable *Table, secondaryTables ...*SuffixedTable) *handler.Check {
 	config := execConfig{}
 	create := func(config execConfig) string {
@@ -240,6 +245,7 @@ func NewMultiTableCheck(primaryTable *Table, secondaryTables ...*SuffixedTable)
 
 	return &handler.Check{
 		Executes: []func(handler.Executer, string) (bool, error){
+			addColumnsCheck(append([]*SuffixedTable{{Table: *primaryTable}}, secondaryTables...)...),
 			execNextIfExists(config, create, nil, true),
 		},
 	}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const tableColumnsStmt = "SELECT column_name, udt_name FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2"
+
+// columnQuerier is implemented by the transaction of [Handler.Init]
+type columnQuerier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// addColumnsCheck adds the nullable columns of the tables which are missing in the existing tables.
+// Columns whose type changed and missing columns which are not nullable can't be added,
+// the projection must be rebuilt in this case.
+// Nothing is done if the tables don't exist yet, they are created with all columns.
+// The primary table of the projection has an empty suffix.
+func addColumnsCheck(tables ...*SuffixedTable) func(handler.Executer, string) (bool, error) {
+	return func(ex handler.Executer, projectionName string) (bool, error) {
+		if projectionName == "" {
+			return false, ErrNoProjection
+		}
+		querier, ok := ex.(columnQuerier)
+		if !ok {
+			return true, nil
+		}
+		for _, table := range tables {
+			tableName := projectionName
+			if table.suffix != "" {
+				tableName += "_" + table.suffix
+			}
+			if err := addColumns(ex, querier, &table.Table, tableName); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+func addColumns(ex handler.Executer, querier columnQuerier, table *Table, tableName string) error {
+	existing, err := tableColumns(querier, tableName)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	for _, column := range table.columns {
+		existingType, ok := existing[column.Name]
+		if ok {
+			if existingType != udtName(column.Type) {
+				return zerrors.ThrowPreconditionFailedf(nil, "CRDB-Col7t", "type of column %s of %s changed from %s to %s, the projection must be rebuilt", column.Name, tableName, existingType, udtName(column.Type))
+			}
+			continue
+		}
+		if !column.nullable {
+			return zerrors.ThrowPreconditionFailedf(nil, "CRDB-Col8n", "column %s of %s is not nullable, the projection must be rebuilt", column.Name, tableName)
+		}
+		logging.WithFields("table", tableName, "column", column.Name).Info("add column to projection")
+		if _, err = ex.Exec(addColumnStatement(column, tableName)); err != nil {
+			return zerrors.ThrowInternal(err, "CRDB-Col9a", "add column failed")
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the type of the columns of the table mapped by name
+func tableColumns(querier columnQuerier, tableName string) (_ map[string]string, err error) {
+	schema, name := "public", tableName
+	if i := strings.LastIndex(tableName, "."); i >= 0 {
+		schema, name = tableName[:i], tableName[i+1:]
+	}
+	rows, err := querier.Query(tableColumnsStmt, schema, name)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "CRDB-Col0q", "query columns failed")
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil && closeErr != nil {
+			err = zerrors.ThrowInternal(closeErr, "CRDB-Col0c", "close rows failed")
+		}
+	}()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var column, columnType string
+		if err = rows.Scan(&column, &columnType); err != nil {
+			return nil, zerrors.ThrowInternal(err, "CRDB-Col0s", "scan columns failed")
+		}
+		columns[column] = columnType
+	}
+	if err = rows.Err(); err != nil {
+		return nil, zerrors.ThrowInternal(err, "CRDB-Col0r", "read columns failed")
+	}
+	return columns, nil
+}
+
+func addColumnStatement(column *InitColumn, tableName string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s", tableName, createColumnsStatement([]*InitColumn{column}, tableName))
+}
+
+// udtName returns the name of the type in information_schema.columns
+func udtName(columnType ColumnType) string {
+	switch columnType {
+	case ColumnTypeText:
+		return "text"
+	case ColumnTypeTextArray:
+		return "_text"
+	case ColumnTypeTimestamp:
+		return "timestamptz"
+	case ColumnTypeInterval:
+		return "interval"
+	case ColumnTypeEnum:
+		return "int2"
+	case ColumnTypeEnumArray:
+		return "_int2"
+	case ColumnTypeInt64:
+		return "int8"
+	case ColumnTypeBool:
+		return "bool"
+	case ColumnTypeJSONB:
+		return "jsonb"
+	case ColumnTypeBytes:
+		return "bytea"
+	default:
+		panic("unknown column type")
+	}
+}
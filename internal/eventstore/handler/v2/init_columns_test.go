@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestHandler_Init_addColumns(t *testing.T) {
+	columnsResult := func(rows ...[]driver.Value) mock.QueryOpt {
+		return mock.WithQueryResult([]string{"column_name", "udt_name"}, rows)
+	}
+	table := NewTable([]*InitColumn{
+		NewColumn("id", ColumnTypeText),
+		NewColumn("instance_id", ColumnTypeText),
+		NewColumn("last_activity_at", ColumnTypeTimestamp, Nullable()),
+	},
+		NewPrimaryKey("instance_id", "id"),
+	)
+	tests := []struct {
+		name    string
+		table   *Table
+		mock    func(t *testing.T) *mock.SQLMock
+		wantErr func(error) bool
+	}{
+		{
+			name:  "new table created",
+			table: table,
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExpectQuery(tableColumnsStmt,
+						mock.WithQueryArgs("projections", "columns"),
+						columnsResult(),
+					),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec(createTableStatement(table, "projections.columns", ""), mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExpectCommit(nil),
+				)
+			},
+		},
+		{
+			name:  "nullable column added to older table",
+			table: table,
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExpectQuery(tableColumnsStmt,
+						mock.WithQueryArgs("projections", "columns"),
+						columnsResult(
+							[]driver.Value{"id", "text"},
+							[]driver.Value{"instance_id", "text"},
+						),
+					),
+					mock.ExcpectExec("ALTER TABLE projections.columns ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMPTZ", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec(createTableStatement(table, "projections.columns", ""), mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExpectCommit(nil),
+				)
+			},
+		},
+		{
+			name:  "up to date table unchanged",
+			table: table,
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExpectQuery(tableColumnsStmt,
+						mock.WithQueryArgs("projections", "columns"),
+						columnsResult(
+							[]driver.Value{"id", "text"},
+							[]driver.Value{"instance_id", "text"},
+							[]driver.Value{"last_activity_at", "timestamptz"},
+						),
+					),
+					mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec(createTableStatement(table, "projections.columns", ""), mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+					mock.ExpectCommit(nil),
+				)
+			},
+		},
+		{
+			name:  "changed type requires rebuild",
+			table: table,
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExpectQuery(tableColumnsStmt,
+						mock.WithQueryArgs("projections", "columns"),
+						columnsResult(
+							[]driver.Value{"id", "int8"},
+							[]driver.Value{"instance_id", "text"},
+						),
+					),
+				)
+			},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+		{
+			name: "not nullable column requires rebuild",
+			table: NewTable([]*InitColumn{
+				NewColumn("id", ColumnTypeText),
+				NewColumn("instance_id", ColumnTypeText),
+				NewColumn("state", ColumnTypeEnum),
+			},
+				NewPrimaryKey("instance_id", "id"),
+			),
+			mock: func(t *testing.T) *mock.SQLMock {
+				return mock.NewSQLMock(t,
+					mock.ExpectBegin(nil),
+					mock.ExpectQuery(tableColumnsStmt,
+						mock.WithQueryArgs("projections", "columns"),
+						columnsResult(
+							[]driver.Value{"id", "text"},
+							[]driver.Value{"instance_id", "text"},
+						),
+					),
+				)
+			},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := tt.mock(t)
+			h := &Handler{
+				client:     &database.DB{DB: client.DB},
+				projection: &initProjection{projection: projection{name: "projections.columns"}, table: tt.table},
+			}
+
+			err := h.Init(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("unexpected error, got: %v", err)
+			}
+			client.Assert(t)
+		})
+	}
+}
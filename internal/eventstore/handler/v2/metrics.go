@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zitadel/logging"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+)
+
+const (
+	ReducedEventsCounter            = "projection_events_reduced"
+	ReducedEventsCounterDescription = "Events reduced by the projections"
+	FailedReducesCounter            = "projection_events_reduce_failed"
+	FailedReducesCounterDescription = "Events the projections failed to reduce"
+)
+
+var registerReduceCountersOnce sync.Once
+
+func registerReduceCounters() {
+	registerReduceCountersOnce.Do(func() {
+		err := metrics.RegisterCounter(ReducedEventsCounter, ReducedEventsCounterDescription)
+		logging.WithFields("metric", ReducedEventsCounter).OnError(err).Panic("unable to register counter")
+		err = metrics.RegisterCounter(FailedReducesCounter, FailedReducesCounterDescription)
+		logging.WithFields("metric", FailedReducesCounter).OnError(err).Panic("unable to register counter")
+	})
+}
+
+// countReduce increments the counter of the reduced event type.
+// It must only be called for events of the reducers of the projection,
+// so the labels are bounded by the registered aggregate and event types.
+func countReduce(ctx context.Context, projectionName string, event eventstore.Event, err error) {
+	metricName := ReducedEventsCounter
+	if err != nil {
+		metricName = FailedReducesCounter
+	}
+	labels := map[string]attribute.Value{
+		"projection":     attribute.StringValue(projectionName),
+		"aggregate_type": attribute.StringValue(string(event.Aggregate().Type)),
+		"event_type":     attribute.StringValue(string(event.Type())),
+	}
+	addCountErr := metrics.AddCount(ctx, metricName, 1, labels)
+	logging.WithFields("name", metricName, "labels", labels).OnError(addCountErr).Error("incrementing counter metric failed")
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+)
+
+type countKey struct {
+	name, projection, aggregateType, eventType string
+}
+
+type testMetrics struct {
+	metrics.Metrics
+	counts map[countKey]int64
+}
+
+func (m *testMetrics) AddCount(_ context.Context, name string, value int64, labels map[string]attribute.Value) error {
+	m.counts[countKey{
+		name:          name,
+		projection:    labels["projection"].AsString(),
+		aggregateType: labels["aggregate_type"].AsString(),
+		eventType:     labels["event_type"].AsString(),
+	}] += value
+	return nil
+}
+
+func (m *testMetrics) RegisterCounter(string, string) error { return nil }
+
+func TestHandler_reduce_metrics(t *testing.T) {
+	m := &testMetrics{counts: make(map[countKey]int64)}
+	previous := metrics.M
+	metrics.M = m
+	defer func() { metrics.M = previous }()
+
+	noOp := func(event eventstore.Event) (*Statement, error) {
+		return NewNoOpStatement(event), nil
+	}
+	h := &Handler{
+		projection: &projection{
+			name: "projections.metrics",
+			reducers: []AggregateReducer{
+				{
+					Aggregate: "session",
+					EventReducers: []EventReducer{
+						{Event: "session.added", Reduce: noOp},
+						{Event: "session.failed", Reduce: func(eventstore.Event) (*Statement, error) {
+							return nil, errors.New("reduce failed")
+						}},
+					},
+				},
+				{
+					Aggregate: "execution",
+					EventReducers: []EventReducer{
+						{Event: "execution.set", Reduce: noOp},
+					},
+				},
+			},
+		},
+	}
+	event := func(aggregateType eventstore.AggregateType, eventType eventstore.EventType) eventstore.Event {
+		return &testEvent{
+			BaseEvent:     eventstore.BaseEvent{EventType: eventType},
+			aggregateType: aggregateType,
+		}
+	}
+	events := []eventstore.Event{
+		event("session", "session.added"),
+		event("session", "session.added"),
+		event("execution", "execution.set"),
+		event("session", "session.failed"),
+		// events without reducer are not counted
+		event("session", "session.unknown"),
+		event("user", "user.added"),
+	}
+	for _, e := range events {
+		_, _ = h.reduce(context.Background(), e)
+	}
+
+	assert.Equal(t, map[countKey]int64{
+		{ReducedEventsCounter, "projections.metrics", "session", "session.added"}:   2,
+		{ReducedEventsCounter, "projections.metrics", "execution", "execution.set"}: 1,
+		{FailedReducesCounter, "projections.metrics", "session", "session.failed"}:  1,
+	}, m.counts)
+}
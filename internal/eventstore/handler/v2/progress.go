@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"time"
+)
+
+// Progress describes how far a projection caught up the events of an instance
+type Progress struct {
+	Projection string
+	InstanceID string
+	// ProcessedEvents is the amount of events processed since the catch up started
+	ProcessedEvents uint64
+	// Position is the position of the last processed event
+	Position float64
+	// Sequence is the sequence of the last processed event in its aggregate
+	Sequence uint64
+	// EstimatedRemaining is the estimated duration until the catch up is done.
+	// It is only estimated if the catch up is limited to a max position, e.g. during prefilling,
+	// otherwise it is 0.
+	EstimatedRemaining time.Duration
+}
+
+// ReportProgress is called after each iteration of a catch up which processed events.
+// It is called synchronously by the handler and must therefore return quickly.
+type ReportProgress func(ctx context.Context, progress Progress)
+
+// catchUpProgress tracks the progress of a single [Handler.Trigger].
+// All methods are no-ops on a nil catchUpProgress, which is used if progress is not reported.
+type catchUpProgress struct {
+	report      ReportProgress
+	now         nowFunc
+	maxPosition float64
+
+	startedAt     time.Time
+	startPosition float64
+	changed       bool
+	progress      Progress
+}
+
+func (h *Handler) newCatchUpProgress(instanceID string, maxPosition float64) *catchUpProgress {
+	if h.reportProgress == nil {
+		return nil
+	}
+	return &catchUpProgress{
+		report:      h.reportProgress,
+		now:         h.now,
+		maxPosition: maxPosition,
+		progress: Progress{
+			Projection: h.ProjectionName(),
+			InstanceID: instanceID,
+		},
+	}
+}
+
+// begin sets the position from which the catch up started, only the first call has an effect
+func (p *catchUpProgress) begin(position float64) {
+	if p == nil || !p.startedAt.IsZero() {
+		return
+	}
+	p.startedAt = p.now()
+	p.startPosition = position
+}
+
+// processed adds the events processed by an iteration, the state must be the state after the iteration
+func (p *catchUpProgress) processed(currentState *state, events int) {
+	if p == nil || events <= 0 {
+		return
+	}
+	p.progress.ProcessedEvents += uint64(events)
+	p.progress.Position = currentState.position
+	p.progress.Sequence = currentState.sequence
+	p.changed = true
+}
+
+// reportChanges calls the callback if events were processed since the last report.
+// It must only be called after the iteration was committed.
+func (p *catchUpProgress) reportChanges(ctx context.Context) {
+	if p == nil || !p.changed {
+		return
+	}
+	p.changed = false
+	p.progress.EstimatedRemaining = p.estimateRemaining()
+	p.report(ctx, p.progress)
+}
+
+// estimateRemaining extrapolates the elapsed time by the share of positions left until the max position
+func (p *catchUpProgress) estimateRemaining() time.Duration {
+	if p.maxPosition <= p.startPosition || p.progress.Position <= p.startPosition || p.progress.Position >= p.maxPosition {
+		return 0
+	}
+	done := (p.progress.Position - p.startPosition) / (p.maxPosition - p.startPosition)
+	elapsed := p.now().Sub(p.startedAt)
+	return time.Duration(float64(elapsed) * (1 - done) / done)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressRun simulates the iterations of a catch up of the handler,
+// each iteration processes the given amount of events and advances the clock by a second
+func progressRun(h *Handler, clock *time.Time, startPosition, maxPosition float64, iterations []int) {
+	progress := h.newCatchUpProgress("instance", maxPosition)
+	position := startPosition
+	var sequence uint64
+	for _, events := range iterations {
+		progress.begin(startPosition)
+		*clock = clock.Add(time.Second)
+		position += float64(events)
+		sequence += uint64(events)
+		progress.processed(&state{instanceID: "instance", position: position, sequence: sequence}, events)
+		progress.reportChanges(context.Background())
+	}
+}
+
+func TestHandler_reportProgress(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxPosition   float64
+		iterations    []int
+		wantProcessed []uint64
+		wantRemaining []time.Duration
+	}{
+		{
+			name:          "prefill estimates remaining",
+			maxPosition:   40,
+			iterations:    []int{10, 10, 10, 10},
+			wantProcessed: []uint64{10, 20, 30, 40},
+			wantRemaining: []time.Duration{3 * time.Second, 2 * time.Second, time.Second, 0},
+		},
+		{
+			name:          "iterations without events not reported",
+			maxPosition:   40,
+			iterations:    []int{10, 0, 30},
+			wantProcessed: []uint64{10, 40},
+			wantRemaining: []time.Duration{3 * time.Second, 0},
+		},
+		{
+			name:          "trigger without max position",
+			iterations:    []int{5, 5},
+			wantProcessed: []uint64{5, 10},
+			wantRemaining: []time.Duration{0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := time.Now()
+			var reported []Progress
+			h := &Handler{
+				projection: &projection{name: "projections.progress"},
+				now:        func() time.Time { return clock },
+				reportProgress: func(_ context.Context, progress Progress) {
+					reported = append(reported, progress)
+				},
+			}
+
+			progressRun(h, &clock, 0, tt.maxPosition, tt.iterations)
+
+			require.Len(t, reported, len(tt.wantProcessed))
+			for i, progress := range reported {
+				assert.Equal(t, "projections.progress", progress.Projection)
+				assert.Equal(t, "instance", progress.InstanceID)
+				assert.Equal(t, tt.wantProcessed[i], progress.ProcessedEvents)
+				assert.Equal(t, tt.wantRemaining[i], progress.EstimatedRemaining)
+				if i == 0 {
+					continue
+				}
+				assert.Greater(t, progress.ProcessedEvents, reported[i-1].ProcessedEvents)
+				assert.Greater(t, progress.Position, reported[i-1].Position)
+				assert.Greater(t, progress.Sequence, reported[i-1].Sequence)
+				assert.LessOrEqual(t, progress.EstimatedRemaining, reported[i-1].EstimatedRemaining)
+			}
+		})
+	}
+}
+
+func TestHandler_reportProgress_disabled(t *testing.T) {
+	h := &Handler{
+		projection: &projection{name: "projections.progress"},
+		now:        time.Now,
+	}
+	progress := h.newCatchUpProgress("instance", 0)
+	assert.Nil(t, progress)
+	// a nil progress must be usable by the handler
+	progress.begin(0)
+	progress.processed(&state{position: 1}, 1)
+	progress.reportChanges(context.Background())
+}
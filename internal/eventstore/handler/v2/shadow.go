@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+//go:embed shadow_lagging.sql
+var shadowLaggingStmt string
+
+const (
+	activeTableQuery = "SELECT active_table FROM projections.active_tables WHERE projection_name = $1"
+	activeTableStmt  = "INSERT INTO projections.active_tables (projection_name, active_table, change_date) VALUES ($1, $2, now())" +
+		" ON CONFLICT (projection_name) DO UPDATE SET active_table = EXCLUDED.active_table, change_date = EXCLUDED.change_date"
+)
+
+// ActiveTable is the table of a projection which is read by the queries.
+// The table can be replaced by a shadow table without downtime, see [Handler.Shadow] and [Handler.Swap].
+type ActiveTable struct {
+	// projection is the name of the table the projection was created with, it identifies the persisted active table
+	projection string
+	name       atomic.Pointer[string]
+}
+
+func NewActiveTable(name string) *ActiveTable {
+	table := &ActiveTable{projection: name}
+	table.name.Store(&name)
+	return table
+}
+
+// Name returns the name of the currently active table
+func (t *ActiveTable) Name() string {
+	return *t.name.Load()
+}
+
+// Load activates the table persisted by [Handler.Swap],
+// the active table is unchanged if the projection was never swapped.
+func (t *ActiveTable) Load(ctx context.Context, client *database.DB) error {
+	var table string
+	err := client.QueryRowContext(ctx, func(row *sql.Row) error {
+		return row.Scan(&table)
+	}, activeTableQuery, t.projection)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return zerrors.ThrowInternal(err, "V2-Sh6dw", "unable to load active table")
+	}
+	t.name.Store(&table)
+	return nil
+}
+
+// shadowProjection reduces the events of the projection into another table
+type shadowProjection struct {
+	Projection
+	table string
+}
+
+func (p *shadowProjection) Name() string {
+	return p.table
+}
+
+func (p *shadowProjection) Init() *handler.Check {
+	if init, ok := p.Projection.(initializer); ok {
+		return init.Init()
+	}
+	return new(handler.Check)
+}
+
+// Shadow returns a handler which builds the projection of h into table.
+// The shadow handler keeps its own state, so the table is built from the beginning
+// and the table of h is not touched.
+func (h *Handler) Shadow(table string) *Handler {
+	return &Handler{
+		projection:             &shadowProjection{Projection: h.projection, table: table},
+		client:                 h.client,
+		es:                     h.es,
+		bulkLimit:              h.bulkLimit,
+		eventTypes:             h.eventTypes,
+		requeueEvery:           h.requeueEvery,
+		handleActiveInstances:  h.handleActiveInstances,
+		now:                    h.now,
+		maxFailureCount:        h.maxFailureCount,
+		retryFailedAfter:       h.retryFailedAfter,
+		triggeredInstancesSync: sync.Map{},
+		triggerWithoutEvents:   h.triggerWithoutEvents,
+		reportProgress:         h.reportProgress,
+		txDuration:             h.txDuration,
+	}
+}
+
+// Swap replaces the active table by the table of the shadow handler.
+// The shadow table must have caught up with the active table for all instances,
+// otherwise an error is returned and the active table stays unchanged.
+// Projections which take their name from the active table continue on the shadow table and its state.
+// The active table is persisted, so it is used after a restart as well, see [ActiveTable.Load].
+// The previous table is not dropped.
+func (h *Handler) Swap(ctx context.Context, shadow *Handler, active *ActiveTable) error {
+	lagging, err := h.laggingInstances(ctx, active.Name(), shadow.ProjectionName())
+	if err != nil {
+		return err
+	}
+	if len(lagging) > 0 {
+		h.log().WithField("shadow", shadow.ProjectionName()).WithField("instances", lagging).Info("shadow projection not caught up")
+		return zerrors.ThrowPreconditionFailed(nil, "V2-Sh4dw", "shadow projection not caught up")
+	}
+	table := shadow.ProjectionName()
+	if _, err = h.client.ExecContext(ctx, activeTableStmt, active.projection, table); err != nil {
+		return zerrors.ThrowInternal(err, "V2-Sh7dw", "unable to persist active table")
+	}
+	active.name.Store(&table)
+	return nil
+}
+
+// laggingInstances returns the instances for which the shadow table has not reached the position of the active table
+func (h *Handler) laggingInstances(ctx context.Context, activeTable, shadowTable string) (instances []string, err error) {
+	err = h.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			var instance string
+			if err := rows.Scan(&instance); err != nil {
+				return err
+			}
+			instances = append(instances, instance)
+		}
+		return rows.Err()
+	}, shadowLaggingStmt, activeTable, shadowTable)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V2-Sh5dw", "unable to compare states")
+	}
+	return instances, nil
+}
@@ -0,0 +1,11 @@
+SELECT
+    a.instance_id
+FROM
+    projections.current_states a
+LEFT JOIN
+    projections.current_states s
+    ON s.instance_id = a.instance_id
+    AND s.projection_name = $2
+WHERE
+    a.projection_name = $1
+    AND (s."position" IS NULL OR s."position" < a."position");
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+type initProjection struct {
+	projection
+	table *Table
+}
+
+func (p *initProjection) Init() *handler.Check {
+	return NewTableCheck(p.table)
+}
+
+func TestHandler_Shadow(t *testing.T) {
+	table := NewTable([]*InitColumn{
+		NewColumn("id", ColumnTypeText),
+		NewColumn("instance_id", ColumnTypeText),
+	},
+		NewPrimaryKey("instance_id", "id"),
+	)
+	mock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExpectQuery(tableColumnsStmt,
+			mock.WithQueryArgs("projections", "shadow"),
+			mock.WithQueryResult([]string{"column_name", "udt_name"}, nil),
+		),
+		mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+		mock.ExcpectExec(createTableStatement(table, "projections.shadow", ""), mock.WithExecNoRowsAffected()),
+		mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+		mock.ExpectCommit(nil),
+	)
+	active := &Handler{
+		client:     &database.DB{DB: mock.DB},
+		projection: &initProjection{projection: projection{name: "projections.active"}, table: table},
+	}
+
+	shadow := active.Shadow("projections.shadow")
+	if name := shadow.ProjectionName(); name != "projections.shadow" {
+		t.Errorf("unexpected shadow name, want: projections.shadow, got: %s", name)
+	}
+	if name := active.ProjectionName(); name != "projections.active" {
+		t.Errorf("unexpected active name, want: projections.active, got: %s", name)
+	}
+	// the table is created with the name of the shadow
+	if err := shadow.Init(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	mock.Assert(t)
+}
+
+func TestHandler_Swap(t *testing.T) {
+	tests := []struct {
+		name       string
+		lagging    [][]driver.Value
+		wantErr    func(error) bool
+		wantActive string
+	}{
+		{
+			name:       "shadow lagging",
+			lagging:    [][]driver.Value{{"instance"}},
+			wantErr:    zerrors.IsPreconditionFailed,
+			wantActive: "projections.active",
+		},
+		{
+			name:       "shadow caught up",
+			lagging:    nil,
+			wantActive: "projections.shadow",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			persisted := mock.ExcpectExec(activeTableStmt,
+				mock.WithExecArgs("projections.active", "projections.shadow"),
+				mock.WithExecRowsAffected(1),
+			)
+			if tt.wantErr != nil {
+				// the active table is not persisted if the shadow is lagging
+				persisted = func(sqlmock.Sqlmock) {}
+			}
+			mock := mock.NewSQLMock(t,
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(shadowLaggingStmt,
+					mock.WithQueryArgs("projections.active", "projections.shadow"),
+					mock.WithQueryResult([]string{"instance_id"}, tt.lagging),
+				),
+				mock.ExpectCommit(nil),
+				persisted,
+			)
+			active := NewActiveTable("projections.active")
+			h := &Handler{
+				client:     &database.DB{DB: mock.DB},
+				projection: &projection{name: "projections.active"},
+			}
+			shadow := h.Shadow("projections.shadow")
+
+			err := h.Swap(context.Background(), shadow, active)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if name := active.Name(); name != tt.wantActive {
+				t.Errorf("unexpected active table, want: %s, got: %s", tt.wantActive, name)
+			}
+			mock.Assert(t)
+		})
+	}
+}
+
+func TestActiveTable_Load(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      func(sqlmock.Sqlmock)
+		end        func(sqlmock.Sqlmock)
+		wantErr    func(error) bool
+		wantActive string
+	}{
+		{
+			name: "never swapped",
+			query: mock.ExpectQuery(activeTableQuery,
+				mock.WithQueryArgs("projections.active"),
+				mock.WithQueryResult([]string{"active_table"}, nil),
+			),
+			end:        mock.ExpectRollback(nil),
+			wantActive: "projections.active",
+		},
+		{
+			name: "swapped",
+			query: mock.ExpectQuery(activeTableQuery,
+				mock.WithQueryArgs("projections.active"),
+				mock.WithQueryResult([]string{"active_table"}, [][]driver.Value{{"projections.shadow"}}),
+			),
+			end:        mock.ExpectCommit(nil),
+			wantActive: "projections.shadow",
+		},
+		{
+			name: "query fails",
+			query: mock.ExpectQuery(activeTableQuery,
+				mock.WithQueryArgs("projections.active"),
+				mock.WithQueryErr(sql.ErrConnDone),
+			),
+			end:        mock.ExpectRollback(nil),
+			wantErr:    zerrors.IsInternal,
+			wantActive: "projections.active",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mock.NewSQLMock(t, mock.ExpectBegin(nil), tt.query, tt.end)
+			active := NewActiveTable("projections.active")
+
+			err := active.Load(context.Background(), &database.DB{DB: mock.DB})
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if name := active.Name(); name != tt.wantActive {
+				t.Errorf("unexpected active table, want: %s, got: %s", tt.wantActive, name)
+			}
+			mock.Assert(t)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -37,13 +38,13 @@ func (s *executionError) Unwrap() error {
 	return s.parent
 }
 
-func (h *Handler) eventsToStatements(tx *sql.Tx, events []eventstore.Event, currentState *state) (statements []*Statement, err error) {
+func (h *Handler) eventsToStatements(ctx context.Context, tx *sql.Tx, events []eventstore.Event, currentState *state) (statements []*Statement, err error) {
 	statements = make([]*Statement, 0, len(events))
 
 	previousPosition := currentState.position
 	offset := currentState.offset
 	for _, event := range events {
-		statement, err := h.reduce(event)
+		statement, err := h.reduce(ctx, event)
 		if err != nil {
 			h.logEvent(event).WithError(err).Error("reduce failed")
 			if shouldContinue := h.handleFailedStmt(tx, failureFromEvent(event, err)); shouldContinue {
@@ -64,7 +65,7 @@ func (h *Handler) eventsToStatements(tx *sql.Tx, events []eventstore.Event, curr
 	return statements, nil
 }
 
-func (h *Handler) reduce(event eventstore.Event) (*Statement, error) {
+func (h *Handler) reduce(ctx context.Context, event eventstore.Event) (*Statement, error) {
 	for _, reducer := range h.projection.Reducers() {
 		if reducer.Aggregate != event.Aggregate().Type {
 			continue
@@ -73,7 +74,9 @@ func (h *Handler) reduce(event eventstore.Event) (*Statement, error) {
 			if reduce.Event != event.Type() {
 				continue
 			}
-			return reduce.Reduce(event)
+			statement, err := reduce.Reduce(event)
+			countReduce(ctx, h.ProjectionName(), event, err)
+			return statement, err
 		}
 	}
 	return NewNoOpStatement(event), nil
@@ -88,6 +91,8 @@ type Statement struct {
 	InstanceID    string
 
 	offset uint32
+	// batch is set if the statement can be coalesced with following statements
+	batch *batchableDelete
 
 	Execute Exec
 }
@@ -556,6 +561,13 @@ func NewTextArrayContainsCond(column string, value string) Condition {
 	}
 }
 
+// NewOneOfTextCond returns a Condition that checks if the column equals one of the given values
+func NewOneOfTextCond(column string, values []string) Condition {
+	return func(param string) (string, []any) {
+		return column + " = ANY(" + param + ")", []any{database.TextArray[string](values)}
+	}
+}
+
 // Not is a function and not a method, so that calling it is well readable
 // For example conditions := []Condition{ Not(NewTextArrayContainsCond())}
 func Not(condition Condition) Condition {
@@ -88,7 +88,7 @@ func initDB(db *database.DB) error {
 	err := initialise.Init(db,
 		initialise.VerifyUser(config.Username(), ""),
 		initialise.VerifyDatabase(config.DatabaseName()),
-		initialise.VerifyGrant(config.DatabaseName(), config.Username()))
+		initialise.VerifyGrant(config.DatabaseName(), config.Username(), initialise.GrantPrivilegesAll))
 	if err != nil {
 		return err
 	}
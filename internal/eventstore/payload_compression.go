@@ -0,0 +1,60 @@
+package eventstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// compressedPayloadKey marks payloads stored gzipped.
+// The compressed payload is wrapped in a json object so it still fits into the jsonb column,
+// payloads without the marker are read as is.
+const compressedPayloadKey = "zitadel.gzip"
+
+var compressedPayloadPrefix = []byte(`{"` + compressedPayloadKey + `"`)
+
+type compressedPayload struct {
+	Gzip []byte `json:"zitadel.gzip"`
+}
+
+// CompressPayload gzips the payload if it is larger than threshold bytes.
+// The payload is returned unchanged if threshold is not positive.
+// The database only sees the wrapper object of a compressed payload,
+// so [SearchQuery.EventData] and [SearchQuery.EventPayloadContains] don't match it.
+func CompressPayload(payload []byte, threshold int) ([]byte, error) {
+	if threshold <= 0 || len(payload) <= threshold {
+		return payload, nil
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&compressedPayload{Gzip: buf.Bytes()})
+}
+
+// IsCompressedPayload returns true if data was compressed by [CompressPayload]
+func IsCompressedPayload(data []byte) bool {
+	return bytes.HasPrefix(data, compressedPayloadPrefix)
+}
+
+// DecompressPayload returns the original payload of data stored by [CompressPayload].
+func DecompressPayload(data []byte) ([]byte, error) {
+	if !IsCompressedPayload(data) {
+		return data, nil
+	}
+	compressed := new(compressedPayload)
+	if err := json.Unmarshal(data, compressed); err != nil || len(compressed.Gzip) == 0 {
+		return data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed.Gzip))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
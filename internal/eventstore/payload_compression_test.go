@@ -0,0 +1,106 @@
+package eventstore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompressPayload(t *testing.T) {
+	large := []byte(`{"attributes":"` + strings.Repeat("a", 100) + `"}`)
+	tests := []struct {
+		name           string
+		payload        []byte
+		threshold      int
+		wantCompressed bool
+	}{
+		{
+			name:      "disabled",
+			payload:   large,
+			threshold: 0,
+		},
+		{
+			name:      "below threshold",
+			payload:   large,
+			threshold: len(large) + 1,
+		},
+		{
+			name:      "equal to threshold",
+			payload:   large,
+			threshold: len(large),
+		},
+		{
+			name:           "above threshold",
+			payload:        large,
+			threshold:      len(large) - 1,
+			wantCompressed: true,
+		},
+		{
+			name:      "empty payload",
+			payload:   nil,
+			threshold: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := CompressPayload(tt.payload, tt.threshold)
+			if err != nil {
+				t.Fatalf("CompressPayload() unexpected error = %v", err)
+			}
+			if compressed := IsCompressedPayload(stored); compressed != tt.wantCompressed {
+				t.Errorf("CompressPayload() compressed = %v, want %v", compressed, tt.wantCompressed)
+			}
+			if !tt.wantCompressed && !bytes.Equal(stored, tt.payload) {
+				t.Errorf("CompressPayload() = %s, want unchanged %s", stored, tt.payload)
+			}
+			payload, err := DecompressPayload(stored)
+			if err != nil {
+				t.Fatalf("DecompressPayload() unexpected error = %v", err)
+			}
+			if !bytes.Equal(payload, tt.payload) {
+				t.Errorf("DecompressPayload() = %s, want %s", payload, tt.payload)
+			}
+		})
+	}
+}
+
+func TestDecompressPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "uncompressed event",
+			data: []byte(`{"userName":"gigi"}`),
+			want: []byte(`{"userName":"gigi"}`),
+		},
+		{
+			name: "marker as string value",
+			data: []byte(`{"name":"zitadel.gzip"}`),
+			want: []byte(`{"name":"zitadel.gzip"}`),
+		},
+		{
+			name: "no payload",
+			data: nil,
+			want: nil,
+		},
+		{
+			name:    "invalid gzip",
+			data:    []byte(`{"zitadel.gzip": "aW52YWxpZA=="}`),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecompressPayload(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecompressPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("DecompressPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+type withoutInstanceKey struct{}
+
+// WithoutInstance returns a context in which commands are pushed without instance
+// if neither the command nor the context define one.
+// It must only be used for events which don't belong to an instance, e.g. the events of the setup steps.
+func WithoutInstance(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutInstanceKey{}, true)
+}
+
+// IsWithoutInstance returns true if the context was created by [WithoutInstance]
+func IsWithoutInstance(ctx context.Context) bool {
+	withoutInstance, _ := ctx.Value(withoutInstanceKey{}).(bool)
+	return withoutInstance
+}
+
+// PushInstanceID returns the instance to which the command is pushed,
+// which is the instance of its aggregate or the instance of the context if the aggregate has none.
+// An invalid argument error is returned if neither defines an instance,
+// because such events are not visible to instance scoped projections.
+// Commands are pushed without instance if the context was created by [WithoutInstance].
+func PushInstanceID(ctx context.Context, command Command) (string, error) {
+	if instanceID := command.Aggregate().InstanceID; instanceID != "" {
+		return instanceID, nil
+	}
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	if instanceID == "" && !IsWithoutInstance(ctx) {
+		return "", zerrors.ThrowInvalidArgument(nil, "EVENT-Ins0n", "no instance in context")
+	}
+	return instanceID, nil
+}
@@ -0,0 +1,70 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestPushInstanceID(t *testing.T) {
+	tests := []struct {
+		name                string
+		ctx                 context.Context
+		aggregateInstanceID string
+		want                string
+		wantErr             func(error) bool
+	}{
+		{
+			name:                "instance of aggregate",
+			ctx:                 authz.WithInstanceID(context.Background(), "context-instance"),
+			aggregateInstanceID: "aggregate-instance",
+			want:                "aggregate-instance",
+		},
+		{
+			name:                "instance of aggregate without instance in context",
+			ctx:                 context.Background(),
+			aggregateInstanceID: "aggregate-instance",
+			want:                "aggregate-instance",
+		},
+		{
+			name: "instance of context",
+			ctx:  authz.WithInstanceID(context.Background(), "context-instance"),
+			want: "context-instance",
+		},
+		{
+			name:    "no instance",
+			ctx:     context.Background(),
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name: "explicitly without instance",
+			ctx:  WithoutInstance(context.Background()),
+			want: "",
+		},
+		{
+			name: "instance of context preferred over without instance",
+			ctx:  WithoutInstance(authz.WithInstanceID(context.Background(), "context-instance")),
+			want: "context-instance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := &testEvent{
+				BaseEvent: BaseEvent{
+					Agg: &Aggregate{ID: "id", Type: "test.aggregate", InstanceID: tt.aggregateInstanceID},
+				},
+			}
+			got, err := PushInstanceID(tt.ctx, command)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
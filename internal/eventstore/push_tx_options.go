@@ -0,0 +1,22 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+)
+
+type pushTxOptionsKey struct{}
+
+// WithPushTxOptions returns a context in which commands are pushed in a transaction started with opts,
+// e.g. to push at a weaker isolation level on postgres.
+// Pushes are rejected if opts is read-only.
+// By default the transaction is started with the default options of the database, which is serializable.
+func WithPushTxOptions(ctx context.Context, opts *sql.TxOptions) context.Context {
+	return context.WithValue(ctx, pushTxOptionsKey{}, opts)
+}
+
+// PushTxOptions returns the options set by [WithPushTxOptions], nil if not set
+func PushTxOptions(ctx context.Context) *sql.TxOptions {
+	opts, _ := ctx.Value(pushTxOptionsKey{}).(*sql.TxOptions)
+	return opts
+}
@@ -39,6 +39,9 @@ type Event struct {
 	// It's recommend to use the aggregate id of the user
 	EditorUser string
 
+	//Service is the service which created the event
+	Service string
+
 	//Version describes the definition of the aggregate at a certain point in time
 	// it's used in read models to reduce the events in the correct definition
 	Version eventstore.Version
@@ -75,6 +78,11 @@ func (e *Event) Creator() string {
 	return e.EditorUser
 }
 
+// EditorService implements [eventstore.EditorServicer]
+func (e *Event) EditorService() string {
+	return e.Service
+}
+
 // Type implements [eventstore.Event]
 func (e *Event) Type() eventstore.EventType {
 	return e.Typ
@@ -75,6 +75,20 @@ func (m *MockRepository) ExpectInstanceIDsError(err error) *MockRepository {
 	return m
 }
 
+func (m *MockRepository) ExpectExists(exists bool) *MockRepository {
+	m.MockQuerier.ctrl.T.Helper()
+
+	m.MockQuerier.EXPECT().Exists(gomock.Any(), gomock.Any()).Return(exists, nil)
+	return m
+}
+
+func (m *MockRepository) ExpectExistsError(err error) *MockRepository {
+	m.MockQuerier.ctrl.T.Helper()
+
+	m.MockQuerier.EXPECT().Exists(gomock.Any(), gomock.Any()).Return(false, err)
+	return m
+}
+
 // ExpectPush checks if the expectedCommands are send to the Push method.
 // The call will sleep at least the amount of passed duration.
 func (m *MockRepository) ExpectPush(expectedCommands []eventstore.Command, sleep time.Duration) *MockRepository {
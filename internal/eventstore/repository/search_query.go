@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
+	"reflect"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -24,11 +27,16 @@ type SearchQuery struct {
 	InstanceIDs       *Filter
 	ExcludedInstances *Filter
 	Creator           *Filter
+	CreatorPrefix     *Filter
+	EditorService     *Filter
+	ExcludedServices  *Filter
 	Owner             *Filter
 	Position          *Filter
+	PositionAtMost    *Filter
 	Sequence          *Filter
 	CreatedAfter      *Filter
 	CreatedBefore     *Filter
+	EventTypes        *Filter
 }
 
 // Filter represents all fields needed to compare a field of an event with a value
@@ -54,6 +62,11 @@ const (
 	OperationJSONContains
 	//OperationNotIn checks if a stored value does not match one of the passed value list
 	OperationNotIn
+	// OperationLessOrEqual compares if the stored value is less than or equal the given one
+	OperationLessOrEqual
+	// OperationStartsWith checks if a stored text starts with the given value,
+	// wildcards in the value are matched literally
+	OperationStartsWith
 
 	operationCount
 )
@@ -111,9 +124,24 @@ func (f *Filter) Validate() error {
 	if f.Operation <= 0 || f.Operation >= operationCount {
 		return zerrors.ThrowPreconditionFailed(nil, "REPO-RrQTy", "operation not definded")
 	}
+	if f.Operation == OperationJSONContains && !isJSONObject(f.Value) {
+		return zerrors.ThrowPreconditionFailed(nil, "REPO-3bG9x", "value must be a json object")
+	}
+	if f.Operation == OperationStartsWith && reflect.ValueOf(f.Value).Kind() != reflect.String {
+		return zerrors.ThrowPreconditionFailed(nil, "REPO-Lk3sw", "value must be a text")
+	}
 	return nil
 }
 
+// isJSONObject checks if the value is marshaled to a json object
+func isJSONObject(value interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
 func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, error) {
 	if builder == nil ||
 		builder.GetColumns().Validate() != nil {
@@ -135,11 +163,16 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 		instanceIDFilter,
 		instanceIDsFilter,
 		editorUserFilter,
+		editorUserPrefixFilter,
+		editorServiceFilter,
+		excludedEditorServicesFilter,
 		resourceOwnerFilter,
 		positionAfterFilter,
+		positionAtMostFilter,
 		eventSequenceGreaterFilter,
 		creationDateAfterFilter,
 		creationDateBeforeFilter,
+		aggregateEventTypesFilter,
 	} {
 		filter := f(builder, query)
 		if filter == nil {
@@ -155,7 +188,10 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 			aggregateTypeFilter,
 			aggregateIDFilter,
 			eventTypeFilter,
+			eventTypePrefixFilter,
+			excludedEventTypeFilter,
 			eventDataFilter,
+			eventPayloadFilter,
 		} {
 			filter := f(q)
 			if filter == nil {
@@ -199,6 +235,14 @@ func creationDateBeforeFilter(builder *eventstore.SearchQueryBuilder, query *Sea
 	return query.CreatedBefore
 }
 
+func aggregateEventTypesFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if len(builder.GetEventTypes()) == 0 {
+		return nil
+	}
+	query.EventTypes = NewFilter(FieldEventType, database.TextArray[eventstore.EventType](builder.GetEventTypes()), OperationIn)
+	return query.EventTypes
+}
+
 func resourceOwnerFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
 	if builder.GetResourceOwner() == "" {
 		return nil
@@ -215,6 +259,30 @@ func editorUserFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery
 	return query.Creator
 }
 
+func editorUserPrefixFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetEditorUserPrefix() == "" {
+		return nil
+	}
+	query.CreatorPrefix = NewFilter(FieldEditorUser, builder.GetEditorUserPrefix(), OperationStartsWith)
+	return query.CreatorPrefix
+}
+
+func editorServiceFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetEditorService() == "" {
+		return nil
+	}
+	query.EditorService = NewFilter(FieldEditorService, builder.GetEditorService(), OperationEquals)
+	return query.EditorService
+}
+
+func excludedEditorServicesFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if len(builder.GetExcludedEditorServices()) == 0 {
+		return nil
+	}
+	query.ExcludedServices = NewFilter(FieldEditorService, database.TextArray[string](builder.GetExcludedEditorServices()), OperationNotIn)
+	return query.ExcludedServices
+}
+
 func instanceIDFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
 	if builder.GetInstanceID() == nil {
 		return nil
@@ -239,6 +307,14 @@ func positionAfterFilter(builder *eventstore.SearchQueryBuilder, query *SearchQu
 	return query.Position
 }
 
+func positionAtMostFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetPositionAtMost() == 0 {
+		return nil
+	}
+	query.PositionAtMost = NewFilter(FieldPosition, builder.GetPositionAtMost(), OperationLessOrEqual)
+	return query.PositionAtMost
+}
+
 func aggregateIDFilter(query *eventstore.SearchQuery) *Filter {
 	if len(query.GetAggregateIDs()) < 1 {
 		return nil
@@ -259,6 +335,20 @@ func eventTypeFilter(query *eventstore.SearchQuery) *Filter {
 	return NewFilter(FieldEventType, database.TextArray[eventstore.EventType](query.GetEventTypes()), OperationIn)
 }
 
+func eventTypePrefixFilter(query *eventstore.SearchQuery) *Filter {
+	if query.GetEventTypePrefix() == "" {
+		return nil
+	}
+	return NewFilter(FieldEventType, query.GetEventTypePrefix(), OperationStartsWith)
+}
+
+func excludedEventTypeFilter(query *eventstore.SearchQuery) *Filter {
+	if len(query.GetExcludedEventTypes()) < 1 {
+		return nil
+	}
+	return NewFilter(FieldEventType, database.TextArray[eventstore.EventType](query.GetExcludedEventTypes()), OperationNotIn)
+}
+
 func aggregateTypeFilter(query *eventstore.SearchQuery) *Filter {
 	if len(query.GetAggregateTypes()) < 1 {
 		return nil
@@ -275,3 +365,10 @@ func eventDataFilter(query *eventstore.SearchQuery) *Filter {
 	}
 	return NewFilter(FieldEventData, query.GetEventData(), OperationJSONContains)
 }
+
+func eventPayloadFilter(query *eventstore.SearchQuery) *Filter {
+	if query.GetEventPayload() == nil {
+		return nil
+	}
+	return NewFilter(FieldEventData, query.GetEventPayload(), OperationJSONContains)
+}
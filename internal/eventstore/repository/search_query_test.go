@@ -58,6 +58,51 @@ func TestFilter_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "json contains object",
+			fields: fields{
+				field:     FieldEventData,
+				operation: OperationJSONContains,
+				value:     map[string]interface{}{"name": "zitadel"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "json contains scalar error",
+			fields: fields{
+				field:     FieldEventData,
+				operation: OperationJSONContains,
+				value:     "zitadel",
+			},
+			wantErr: true,
+		},
+		{
+			name: "json contains array error",
+			fields: fields{
+				field:     FieldEventData,
+				operation: OperationJSONContains,
+				value:     []string{"zitadel"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "starts with text",
+			fields: fields{
+				field:     FieldEventType,
+				operation: OperationStartsWith,
+				value:     eventstore.EventType("user."),
+			},
+			wantErr: false,
+		},
+		{
+			name: "starts with number error",
+			fields: fields{
+				field:     FieldSequence,
+				operation: OperationStartsWith,
+				value:     uint64(235),
+			},
+			wantErr: true,
+		},
 		{
 			name:    "filter is nil",
 			fields:  fields{isNil: true},
@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -28,7 +31,7 @@ const (
 	//
 	//previous_data selects the needed data of the latest event of the aggregate
 	// and buffers it (crdb inmemory)
-	crdbInsert = "WITH previous_data (aggregate_type_sequence, aggregate_sequence, resource_owner) AS (" +
+	crdbInsertTemplate = "WITH previous_data (aggregate_type_sequence, aggregate_sequence, resource_owner) AS (" +
 		"SELECT agg_type.seq, agg.seq, agg.ro FROM " +
 		"(" +
 		//max sequence of requested aggregate type
@@ -72,20 +75,23 @@ const (
 		" $2::VARCHAR AS aggregate_type," +
 		" $3::VARCHAR AS aggregate_id," +
 		" $4::VARCHAR AS aggregate_version," +
-		" hlc_to_timestamp(cluster_logical_timestamp()) AS creation_date," +
+		" %s AS creation_date," +
 		" cluster_logical_timestamp() AS position," +
 		" $5::JSONB AS event_data," +
 		" $6::VARCHAR AS editor_user," +
 		" $7::VARCHAR AS editor_service," +
 		" COALESCE((resource_owner), $8::VARCHAR) AS resource_owner," +
 		" $9::VARCHAR AS instance_id," +
-		" COALESCE(aggregate_sequence, 0)+1," +
-		" aggregate_sequence AS previous_aggregate_sequence," +
+		// $11 is the sequence of the previous event of the aggregate pushed in the same transaction
+		" COALESCE($11::INT8, aggregate_sequence, 0)+1," +
+		" COALESCE($11::INT8, aggregate_sequence) AS previous_aggregate_sequence," +
 		" aggregate_type_sequence AS previous_aggregate_type_sequence," +
 		" $10 AS in_tx_order " +
 		"FROM previous_data " +
 		"RETURNING id, event_sequence, creation_date, resource_owner, instance_id"
 
+	aggregateExistsQuery = "SELECT EXISTS(SELECT 1 FROM eventstore.events WHERE aggregate_type = $1 AND aggregate_id = $2 AND instance_id = $3)"
+
 	uniqueInsert = `INSERT INTO eventstore.unique_constraints
 					(
 						unique_type,
@@ -102,6 +108,46 @@ const (
 					WHERE unique_type = $1 and unique_field = $2 and instance_id = $3`
 	uniqueDeleteInstance = `DELETE FROM eventstore.unique_constraints
 					WHERE instance_id = $1`
+	uniqueExistsQuery = "SELECT unique_type, unique_field FROM eventstore.unique_constraints" +
+		" WHERE (instance_id, unique_type, unique_field) IN (%s)"
+	uniqueListQuery = "SELECT unique_type, unique_field FROM eventstore.unique_constraints" +
+		" WHERE instance_id = $1 AND ($2::TEXT = '' OR unique_type = $2::TEXT)" +
+		" ORDER BY unique_type, unique_field"
+
+	currentSequenceQuery = `SELECT COALESCE(MAX("sequence"), 0)` +
+		" FROM eventstore.events2" +
+		" WHERE instance_id = $1 AND aggregate_type = $2 AND aggregate_id = $3"
+
+	streamQuery = `SELECT "sequence"` +
+		" FROM eventstore.events2" +
+		" WHERE instance_id = $1 AND aggregate_type = $2 AND aggregate_id = $3" +
+		` ORDER BY "sequence"`
+
+	// backfillInstanceIDStmt sets the instance id of a batch of events in eventstore.events2 without instance id,
+	// which is stored as empty string because the column is part of the primary key
+	backfillInstanceIDStmt = "UPDATE eventstore.events2 SET instance_id = $1" +
+		` WHERE (instance_id, aggregate_type, aggregate_id, "sequence") IN (` +
+		`SELECT instance_id, aggregate_type, aggregate_id, "sequence" FROM eventstore.events2 WHERE instance_id = '' LIMIT $2)`
+
+	aggregateTypeStorageQuery = "SELECT aggregate_type, COALESCE(SUM(pg_column_size(payload)), 0)" +
+		" FROM eventstore.events2" +
+		" WHERE instance_id = $1" +
+		" GROUP BY aggregate_type"
+
+	aggregateTypesQuery = "SELECT DISTINCT aggregate_type" +
+		" FROM eventstore.events2" +
+		" WHERE instance_id = $1"
+)
+
+var (
+	// crdbInserts are the insert statements per creation date of the events,
+	// events inserted by separate statements of the same push have distinct statement timestamps
+	crdbInserts = map[string]string{
+		eventstore.CreationDateDefault:     fmt.Sprintf(crdbInsertTemplate, "hlc_to_timestamp(cluster_logical_timestamp())"),
+		eventstore.CreationDateStatement:   fmt.Sprintf(crdbInsertTemplate, "statement_timestamp()"),
+		eventstore.CreationDateTransaction: fmt.Sprintf(crdbInsertTemplate, "transaction_timestamp()"),
+	}
+	crdbInsert = crdbInserts[eventstore.CreationDateDefault]
 )
 
 // awaitOpenTransactions ensures event ordering, so we don't events younger that open transactions
@@ -119,9 +165,70 @@ func awaitOpenTransactions(useV1 bool) string {
 
 type CRDB struct {
 	*database.DB
+
+	// creationDate defines the timestamp function used as creation date of the pushed events
+	creationDate string
+	// slowQuery is the duration after which filter queries are logged, disabled if not positive
+	slowQuery time.Duration
+	// compressionThreshold is the size in bytes above which payloads are stored gzipped, disabled if not positive
+	compressionThreshold int
+	// pruneSafetyWindow is the minimal age of pruned events, [DefaultPruneSafetyWindow] is used if nil
+	pruneSafetyWindow *time.Duration
+	// exportRedactors scrub the payloads of the events written by [CRDB.ExportEvents]
+	exportRedactors ExportRedactors
+}
+
+type CRDBOption func(*CRDB)
+
+// WithPruneSafetyWindow overrides the minimal age of the events deleted by [CRDB.PruneEvents],
+// which is [DefaultPruneSafetyWindow] by default.
+// A window of 0 allows to prune events up to now.
+func WithPruneSafetyWindow(window time.Duration) CRDBOption {
+	return func(db *CRDB) {
+		db.pruneSafetyWindow = &window
+	}
+}
+
+// WithCreationDate defines which timestamp is used as creation date of pushed events,
+// see [eventstore.CreationDateDefault], [eventstore.CreationDateStatement] and [eventstore.CreationDateTransaction].
+// Unknown values fall back to the default.
+func WithCreationDate(creationDate string) CRDBOption {
+	return func(db *CRDB) {
+		if _, ok := crdbInserts[creationDate]; !ok {
+			logging.WithFields("creationDate", creationDate).Warn("unknown creation date of events, default is used")
+			return
+		}
+		db.creationDate = creationDate
+	}
+}
+
+// WithSlowQueryThreshold logs filter queries taking longer than threshold
+// including the statement, the arguments and the elapsed time.
+// Logging is disabled if threshold is not positive.
+func WithSlowQueryThreshold(threshold time.Duration) CRDBOption {
+	return func(db *CRDB) {
+		db.slowQuery = threshold
+	}
+}
+
+// WithPayloadCompression stores payloads larger than threshold bytes gzipped.
+// Compressed payloads are decompressed while scanning events, regardless of this option.
+// Filters on the event data don't match compressed payloads.
+// Compression is disabled if threshold is not positive.
+func WithPayloadCompression(threshold int) CRDBOption {
+	return func(db *CRDB) {
+		db.compressionThreshold = threshold
+	}
+}
+
+// WithExportRedactors applies the redactors to the payloads of the events exported by [CRDB.ExportEvents].
+func WithExportRedactors(redactors ExportRedactors) CRDBOption {
+	return func(db *CRDB) {
+		db.exportRedactors = redactors
+	}
 }
 
-func NewCRDB(client *database.DB) *CRDB {
+func NewCRDB(client *database.DB, opts ...CRDBOption) *CRDB {
 	switch client.Type() {
 	case "cockroach":
 		awaitOpenTransactionsV1 = " AND creation_date::TIMESTAMP < (SELECT COALESCE(MIN(start), NOW())::TIMESTAMP FROM crdb_internal.cluster_transactions where application_name = '" + dialect.EventstorePusherAppName + "')"
@@ -131,24 +238,46 @@ func NewCRDB(client *database.DB) *CRDB {
 		awaitOpenTransactionsV2 = ` AND "position" < (SELECT COALESCE(EXTRACT(EPOCH FROM min(xact_start)), EXTRACT(EPOCH FROM now())) FROM pg_stat_activity WHERE datname = current_database() AND application_name = '` + dialect.EventstorePusherAppName + `' AND state <> 'idle')`
 	}
 
-	return &CRDB{client}
+	db := &CRDB{DB: client}
+	for _, opt := range opts {
+		opt(db)
+	}
+	return db
 }
 
 func (db *CRDB) Health(ctx context.Context) error { return db.Ping() }
 
 // Push adds all events to the eventstreams of the aggregates.
 // This call is transaction save. The transaction will be rolled back if one event fails
+// The commands can belong to different instances, commands without instance are pushed to the instance of the context,
+// see [eventstore.PushInstanceID].
+// Unique constraints are not handled if the context is in import mode, see [eventstore.WithImportMode].
+// The transaction is started with the options of [eventstore.WithPushTxOptions].
 func (db *CRDB) Push(ctx context.Context, commands ...eventstore.Command) (events []eventstore.Event, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, zerrors.ThrowDeadlineExceeded(err, "SQL-Cq7Vx", "push cancelled")
+	}
+	txOpts := eventstore.PushTxOptions(ctx)
+	if txOpts != nil && txOpts.ReadOnly {
+		return nil, zerrors.ThrowInvalidArgument(nil, "SQL-Tx0ro", "events cannot be pushed in a read-only transaction")
+	}
+	instanceIDs := make([]string, len(commands))
+	for i, command := range commands {
+		if instanceIDs[i], err = eventstore.PushInstanceID(ctx, command); err != nil {
+			return nil, err
+		}
+	}
 	events = make([]eventstore.Event, len(commands))
 
-	err = crdb.ExecuteTx(ctx, db.DB.DB, nil, func(tx *sql.Tx) error {
+	err = crdb.ExecuteTx(ctx, db.DB.DB, txOpts, func(tx *sql.Tx) error {
 
-		var uniqueConstraints []*eventstore.UniqueConstraint
+		// sequences keeps the latest sequence of the aggregates pushed in this transaction
+		// so that events of the same aggregate don't depend on reading the previously inserted rows
+		sequences := make(map[aggregateKey]uint64)
 
 		for i, command := range commands {
-			if command.Aggregate().InstanceID == "" {
-				command.Aggregate().InstanceID = authz.GetInstance(ctx).InstanceID()
-			}
+			instanceID := instanceIDs[i]
+			command.Aggregate().InstanceID = instanceID
 
 			var payload []byte
 			if command.Payload() != nil {
@@ -157,28 +286,56 @@ func (db *CRDB) Push(ctx context.Context, commands ...eventstore.Command) (event
 					return err
 				}
 			}
+			if err = eventstore.ValidatePayload(command.Type(), payload); err != nil {
+				return err
+			}
+			if err = eventstore.ValidateAggregateVersion(command.Aggregate().Type, command.Aggregate().Version); err != nil {
+				return err
+			}
+			storedPayload, err := eventstore.CompressPayload(payload, db.compressionThreshold)
+			if err != nil {
+				return zerrors.ThrowInternal(err, "SQL-Gz7pq", "unable to compress payload")
+			}
 			e := &repository.Event{
 				Typ:           command.Type(),
 				Data:          payload,
-				EditorUser:    command.Creator(),
+				EditorUser:    eventstore.EditorUser(ctx, command),
 				Version:       command.Aggregate().Version,
 				AggregateID:   command.Aggregate().ID,
 				AggregateType: command.Aggregate().Type,
 				ResourceOwner: sql.NullString{String: command.Aggregate().ResourceOwner, Valid: command.Aggregate().ResourceOwner != ""},
-				InstanceID:    command.Aggregate().InstanceID,
+				InstanceID:    instanceID,
+				// the service declared by the command is not stored,
+				// because eventstore.events2 written by the v3 pusher has no editor service
+				Service: "zitadel",
 			}
 
-			err := tx.QueryRowContext(ctx, crdbInsert,
+			key := aggregateKey{
+				instanceID:    e.Aggregate().InstanceID,
+				aggregateType: e.Aggregate().Type,
+				aggregateID:   e.Aggregate().ID,
+			}
+			var previousSequence sql.NullInt64
+			if sequence, ok := sequences[key]; ok {
+				previousSequence = sql.NullInt64{Int64: int64(sequence), Valid: true}
+			} else if eventstore.RequiresExistingAggregate(command) {
+				if err = aggregateExists(ctx, tx, key); err != nil {
+					return err
+				}
+			}
+
+			err = tx.QueryRowContext(ctx, crdbInserts[db.creationDate],
 				e.Type(),
 				e.Aggregate().Type,
 				e.Aggregate().ID,
 				e.Aggregate().Version,
-				payload,
+				storedPayload,
 				e.Creator(),
-				"zitadel",
+				e.Service,
 				e.Aggregate().ResourceOwner,
 				e.Aggregate().InstanceID,
 				i,
+				previousSequence,
 			).Scan(&e.ID, &e.Seq, &e.CreationDate, &e.ResourceOwner, &e.InstanceID)
 
 			if err != nil {
@@ -192,12 +349,23 @@ func (db *CRDB) Push(ctx context.Context, commands ...eventstore.Command) (event
 				return zerrors.ThrowInternal(err, "SQL-SBP37", "unable to create event")
 			}
 
-			uniqueConstraints = append(uniqueConstraints, command.UniqueConstraints()...)
+			sequences[key] = e.Seq
 			events[i] = e
 		}
 
-		return db.handleUniqueConstraints(ctx, tx, uniqueConstraints...)
+		// the constraints are scoped by the instance of the command, which differs from the context in batches of several instances
+		for i, command := range commands {
+			if err = db.handleUniqueConstraints(ctx, tx, events[i].Aggregate().InstanceID, command.UniqueConstraints()...); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
+	// the transaction is rolled back if the context is done during the push,
+	// other errors are returned as they are even if the context is done meanwhile
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, zerrors.ThrowDeadlineExceeded(err, "SQL-Cq7Vy", "push cancelled")
+	}
 	if err != nil && !errors.Is(err, &zerrors.ZitadelError{}) {
 		err = zerrors.ThrowInternal(err, "SQL-DjgtG", "unable to store events")
 	}
@@ -205,30 +373,81 @@ func (db *CRDB) Push(ctx context.Context, commands ...eventstore.Command) (event
 	return events, err
 }
 
-// handleUniqueConstraints adds or removes unique constraints
-func (db *CRDB) handleUniqueConstraints(ctx context.Context, tx *sql.Tx, uniqueConstraints ...*eventstore.UniqueConstraint) (err error) {
+// aggregateExists returns a not found error if no event of the aggregate exists
+func aggregateExists(ctx context.Context, tx *sql.Tx, key aggregateKey) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, aggregateExistsQuery, key.aggregateType, key.aggregateID, key.instanceID).Scan(&exists)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "SQL-Hn3lq", "unable to check existence of aggregate")
+	}
+	if !exists {
+		return zerrors.ThrowNotFoundf(nil, "SQL-Hn3lr", "aggregate %s %s not found", key.aggregateType, key.aggregateID)
+	}
+	return nil
+}
+
+// aggregateKey identifies an aggregate across instances
+type aggregateKey struct {
+	instanceID    string
+	aggregateType eventstore.AggregateType
+	aggregateID   string
+}
+
+// BackfillInstanceID sets the instance id of all events in eventstore.events2 without instance id.
+// The legacy eventstore.events table is not updated, it was copied to eventstore.events2 by setup step 14.
+// The events are updated in batches of batchSize, each batch is committed separately.
+// If the backfill is interrupted it continues with the remaining events on the next call.
+func (db *CRDB) BackfillInstanceID(ctx context.Context, instanceID string, batchSize int) (updated int64, err error) {
+	if instanceID == "" || batchSize <= 0 {
+		return 0, zerrors.ThrowInvalidArgument(nil, "SQL-Bq3fk", "instance id and positive batch size required")
+	}
+	for {
+		res, err := db.DB.ExecContext(ctx, backfillInstanceIDStmt, instanceID, batchSize)
+		if err != nil {
+			return updated, zerrors.ThrowInternal(err, "SQL-Bq3fl", "unable to backfill instance id")
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return updated, zerrors.ThrowInternal(err, "SQL-Bq3fm", "unable to backfill instance id")
+		}
+		updated += rows
+		logging.WithFields("instance", instanceID, "batch", rows, "updated", updated).Info("instance id of events backfilled")
+		if rows < int64(batchSize) {
+			return updated, nil
+		}
+	}
+}
+
+// handleUniqueConstraints adds or removes unique constraints of the instance
+// The constraints are skipped in import mode, see [eventstore.WithImportMode]
+func (db *CRDB) handleUniqueConstraints(ctx context.Context, tx *sql.Tx, instanceID string, uniqueConstraints ...*eventstore.UniqueConstraint) (err error) {
+	if eventstore.IsImportMode(ctx) {
+		return nil
+	}
 	if len(uniqueConstraints) == 0 || (len(uniqueConstraints) == 1 && uniqueConstraints[0] == nil) {
 		return nil
 	}
 
 	for _, uniqueConstraint := range uniqueConstraints {
-		uniqueConstraint.UniqueField = strings.ToLower(uniqueConstraint.UniqueField)
+		if !uniqueConstraint.CaseSensitive {
+			uniqueConstraint.UniqueField = strings.ToLower(uniqueConstraint.UniqueField)
+		}
 		switch uniqueConstraint.Action {
 		case eventstore.UniqueConstraintAdd:
-			_, err := tx.ExecContext(ctx, uniqueInsert, uniqueConstraint.UniqueType, uniqueConstraint.UniqueField, authz.GetInstance(ctx).InstanceID())
+			_, err := tx.ExecContext(ctx, uniqueInsert, uniqueConstraint.UniqueType, uniqueConstraint.UniqueField, instanceID)
 			if err != nil {
 				logging.WithFields(
 					"unique_type", uniqueConstraint.UniqueType,
 					"unique_field", uniqueConstraint.UniqueField).WithError(err).Info("insert unique constraint failed")
 
 				if db.isUniqueViolationError(err) {
-					return zerrors.ThrowAlreadyExists(err, "SQL-wHcEq", uniqueConstraint.ErrorMessage)
+					return eventstore.ThrowUniqueConstraintViolation(err, "SQL-wHcEq", uniqueConstraint)
 				}
 
 				return zerrors.ThrowInternal(err, "SQL-dM9ds", "unable to create unique constraint")
 			}
 		case eventstore.UniqueConstraintRemove:
-			_, err := tx.ExecContext(ctx, uniqueDelete, uniqueConstraint.UniqueType, uniqueConstraint.UniqueField, authz.GetInstance(ctx).InstanceID())
+			_, err := tx.ExecContext(ctx, uniqueDelete, uniqueConstraint.UniqueType, uniqueConstraint.UniqueField, instanceID)
 			if err != nil {
 				logging.WithFields(
 					"unique_type", uniqueConstraint.UniqueType,
@@ -236,10 +455,10 @@ func (db *CRDB) handleUniqueConstraints(ctx context.Context, tx *sql.Tx, uniqueC
 				return zerrors.ThrowInternal(err, "SQL-6n88i", "unable to remove unique constraint")
 			}
 		case eventstore.UniqueConstraintInstanceRemove:
-			_, err := tx.ExecContext(ctx, uniqueDeleteInstance, authz.GetInstance(ctx).InstanceID())
+			_, err := tx.ExecContext(ctx, uniqueDeleteInstance, instanceID)
 			if err != nil {
 				logging.WithFields(
-					"instance_id", authz.GetInstance(ctx).InstanceID()).WithError(err).Info("delete instance unique constraints failed")
+					"instance_id", instanceID).WithError(err).Info("delete instance unique constraints failed")
 				return zerrors.ThrowInternal(err, "SQL-6n88i", "unable to remove unique constraints of instance")
 			}
 		}
@@ -247,6 +466,94 @@ func (db *CRDB) handleUniqueConstraints(ctx context.Context, tx *sql.Tx, uniqueC
 	return nil
 }
 
+// UniqueConstraintKey identifies a unique constraint in the result of [CRDB.UniqueConstraintsExist]
+func UniqueConstraintKey(uniqueType, uniqueField string) string {
+	return uniqueType + ":" + uniqueField
+}
+
+// UniqueConstraintsExist reports which of the constraints are already claimed in the instance of the context,
+// global constraints are checked across all instances.
+// The result is keyed by [UniqueConstraintKey] of the type and field as passed by the caller,
+// fields are compared lowercased unless the constraint is case sensitive.
+func (db *CRDB) UniqueConstraintsExist(ctx context.Context, constraints ...*eventstore.UniqueConstraint) (exists map[string]bool, err error) {
+	exists = make(map[string]bool, len(constraints))
+	keys := make(map[string][]string, len(constraints))
+	placeholders := make([]string, 0, len(constraints))
+	args := make([]any, 0, len(constraints)*3)
+	for _, constraint := range constraints {
+		if constraint == nil {
+			continue
+		}
+		// global constraints are stored without instance
+		instanceID := authz.GetInstance(ctx).InstanceID()
+		if constraint.IsGlobal {
+			instanceID = ""
+		}
+		field := constraint.UniqueField
+		if !constraint.CaseSensitive {
+			field = strings.ToLower(field)
+		}
+		key := UniqueConstraintKey(constraint.UniqueType, constraint.UniqueField)
+		exists[key] = false
+		normalized := UniqueConstraintKey(constraint.UniqueType, field)
+		keys[normalized] = append(keys[normalized], key)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3))
+		args = append(args, instanceID, constraint.UniqueType, field)
+	}
+	if len(placeholders) == 0 {
+		return exists, nil
+	}
+
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var uniqueType, uniqueField string
+				if err := rows.Scan(&uniqueType, &uniqueField); err != nil {
+					return err
+				}
+				for _, key := range keys[UniqueConstraintKey(uniqueType, uniqueField)] {
+					exists[key] = true
+				}
+			}
+			return nil
+		},
+		fmt.Sprintf(uniqueExistsQuery, strings.Join(placeholders, ", ")),
+		args...,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Uq3xs", "unable to check unique constraints")
+	}
+	return exists, nil
+}
+
+// ListUniqueConstraints returns the unique constraints claimed in the instance ordered by type and field.
+// Only the constraints of uniqueType are returned if it's not empty.
+// The constraints pushed without instance are returned if the instance id is empty.
+// It's meant to troubleshoot already exists errors of pushes.
+func (db *CRDB) ListUniqueConstraints(ctx context.Context, instanceID string, uniqueType string) (constraints []*eventstore.UniqueConstraint, err error) {
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				constraint := &eventstore.UniqueConstraint{
+					Action: eventstore.UniqueConstraintAdd,
+				}
+				if err := rows.Scan(&constraint.UniqueType, &constraint.UniqueField); err != nil {
+					return err
+				}
+				constraints = append(constraints, constraint)
+			}
+			return nil
+		},
+		uniqueListQuery,
+		instanceID,
+		uniqueType,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Uq4ls", "unable to list unique constraints")
+	}
+	return constraints, nil
+}
+
 // FilterToReducer finds all events matching the given search query and passes them to the reduce function.
 func (crdb *CRDB) FilterToReducer(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder, reduce eventstore.Reducer) error {
 	err := query(ctx, crdb, searchQuery, reduce, false)
@@ -278,6 +585,262 @@ func (db *CRDB) InstanceIDs(ctx context.Context, searchQuery *eventstore.SearchQ
 	return ids, nil
 }
 
+// Exists returns if at least one event matches the search query
+func (db *CRDB) Exists(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (bool, error) {
+	var exists bool
+	err := query(ctx, db, searchQuery, &exists, false)
+	return exists, err
+}
+
+// LatestEvent returns the most recent event of the given aggregate
+func (db *CRDB) LatestEvent(ctx context.Context, aggregateType, aggregateID string) (eventstore.Event, error) {
+	searchQuery := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(authz.GetInstance(ctx).InstanceID()).
+		OrderDesc().
+		Limit(1).
+		AddQuery().
+		AggregateTypes(eventstore.AggregateType(aggregateType)).
+		AggregateIDs(aggregateID).
+		Builder()
+
+	var latest eventstore.Event
+	err := db.FilterToReducer(ctx, searchQuery, func(event eventstore.Event) error {
+		latest = event
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, zerrors.ThrowNotFound(nil, "SQL-Lh4rq", "no event found for aggregate")
+	}
+	return latest, nil
+}
+
+// CurrentSequence returns the sequence of the latest event of the aggregate in the instance of the context,
+// 0 if the aggregate has no events.
+// It is used to check the expected version of an aggregate before pushing.
+func (db *CRDB) CurrentSequence(ctx context.Context, aggregateType, aggregateID string) (sequence uint64, err error) {
+	err = db.DB.QueryRowContext(ctx,
+		func(row *sql.Row) error {
+			return row.Scan(&sequence)
+		},
+		currentSequenceQuery,
+		authz.GetInstance(ctx).InstanceID(),
+		aggregateType,
+		aggregateID,
+	)
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "SQL-Cs8qw", "unable to get current sequence of aggregate")
+	}
+	return sequence, nil
+}
+
+// FilterToAggregateMap returns the events of the search query grouped by aggregate id
+// the events of an aggregate keep the order of the search query.
+// All matching events are held in memory, set a limit on the search query to bound them.
+func (db *CRDB) FilterToAggregateMap(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (map[string][]eventstore.Event, error) {
+	aggregates := make(map[string][]eventstore.Event)
+	err := db.FilterToReducer(ctx, searchQuery, func(event eventstore.Event) error {
+		aggregates[event.Aggregate().ID] = append(aggregates[event.Aggregate().ID], event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aggregates, nil
+}
+
+// FilterLatestPerAggregate returns the latest event of each aggregate matching the search query,
+// e.g. to read the current state of all sessions in a single query.
+// The filters of the search query are applied before the latest event is selected,
+// the events are ordered by instance, aggregate type and aggregate id.
+func (db *CRDB) FilterLatestPerAggregate(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (events []eventstore.Event, err error) {
+	q, err := repository.QueryFromBuilder(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if q.Columns != eventstore.ColumnsEvent {
+		return nil, zerrors.ThrowInvalidArgument(nil, "SQL-Lp4ag", "only events can be filtered per aggregate")
+	}
+	where, values, err := prepareConditions(db, q, false)
+	if err != nil {
+		return nil, err
+	}
+	if where == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "SQL-Lp5ag", "invalid query factory")
+	}
+	stmt := strings.Replace(db.eventQuery(false), "SELECT", "SELECT DISTINCT ON (instance_id, aggregate_type, aggregate_id)", 1) +
+		where +
+		` ORDER BY instance_id, aggregate_type, aggregate_id, "sequence" DESC`
+	if q.Limit > 0 {
+		values = append(values, q.Limit)
+		stmt += " LIMIT ?"
+	}
+	stmt = db.placeholder(stmt)
+
+	start := time.Now()
+	defer logSlowQuery(db.slowQueryThreshold(), start, stmt, values)
+
+	scanEvent := eventsScanner(false)
+	reduce := eventstore.Reducer(func(event eventstore.Event) error {
+		events = append(events, event)
+		return nil
+	})
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				if err := scanEvent(rows.Scan, reduce); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, stmt, values...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Lp6ag", "unable to filter events")
+	}
+	return events, nil
+}
+
+// FilterAsOfSequence returns the events of the search query up to and including the given sequence,
+// ordered by their position in the eventstore. The sequence is the global position as returned by [CRDB.LatestSequence].
+// Replays using the same sequence return the same events regardless of events pushed afterwards.
+func (db *CRDB) FilterAsOfSequence(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder, sequence float64) (events []eventstore.Event, err error) {
+	if sequence <= 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "SQL-Sn4pq", "sequence must be positive")
+	}
+	err = query(ctx, db, searchQuery.PositionAtMost(sequence).OrderAsc(), eventstore.Reducer(func(event eventstore.Event) error {
+		events = append(events, event)
+		return nil
+	}), false)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FilterOne returns the only event matching the search query, e.g. the started event of an intent.
+// A not found error is returned if no event matches and a precondition failed error if several events match.
+// The limit of the search query is overwritten.
+func (db *CRDB) FilterOne(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (eventstore.Event, error) {
+	events := make([]eventstore.Event, 0, 2)
+	err := query(ctx, db, searchQuery.Limit(2), eventstore.Reducer(func(event eventstore.Event) error {
+		events = append(events, event)
+		return nil
+	}), false)
+	if err != nil {
+		return nil, err
+	}
+	switch len(events) {
+	case 0:
+		return nil, zerrors.ThrowNotFound(nil, "SQL-Fo1nf", "no event matches the search query")
+	case 1:
+		return events[0], nil
+	default:
+		return nil, zerrors.ThrowPreconditionFailed(nil, "SQL-Fo2pf", "more than one event matches the search query")
+	}
+}
+
+// Gap describes an event of an aggregate
+// whose sequence doesn't follow the sequence of the event before
+type Gap struct {
+	// Index is the position of the event in the stream of the aggregate
+	Index int
+	// Sequence is the stored sequence of the event
+	Sequence uint64
+	// ExpectedSequence is the sequence following the event before, 1 for the first event
+	ExpectedSequence uint64
+}
+
+// VerifyStream walks the events of the aggregate in eventstore.events2 ordered by sequence
+// and returns all events whose sequence doesn't directly follow the sequence of the prior event.
+// Missing events as well as duplicate sequences are reported.
+func (db *CRDB) VerifyStream(ctx context.Context, aggregateType, aggregateID string) (gaps []Gap, err error) {
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			var previous uint64
+			for i := 0; rows.Next(); i++ {
+				var sequence uint64
+				if err := rows.Scan(&sequence); err != nil {
+					return err
+				}
+				if sequence != previous+1 {
+					gaps = append(gaps, Gap{
+						Index:            i,
+						Sequence:         sequence,
+						ExpectedSequence: previous + 1,
+					})
+				}
+				previous = sequence
+			}
+			return nil
+		},
+		streamQuery,
+		authz.GetInstance(ctx).InstanceID(),
+		aggregateType,
+		aggregateID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Gv8ls", "unable to verify stream")
+	}
+	return gaps, nil
+}
+
+// AggregateTypeStorage returns the stored size of the payloads in bytes per aggregate type of the instance,
+// compressed payloads are counted with their compressed size
+func (db *CRDB) AggregateTypeStorage(ctx context.Context, instanceID string) (storage map[string]int64, err error) {
+	storage = make(map[string]int64)
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var (
+					aggregateType string
+					size          int64
+				)
+				if err := rows.Scan(&aggregateType, &size); err != nil {
+					return err
+				}
+				storage[aggregateType] = size
+			}
+			return nil
+		},
+		aggregateTypeStorageQuery,
+		instanceID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Ph3aq", "unable to compute storage of aggregate types")
+	}
+	return storage, nil
+}
+
+// AggregateTypes returns the sorted distinct aggregate types of the events of the instance.
+// If the instance id is empty the aggregate types of the events without instance are returned.
+func (db *CRDB) AggregateTypes(ctx context.Context, instanceID string) (types []string, err error) {
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var aggregateType string
+				if err := rows.Scan(&aggregateType); err != nil {
+					return err
+				}
+				types = append(types, aggregateType)
+			}
+			return nil
+		},
+		aggregateTypesQuery,
+		instanceID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Agt7y", "unable to query aggregate types")
+	}
+	slices.Sort(types)
+	return types, nil
+}
+
+func (db *CRDB) slowQueryThreshold() time.Duration {
+	return db.slowQuery
+}
+
 func (db *CRDB) db() *database.DB {
 	return db.DB
 }
@@ -304,6 +867,7 @@ func (db *CRDB) eventQuery(useV1 bool) string {
 			", event_sequence" +
 			", event_data" +
 			", editor_user" +
+			", editor_service" +
 			", resource_owner" +
 			", instance_id" +
 			", aggregate_type" +
@@ -341,6 +905,14 @@ func (db *CRDB) instanceIDsQuery(useV1 bool) string {
 	return "SELECT DISTINCT instance_id FROM " + table
 }
 
+func (db *CRDB) existsQuery(useV1 bool) string {
+	table := "eventstore.events2"
+	if useV1 {
+		table = "eventstore.events"
+	}
+	return "SELECT 1 FROM " + table
+}
+
 func (db *CRDB) columnName(col repository.Field, useV1 bool) string {
 	switch col {
 	case repository.FieldAggregateID:
@@ -406,10 +978,14 @@ func (db *CRDB) operation(operation repository.Operation) string {
 		return ">"
 	case repository.OperationLess:
 		return "<"
+	case repository.OperationLessOrEqual:
+		return "<="
 	case repository.OperationJSONContains:
 		return "@>"
 	case repository.OperationNotIn:
 		return "<>"
+	case repository.OperationStartsWith:
+		return "LIKE"
 	}
 	return ""
 }
@@ -437,10 +1013,5 @@ func (db *CRDB) placeholder(query string) string {
 }
 
 func (db *CRDB) isUniqueViolationError(err error) bool {
-	if pgxErr, ok := err.(*pgconn.PgError); ok {
-		if pgxErr.Code == "23505" {
-			return true
-		}
-	}
-	return false
+	return database.IsPGErrorCode(err, database.PGCodeUniqueViolation)
 }
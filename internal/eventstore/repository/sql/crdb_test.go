@@ -1,11 +1,27 @@
 package sql
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 func TestCRDB_placeholder(t *testing.T) {
@@ -97,6 +113,15 @@ func TestCRDB_operation(t *testing.T) {
 				op: "<",
 			},
 		},
+		{
+			name: "less or equal",
+			args: args{
+				operation: repository.OperationLessOrEqual,
+			},
+			res: res{
+				op: "<=",
+			},
+		},
 		{
 			name: "equals",
 			args: args{
@@ -321,3 +346,2127 @@ func generateEvent(t *testing.T, aggregateID string, opts ...func(*repository.Ev
 
 	return e
 }
+
+func TestCRDB_CurrentSequence(t *testing.T) {
+	type res struct {
+		sequence uint64
+		wantErr  bool
+	}
+	tests := []struct {
+		name   string
+		expect func(*sqlmock.ExpectedQuery)
+		res    res
+	}{
+		{
+			name: "aggregate with events",
+			expect: func(query *sqlmock.ExpectedQuery) {
+				query.WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(uint64(42)))
+			},
+			res: res{
+				sequence: 42,
+			},
+		},
+		{
+			name: "aggregate without events",
+			expect: func(query *sqlmock.ExpectedQuery) {
+				query.WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(uint64(0)))
+			},
+			res: res{
+				sequence: 0,
+			},
+		},
+		{
+			name: "query fails",
+			expect: func(query *sqlmock.ExpectedQuery) {
+				query.WillReturnError(sql.ErrConnDone)
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClientWithMatcher(t, sqlmock.QueryMatcherEqual)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			tt.expect(mock.ExpectQuery(currentSequenceQuery).WithArgs("instance", "user", "1"))
+			if tt.res.wantErr {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			db := client.crdb()
+			sequence, err := db.CurrentSequence(authz.WithInstanceID(context.Background(), "instance"), "user", "1")
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.CurrentSequence() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if sequence != tt.res.sequence {
+				t.Errorf("CRDB.CurrentSequence() = %d, want %d", sequence, tt.res.sequence)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_VerifyStream(t *testing.T) {
+	type fields struct {
+		rows [][]driver.Value
+		err  error
+	}
+	type res struct {
+		gaps    []Gap
+		wantErr bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		res    res
+	}{
+		{
+			name: "no events",
+			fields: fields{
+				rows: [][]driver.Value{},
+			},
+			res: res{
+				gaps: nil,
+			},
+		},
+		{
+			name: "consistent stream",
+			fields: fields{
+				rows: [][]driver.Value{
+					{uint64(1)},
+					{uint64(2)},
+					{uint64(3)},
+				},
+			},
+			res: res{
+				gaps: nil,
+			},
+		},
+		{
+			name: "missing event",
+			fields: fields{
+				rows: [][]driver.Value{
+					{uint64(1)},
+					{uint64(2)},
+					{uint64(4)},
+					{uint64(5)},
+				},
+			},
+			res: res{
+				gaps: []Gap{
+					{
+						Index:            2,
+						Sequence:         4,
+						ExpectedSequence: 3,
+					},
+				},
+			},
+		},
+		{
+			name: "missing first event",
+			fields: fields{
+				rows: [][]driver.Value{
+					{uint64(2)},
+					{uint64(3)},
+				},
+			},
+			res: res{
+				gaps: []Gap{
+					{
+						Index:            0,
+						Sequence:         2,
+						ExpectedSequence: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "duplicate sequence",
+			fields: fields{
+				rows: [][]driver.Value{
+					{uint64(1)},
+					{uint64(2)},
+					{uint64(2)},
+					{uint64(3)},
+				},
+			},
+			res: res{
+				gaps: []Gap{
+					{
+						Index:            2,
+						Sequence:         2,
+						ExpectedSequence: 3,
+					},
+				},
+			},
+		},
+		{
+			name: "query fails",
+			fields: fields{
+				err: sql.ErrConnDone,
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			query := mock.ExpectQuery(regexp.QuoteMeta(streamQuery)).WithArgs("instance", "user", "1")
+			if tt.fields.err != nil {
+				query.WillReturnError(tt.fields.err)
+				mock.ExpectRollback()
+			} else {
+				rows := mock.NewRows([]string{"sequence"})
+				for _, row := range tt.fields.rows {
+					rows.AddRow(row...)
+				}
+				query.WillReturnRows(rows)
+				mock.ExpectCommit()
+			}
+
+			db := client.crdb()
+			gaps, err := db.VerifyStream(authz.WithInstanceID(context.Background(), "instance"), "user", "1")
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.VerifyStream() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gaps, tt.res.gaps) {
+				t.Errorf("CRDB.VerifyStream() = %v, want %v", gaps, tt.res.gaps)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_AggregateTypeStorage(t *testing.T) {
+	type fields struct {
+		rows [][]driver.Value
+		err  error
+	}
+	type res struct {
+		storage map[string]int64
+		wantErr bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		res    res
+	}{
+		{
+			name: "no events",
+			fields: fields{
+				rows: [][]driver.Value{},
+			},
+			res: res{
+				storage: map[string]int64{},
+			},
+		},
+		{
+			name: "multiple aggregate types",
+			fields: fields{
+				rows: [][]driver.Value{
+					{"user", int64(4096)},
+					{"org", int64(512)},
+					{"instance", int64(0)},
+				},
+			},
+			res: res{
+				storage: map[string]int64{
+					"user":     4096,
+					"org":      512,
+					"instance": 0,
+				},
+			},
+		},
+		{
+			name: "query fails",
+			fields: fields{
+				err: sql.ErrConnDone,
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			query := mock.ExpectQuery(regexp.QuoteMeta(aggregateTypeStorageQuery)).WithArgs("instance")
+			if tt.fields.err != nil {
+				query.WillReturnError(tt.fields.err)
+				mock.ExpectRollback()
+			} else {
+				rows := mock.NewRows([]string{"aggregate_type", "size"})
+				for _, row := range tt.fields.rows {
+					rows.AddRow(row...)
+				}
+				query.WillReturnRows(rows)
+				mock.ExpectCommit()
+			}
+
+			db := client.crdb()
+			storage, err := db.AggregateTypeStorage(context.Background(), "instance")
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.AggregateTypeStorage() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(storage, tt.res.storage) {
+				t.Errorf("CRDB.AggregateTypeStorage() = %v, want %v", storage, tt.res.storage)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_AggregateTypes(t *testing.T) {
+	type fields struct {
+		rows [][]driver.Value
+		err  error
+	}
+	type args struct {
+		instanceID string
+	}
+	type res struct {
+		types   []string
+		wantErr bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no events",
+			fields: fields{
+				rows: [][]driver.Value{},
+			},
+			args: args{
+				instanceID: "instance",
+			},
+			res: res{
+				types: nil,
+			},
+		},
+		{
+			name: "multiple aggregate types sorted",
+			fields: fields{
+				rows: [][]driver.Value{
+					{"user"},
+					{"org"},
+					{"instance"},
+					{"project"},
+				},
+			},
+			args: args{
+				instanceID: "instance",
+			},
+			res: res{
+				types: []string{"instance", "org", "project", "user"},
+			},
+		},
+		{
+			name: "without instance",
+			fields: fields{
+				rows: [][]driver.Value{
+					{"system"},
+				},
+			},
+			args: args{
+				instanceID: "",
+			},
+			res: res{
+				types: []string{"system"},
+			},
+		},
+		{
+			name: "query fails",
+			fields: fields{
+				err: sql.ErrConnDone,
+			},
+			args: args{
+				instanceID: "instance",
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			query := mock.ExpectQuery(regexp.QuoteMeta(aggregateTypesQuery)).
+				WithArgs(tt.args.instanceID)
+			if tt.fields.err != nil {
+				query.WillReturnError(tt.fields.err)
+				mock.ExpectRollback()
+			} else {
+				rows := mock.NewRows([]string{"aggregate_type"})
+				for _, row := range tt.fields.rows {
+					rows.AddRow(row...)
+				}
+				query.WillReturnRows(rows)
+				mock.ExpectCommit()
+			}
+
+			db := client.crdb()
+			types, err := db.AggregateTypes(context.Background(), tt.args.instanceID)
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.AggregateTypes() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(types, tt.res.types) {
+				t.Errorf("CRDB.AggregateTypes() = %v, want %v", types, tt.res.types)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_Exists(t *testing.T) {
+	type fields struct {
+		exists bool
+		err    error
+	}
+	type res struct {
+		exists  bool
+		wantErr bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		res    res
+	}{
+		{
+			name: "event exists",
+			fields: fields{
+				exists: true,
+			},
+			res: res{
+				exists: true,
+			},
+		},
+		{
+			name: "no event exists",
+			fields: fields{
+				exists: false,
+			},
+			res: res{
+				exists: false,
+			},
+		},
+		{
+			name: "query fails",
+			fields: fields{
+				err: sql.ErrConnDone,
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			query := mock.ExpectQuery(regexp.QuoteMeta(
+				`SELECT EXISTS(SELECT 1 FROM eventstore.events2 WHERE instance_id = $1 AND aggregate_type = $2 AND event_type = ANY($3))`,
+			)).WithArgs("instance", eventstore.AggregateType("user"), []eventstore.EventType{"user.added", "user.changed"})
+			if tt.fields.err != nil {
+				query.WillReturnError(tt.fields.err)
+				mock.ExpectRollback()
+			} else {
+				query.WillReturnRows(mock.NewRows([]string{"exists"}).AddRow(tt.fields.exists))
+				mock.ExpectCommit()
+			}
+
+			db := client.crdb()
+			exists, err := db.Exists(context.Background(),
+				eventstore.NewSearchQueryBuilder(eventstore.ColumnsExists).
+					InstanceID("instance").
+					ForEventTypes("user", "user.added", "user.changed").
+					Builder(),
+			)
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.Exists() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if exists != tt.res.exists {
+				t.Errorf("CRDB.Exists() = %v, want %v", exists, tt.res.exists)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_LatestEvent(t *testing.T) {
+	type fields struct {
+		existingEvents []eventstore.Command
+	}
+	type args struct {
+		aggregateID string
+	}
+	type res struct {
+		sequence  uint64
+		eventType eventstore.EventType
+		wantErr   func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "latest of multiple events",
+			fields: fields{
+				existingEvents: []eventstore.Command{
+					generateEvent(t, "500", func(e *repository.Event) { e.Typ = "test.created" }),
+					generateEvent(t, "500", func(e *repository.Event) { e.Typ = "test.changed" }),
+					generateEvent(t, "500", func(e *repository.Event) { e.Typ = "test.removed" }),
+					generateEvent(t, "501", func(e *repository.Event) { e.Typ = "test.created" }),
+				},
+			},
+			args: args{
+				aggregateID: "500",
+			},
+			res: res{
+				sequence:  3,
+				eventType: "test.removed",
+			},
+		},
+		{
+			name: "no events",
+			fields: fields{
+				existingEvents: []eventstore.Command{},
+			},
+			args: args{
+				aggregateID: "502",
+			},
+			res: res{
+				wantErr: zerrors.IsNotFound,
+			},
+		},
+	}
+	aggregateType := t.Name()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &CRDB{
+				DB: &database.DB{
+					DB:       testCRDBClient,
+					Database: new(testDB),
+				},
+			}
+			if len(tt.fields.existingEvents) > 0 {
+				if _, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.fields.existingEvents...); err != nil {
+					t.Errorf("error in setup = %v", err)
+					return
+				}
+			}
+
+			event, err := db.LatestEvent(context.Background(), aggregateType, tt.args.aggregateID)
+			if tt.res.wantErr != nil {
+				if !tt.res.wantErr(err) {
+					t.Errorf("CRDB.LatestEvent() unexpected error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("CRDB.LatestEvent() error = %v", err)
+				return
+			}
+			if event.Sequence() != tt.res.sequence {
+				t.Errorf("CRDB.LatestEvent() sequence = %d, want %d", event.Sequence(), tt.res.sequence)
+			}
+			if event.Type() != tt.res.eventType {
+				t.Errorf("CRDB.LatestEvent() type = %s, want %s", event.Type(), tt.res.eventType)
+			}
+		})
+	}
+}
+
+func TestCRDB_FilterToReducer_orderDesc(t *testing.T) {
+	db := &CRDB{
+		DB: &database.DB{
+			DB:       testCRDBClient,
+			Database: new(testDB),
+		},
+	}
+	events := make([]eventstore.Command, 20)
+	for i := range events {
+		events[i] = generateEvent(t, "900")
+	}
+	if _, err := db.Push(eventstore.WithoutInstance(context.Background()), events...); err != nil {
+		t.Fatalf("error in setup = %v", err)
+	}
+
+	var sequences []uint64
+	err := query(context.Background(), db,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			OrderDesc().
+			Limit(5).
+			AddQuery().
+			AggregateTypes(eventstore.AggregateType(t.Name())).
+			AggregateIDs("900").
+			Builder(),
+		eventstore.Reducer(func(event eventstore.Event) error {
+			sequences = append(sequences, event.Sequence())
+			return nil
+		}),
+		true,
+	)
+	if err != nil {
+		t.Fatalf("query() error = %v", err)
+	}
+	want := []uint64{20, 19, 18, 17, 16}
+	if !reflect.DeepEqual(sequences, want) {
+		t.Errorf("query() sequences = %v, want %v", sequences, want)
+	}
+}
+
+func TestCRDB_FilterToAggregateMap(t *testing.T) {
+	db := &CRDB{
+		DB: &database.DB{
+			DB:       testCRDBClient,
+			Database: new(testDB),
+		},
+	}
+	_, err := db.Push(eventstore.WithoutInstance(context.Background()),
+		generateEvent(t, "1100", func(e *repository.Event) { e.Typ = "test.created" }),
+		generateEvent(t, "1101", func(e *repository.Event) { e.Typ = "test.created" }),
+		generateEvent(t, "1100", func(e *repository.Event) { e.Typ = "test.changed" }),
+		generateEvent(t, "1101", func(e *repository.Event) { e.Typ = "test.changed" }),
+		generateEvent(t, "1100", func(e *repository.Event) { e.Typ = "test.removed" }),
+	)
+	if err != nil {
+		t.Fatalf("error in setup = %v", err)
+	}
+
+	aggregates, err := db.FilterToAggregateMap(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(eventstore.AggregateType(t.Name())).
+			Builder(),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.FilterToAggregateMap() error = %v", err)
+	}
+
+	want := map[string][]eventstore.EventType{
+		"1100": {"test.created", "test.changed", "test.removed"},
+		"1101": {"test.created", "test.changed"},
+	}
+	if len(aggregates) != len(want) {
+		t.Fatalf("CRDB.FilterToAggregateMap() got %d aggregates, want %d", len(aggregates), len(want))
+	}
+	for aggregateID, wantTypes := range want {
+		events := aggregates[aggregateID]
+		if len(events) != len(wantTypes) {
+			t.Errorf("aggregate %s: got %d events, want %d", aggregateID, len(events), len(wantTypes))
+			continue
+		}
+		for i, event := range events {
+			if event.Type() != wantTypes[i] {
+				t.Errorf("aggregate %s: event %d type = %s, want %s", aggregateID, i, event.Type(), wantTypes[i])
+			}
+			if event.Sequence() != uint64(i+1) {
+				t.Errorf("aggregate %s: event %d sequence = %d, want %d", aggregateID, i, event.Sequence(), i+1)
+			}
+		}
+	}
+}
+
+func TestCRDB_FilterLatestPerAggregate(t *testing.T) {
+	db := &CRDB{
+		DB: &database.DB{
+			DB:       testCRDBClient,
+			Database: new(testDB),
+		},
+	}
+	_, err := db.Push(eventstore.WithoutInstance(context.Background()),
+		generateEvent(t, "1200", func(e *repository.Event) { e.Typ = "test.created" }),
+		generateEvent(t, "1201", func(e *repository.Event) { e.Typ = "test.created" }),
+		generateEvent(t, "1200", func(e *repository.Event) { e.Typ = "test.changed" }),
+		generateEvent(t, "1201", func(e *repository.Event) { e.Typ = "test.changed" }),
+		generateEvent(t, "1200", func(e *repository.Event) { e.Typ = "test.removed" }),
+	)
+	if err != nil {
+		t.Fatalf("error in setup = %v", err)
+	}
+
+	events, err := db.FilterLatestPerAggregate(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(eventstore.AggregateType(t.Name())).
+			Builder(),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.FilterLatestPerAggregate() error = %v", err)
+	}
+
+	want := map[string]eventstore.EventType{
+		"1200": "test.removed",
+		"1201": "test.changed",
+	}
+	if len(events) != len(want) {
+		t.Fatalf("CRDB.FilterLatestPerAggregate() got %d events, want %d", len(events), len(want))
+	}
+	for _, event := range events {
+		if event.Type() != want[event.Aggregate().ID] {
+			t.Errorf("aggregate %s: type = %s, want %s", event.Aggregate().ID, event.Type(), want[event.Aggregate().ID])
+		}
+	}
+}
+
+func TestCRDB_FilterLatestPerAggregate_query(t *testing.T) {
+	client := newMockClient(t)
+	mock := client.mock
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (instance_id, aggregate_type, aggregate_id) created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 WHERE instance_id = $1 AND aggregate_type = $2 ORDER BY instance_id, aggregate_type, aggregate_id, "sequence" DESC LIMIT $3`)).
+		WithArgs("instance", eventstore.AggregateType("session"), uint64(10)).
+		WillReturnRows(
+			mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"}).
+				AddRow(time.Time{}, "session.terminated", 3, 1.3, nil, "creator", "ro", "instance", "session", "1", 1).
+				AddRow(time.Time{}, "session.added", 1, 1.2, nil, "creator", "ro", "instance", "session", "2", 1),
+		)
+	mock.ExpectCommit()
+
+	db := client.crdb()
+	events, err := db.FilterLatestPerAggregate(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			InstanceID("instance").
+			Limit(10).
+			AddQuery().
+			AggregateTypes("session").
+			Builder(),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.FilterLatestPerAggregate() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("CRDB.FilterLatestPerAggregate() got %d events, want 2", len(events))
+	}
+	if events[0].Aggregate().ID != "1" || events[0].Sequence() != 3 {
+		t.Errorf("unexpected first event %v", events[0])
+	}
+	client.assertExpectationsMet(t)
+
+	_, err = db.FilterLatestPerAggregate(context.Background(), eventstore.NewSearchQueryBuilder(eventstore.ColumnsMaxSequence).AddQuery().AggregateTypes("session").Builder())
+	if !zerrors.IsErrorInvalidArgument(err) {
+		t.Errorf("CRDB.FilterLatestPerAggregate() with max sequence columns error = %v, want invalid argument", err)
+	}
+}
+
+func TestCRDB_FilterAsOfSequence(t *testing.T) {
+	client := newMockClient(t)
+	mock := client.mock
+
+	eventRows := func() *sqlmock.Rows {
+		return mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"}).
+			AddRow(time.Time{}, "user.added", 1, 1.1, nil, "creator", "ro", "instance", "user", "1", 1).
+			AddRow(time.Time{}, "user.added", 1, 1.2, nil, "creator", "ro", "instance", "user", "2", 1).
+			AddRow(time.Time{}, "user.changed", 2, 1.2, nil, "creator", "ro", "instance", "user", "1", 1)
+	}
+	// both replays of the snapshot are bound to the same position
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE aggregate_type = $1 AND "position" <= $2 ORDER BY "position", in_tx_order`)).
+			WithArgs(eventstore.AggregateType("user"), 1.2).
+			WillReturnRows(eventRows())
+		mock.ExpectCommit()
+	}
+
+	db := client.crdb()
+	replay := func() []eventstore.Event {
+		events, err := db.FilterAsOfSequence(context.Background(),
+			eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Builder(),
+			1.2,
+		)
+		if err != nil {
+			t.Fatalf("CRDB.FilterAsOfSequence() error = %v", err)
+		}
+		return events
+	}
+	first, second := replay(), replay()
+	if len(first) != 3 {
+		t.Fatalf("CRDB.FilterAsOfSequence() got %d events, want 3", len(first))
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("replays differ: %v != %v", first, second)
+	}
+	client.assertExpectationsMet(t)
+
+	_, err := db.FilterAsOfSequence(context.Background(), eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent), 0)
+	if !zerrors.IsErrorInvalidArgument(err) {
+		t.Errorf("CRDB.FilterAsOfSequence() without sequence error = %v, want invalid argument", err)
+	}
+}
+
+func TestCRDB_Push_cancelled(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		expect  func(sqlmock.Sqlmock)
+		wantErr func(error) bool
+		want    error
+	}{
+		{
+			name:    "cancelled context",
+			ctx:     cancelled,
+			expect:  func(sqlmock.Sqlmock) {},
+			wantErr: zerrors.IsDeadlineExceeded,
+			want:    context.Canceled,
+		},
+		{
+			name: "deadline exceeded during insert",
+			ctx:  eventstore.WithoutInstance(context.Background()),
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).WillReturnError(context.DeadlineExceeded)
+				mock.ExpectRollback()
+			},
+			wantErr: zerrors.IsDeadlineExceeded,
+			want:    context.DeadlineExceeded,
+		},
+		{
+			name: "other errors are not remapped",
+			ctx:  eventstore.WithoutInstance(context.Background()),
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: zerrors.IsInternal,
+			want:    sql.ErrConnDone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+			tt.expect(mock)
+
+			db := client.crdb()
+			_, err := db.Push(tt.ctx, generateEvent(t, "600"))
+			if !tt.wantErr(err) {
+				t.Errorf("CRDB.Push() unexpected error type = %v", err)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("CRDB.Push() error = %v, want %v", err, tt.want)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+type payloadCommand struct {
+	*repository.Event
+	payload any
+}
+
+func (c *payloadCommand) Payload() any {
+	return c.payload
+}
+
+func TestCRDB_Push_payloadValidation(t *testing.T) {
+	eventType := eventstore.EventType(t.Name() + ".added")
+	eventstore.RegisterPayloadValidator(eventType, func(payload []byte) error {
+		var fields map[string]any
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return err
+		}
+		if _, ok := fields["name"]; !ok {
+			return errors.New("name is required")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name    string
+		command eventstore.Command
+		expect  func(sqlmock.Sqlmock)
+		wantErr func(error) bool
+	}{
+		{
+			name: "missing required field",
+			command: &payloadCommand{
+				Event:   generateEvent(t, "800", func(e *repository.Event) { e.Typ = eventType }),
+				payload: map[string]any{"other": "value"},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectRollback()
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name: "valid payload",
+			command: &payloadCommand{
+				Event:   generateEvent(t, "801", func(e *repository.Event) { e.Typ = eventType }),
+				payload: map[string]any{"name": "value"},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+					WillReturnRows(
+						mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+							AddRow("id", 1, time.Now(), "ro", "instance"),
+					)
+				expectPushCommit(mock)
+			},
+		},
+		{
+			name:    "no validator registered",
+			command: generateEvent(t, "802"),
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+					WillReturnRows(
+						mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+							AddRow("id", 1, time.Now(), "ro", "instance"),
+					)
+				expectPushCommit(mock)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+			tt.expect(mock)
+
+			db := client.crdb()
+			_, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.command)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("CRDB.Push() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("CRDB.Push() error = %v, wrong type", err)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_Push_aggregateVersion(t *testing.T) {
+	aggregateType := eventstore.AggregateType(t.Name())
+	eventstore.RegisterAggregateVersion(aggregateType, "v2")
+
+	tests := []struct {
+		name    string
+		version eventstore.Version
+		expect  func(sqlmock.Sqlmock)
+		wantErr func(error) bool
+	}{
+		{
+			name:    "downgrade",
+			version: "v1",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectRollback()
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:    "current version",
+			version: "v2",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+					WillReturnRows(
+						mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+							AddRow("id", 1, time.Now(), "ro", "instance"),
+					)
+				expectPushCommit(mock)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+			tt.expect(mock)
+
+			command := generateEvent(t, "950")
+			command.AggregateType = aggregateType
+			command.Version = tt.version
+
+			db := client.crdb()
+			_, err := db.Push(eventstore.WithoutInstance(context.Background()), command)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("CRDB.Push() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("CRDB.Push() error = %v, wrong type", err)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_Push_creationDate(t *testing.T) {
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Millisecond)
+	tests := []struct {
+		name          string
+		creationDate  string
+		timestampFunc string
+		dates         []time.Time
+		wantSameDate  bool
+	}{
+		{
+			name:          "default",
+			creationDate:  eventstore.CreationDateDefault,
+			timestampFunc: "hlc_to_timestamp(cluster_logical_timestamp())",
+			dates:         []time.Time{first, first},
+			wantSameDate:  true,
+		},
+		{
+			name:          "statement",
+			creationDate:  eventstore.CreationDateStatement,
+			timestampFunc: "statement_timestamp()",
+			dates:         []time.Time{first, second},
+			wantSameDate:  false,
+		},
+		{
+			name:          "transaction",
+			creationDate:  eventstore.CreationDateTransaction,
+			timestampFunc: "transaction_timestamp()",
+			dates:         []time.Time{first, first},
+			wantSameDate:  true,
+		},
+		{
+			name:          "unknown falls back to default",
+			creationDate:  "unknown",
+			timestampFunc: "hlc_to_timestamp(cluster_logical_timestamp())",
+			dates:         []time.Time{first, first},
+			wantSameDate:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			expectPushBegin(mock)
+			for i, date := range tt.dates {
+				mock.ExpectQuery(regexp.QuoteMeta(" " + tt.timestampFunc + " AS creation_date,")).
+					WillReturnRows(mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+						AddRow("id", i+1, date, "ro", "instance"))
+			}
+			expectPushCommit(mock)
+
+			db := client.crdb()
+			WithCreationDate(tt.creationDate)(db)
+			events, err := db.Push(eventstore.WithoutInstance(context.Background()), generateEvent(t, "1300"), generateEvent(t, "1300"))
+			if err != nil {
+				t.Fatalf("CRDB.Push() unexpected error = %v", err)
+			}
+			if same := events[0].CreatedAt().Equal(events[1].CreatedAt()); same != tt.wantSameDate {
+				t.Errorf("expected same creation date %v, got %v", tt.wantSameDate, same)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+// payloadArg captures the payload passed to the insert statement
+type payloadArg struct {
+	stored []byte
+}
+
+func (a *payloadArg) Match(v driver.Value) bool {
+	a.stored, _ = v.([]byte)
+	return true
+}
+
+func TestCRDB_Push_payloadCompression(t *testing.T) {
+	large := []byte(`{"attributes":"` + strings.Repeat("a", 100) + `"}`)
+	tests := []struct {
+		name           string
+		threshold      int
+		wantCompressed bool
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+		},
+		{
+			name:      "payload at threshold",
+			threshold: len(large),
+		},
+		{
+			name:           "payload above threshold",
+			threshold:      len(large) - 1,
+			wantCompressed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			payload := new(payloadArg)
+			expectPushBegin(mock)
+			mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), payload, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+					AddRow("id", 1, time.Time{}, "ro", "instance"))
+			expectPushCommit(mock)
+
+			db := client.crdb()
+			WithPayloadCompression(tt.threshold)(db)
+			pushed, err := db.Push(eventstore.WithoutInstance(context.Background()), &payloadCommand{
+				Event:   generateEvent(t, "1"),
+				payload: json.RawMessage(large),
+			})
+			if err != nil {
+				t.Fatalf("CRDB.Push() unexpected error = %v", err)
+			}
+			if !bytes.Equal(pushed[0].DataAsBytes(), large) {
+				t.Errorf("pushed event payload = %s, want uncompressed", pushed[0].DataAsBytes())
+			}
+			if compressed := eventstore.IsCompressedPayload(payload.stored); compressed != tt.wantCompressed {
+				t.Errorf("stored payload compressed = %v, want %v", compressed, tt.wantCompressed)
+			}
+			if compressed := len(payload.stored) < len(large); compressed != tt.wantCompressed {
+				t.Errorf("stored payload size = %d, uncompressed size %d", len(payload.stored), len(large))
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE aggregate_type = $1`)).
+				WillReturnRows(mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"}).
+					AddRow(time.Time{}, "test.created", 1, 1.1, payload.stored, "user", "ro", "instance", "user", "1", 1))
+			mock.ExpectCommit()
+
+			var events []eventstore.Event
+			err = db.FilterToReducer(context.Background(),
+				eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AddQuery().
+					AggregateTypes("user").
+					Builder(),
+				func(event eventstore.Event) error {
+					events = append(events, event)
+					return nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("CRDB.FilterToReducer() unexpected error = %v", err)
+			}
+			if len(events) != 1 || !bytes.Equal(events[0].DataAsBytes(), large) {
+				t.Errorf("CRDB.FilterToReducer() payload not restored: %v", events)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+type constraintCommand struct {
+	*repository.Event
+	constraints []*eventstore.UniqueConstraint
+}
+
+func (c *constraintCommand) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return c.constraints
+}
+
+func TestCRDB_Push_multipleInstances(t *testing.T) {
+	client := newMockClient(t)
+	mock := client.mock
+
+	command := func(instanceID, username string) *constraintCommand {
+		return &constraintCommand{
+			Event: generateEvent(t, "1", func(e *repository.Event) {
+				e.InstanceID = instanceID
+			}),
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewAddEventUniqueConstraint("usernames", username, "Errors.Unique"),
+			},
+		}
+	}
+	insertRow := func(sequence int, instanceID string) *sqlmock.Rows {
+		return mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+			AddRow("id", sequence, time.Time{}, "ro", instanceID)
+	}
+	aggregateType := eventstore.AggregateType(t.Name())
+
+	expectPushBegin(mock)
+	// the same aggregate id in different instances are different aggregates
+	mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+		WithArgs(sqlmock.AnyArg(), aggregateType, "1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "ro", "instance-a", 0, sql.NullInt64{}).
+		WillReturnRows(insertRow(1, "instance-a"))
+	mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+		WithArgs(sqlmock.AnyArg(), aggregateType, "1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "ro", "instance-b", 1, sql.NullInt64{}).
+		WillReturnRows(insertRow(1, "instance-b"))
+	mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+		WithArgs(sqlmock.AnyArg(), aggregateType, "1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "ro", "instance-a", 2, sql.NullInt64{Int64: 1, Valid: true}).
+		WillReturnRows(insertRow(2, "instance-a"))
+	// unique constraints are added to the instance of their command
+	mock.ExpectExec(regexp.QuoteMeta(uniqueInsert)).
+		WithArgs("usernames", "gigi", "instance-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(uniqueInsert)).
+		WithArgs("usernames", "gigi", "instance-b").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(uniqueInsert)).
+		WithArgs("usernames", "rocky", "instance-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPushCommit(mock)
+
+	db := client.crdb()
+	events, err := db.Push(authz.WithInstanceID(context.Background(), "instance-a"),
+		command("", "gigi"),
+		command("instance-b", "gigi"),
+		command("instance-a", "rocky"),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.Push() unexpected error = %v", err)
+	}
+	want := []struct {
+		instanceID string
+		sequence   uint64
+	}{
+		{"instance-a", 1},
+		{"instance-b", 1},
+		{"instance-a", 2},
+	}
+	for i, event := range events {
+		if event.Aggregate().InstanceID != want[i].instanceID || event.Sequence() != want[i].sequence {
+			t.Errorf("event %d stored in %s with sequence %d, want %s with sequence %d", i, event.Aggregate().InstanceID, event.Sequence(), want[i].instanceID, want[i].sequence)
+		}
+	}
+	client.assertExpectationsMet(t)
+}
+
+func TestCRDB_Push_importMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		importMode bool
+	}{
+		{
+			name:       "constraints enforced",
+			importMode: false,
+		},
+		{
+			name:       "constraints skipped in import mode",
+			importMode: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			expectPushBegin(mock)
+			mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+				WillReturnRows(mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+					AddRow("id", 1, time.Time{}, "ro", "instance"))
+			if !tt.importMode {
+				mock.ExpectExec(regexp.QuoteMeta(uniqueInsert)).
+					WithArgs("usernames", "gigi", "instance").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			expectPushCommit(mock)
+
+			ctx := authz.WithInstanceID(context.Background(), "instance")
+			if tt.importMode {
+				ctx = eventstore.WithImportMode(ctx)
+			}
+			db := client.crdb()
+			_, err := db.Push(ctx, &constraintCommand{
+				Event: generateEvent(t, "1"),
+				constraints: []*eventstore.UniqueConstraint{
+					eventstore.NewAddEventUniqueConstraint("usernames", "gigi", "Errors.Unique"),
+				},
+			})
+			if err != nil {
+				t.Fatalf("CRDB.Push() unexpected error = %v", err)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_FilterOne(t *testing.T) {
+	row := func(rows *sqlmock.Rows, aggregateID string) *sqlmock.Rows {
+		return rows.AddRow(time.Time{}, "idp.intent.started", 1, 1.1, nil, "creator", "ro", "instance", "idpintent", aggregateID, 1)
+	}
+	tests := []struct {
+		name    string
+		rows    []string
+		wantErr func(error) bool
+	}{
+		{
+			name:    "none",
+			wantErr: zerrors.IsNotFound,
+		},
+		{
+			name: "one",
+			rows: []string{"1"},
+		},
+		{
+			name:    "multiple",
+			rows:    []string{"1", "2"},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			rows := mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"})
+			for _, aggregateID := range tt.rows {
+				rows = row(rows, aggregateID)
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE instance_id = $1 AND aggregate_type = $2 AND event_type = $3`)).
+				WithArgs("instance", eventstore.AggregateType("idpintent"), eventstore.EventType("idp.intent.started"), uint64(2)).
+				WillReturnRows(rows)
+			mock.ExpectCommit()
+
+			db := client.crdb()
+			event, err := db.FilterOne(context.Background(),
+				eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					InstanceID("instance").
+					AddQuery().
+					AggregateTypes("idpintent").
+					EventTypes("idp.intent.started").
+					Builder(),
+			)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("CRDB.FilterOne() unexpected error = %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("CRDB.FilterOne() error = %v", err)
+			} else if event.Aggregate().ID != "1" {
+				t.Errorf("unexpected event %v", event)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+// txOptionsConn records the options of the transactions begun on the mocked connection
+type txOptionsConn struct {
+	driver.Conn
+	opts *driver.TxOptions
+}
+
+func (c *txOptionsConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.opts = &opts
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *txOptionsConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *txOptionsConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+type txOptionsConnector struct {
+	conn *txOptionsConn
+	drv  driver.Driver
+}
+
+func (c *txOptionsConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c *txOptionsConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+func TestCRDB_Push_txOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *sql.TxOptions
+		wantOpts driver.TxOptions
+		wantErr  func(error) bool
+	}{
+		{
+			name:     "default",
+			wantOpts: driver.TxOptions{},
+		},
+		{
+			name:     "isolation level",
+			opts:     &sql.TxOptions{Isolation: sql.LevelReadCommitted},
+			wantOpts: driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelReadCommitted)},
+		},
+		{
+			name:    "read-only rejected",
+			opts:    &sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly: true},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.NewWithDSN("push_tx_options_" + tt.name)
+			if err != nil {
+				t.Fatalf("unable to create mock client: %v", err)
+			}
+			defer client.Close()
+			mockConn, err := client.Driver().Open("push_tx_options_" + tt.name)
+			if err != nil {
+				t.Fatalf("unable to open mock connection: %v", err)
+			}
+			conn := &txOptionsConn{Conn: mockConn}
+			txClient := sql.OpenDB(&txOptionsConnector{conn: conn, drv: client.Driver()})
+			defer txClient.Close()
+
+			if tt.wantErr == nil {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+					WillReturnRows(mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+						AddRow("id", 1, time.Time{}, "ro", "instance"))
+				expectPushCommit(mock)
+			}
+
+			ctx := authz.WithInstanceID(context.Background(), "instance")
+			if tt.opts != nil {
+				ctx = eventstore.WithPushTxOptions(ctx, tt.opts)
+			}
+			db := &CRDB{DB: &database.DB{DB: txClient}}
+			_, err = db.Push(ctx, generateEvent(t, "1"))
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("CRDB.Push() unexpected error = %v", err)
+				}
+				if conn.opts != nil {
+					t.Error("CRDB.Push() must not begin a transaction")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CRDB.Push() unexpected error = %v", err)
+			}
+			if conn.opts == nil || *conn.opts != tt.wantOpts {
+				t.Errorf("transaction options = %+v, want %+v", conn.opts, tt.wantOpts)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("not all expectations met: %v", err)
+			}
+		})
+	}
+}
+
+type updateCommand struct {
+	*repository.Event
+}
+
+func (c *updateCommand) RequiresExistingAggregate() bool {
+	return true
+}
+
+func TestCRDB_Push_requiresExistingAggregate(t *testing.T) {
+	insertedRow := func(mock sqlmock.Sqlmock) *sqlmock.Rows {
+		return mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+			AddRow("id", 1, time.Now(), "ro", "instance")
+	}
+	tests := []struct {
+		name     string
+		commands []eventstore.Command
+		expect   func(sqlmock.Sqlmock)
+		wantErr  func(error) bool
+	}{
+		{
+			name:     "orphan update",
+			commands: []eventstore.Command{&updateCommand{generateEvent(t, "1200")}},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(aggregateExistsQuery)).
+					WithArgs(eventstore.AggregateType(t.Name()), "1200", "").
+					WillReturnRows(mock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			wantErr: zerrors.IsNotFound,
+		},
+		{
+			name:     "update of existing aggregate",
+			commands: []eventstore.Command{&updateCommand{generateEvent(t, "1201")}},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(aggregateExistsQuery)).
+					WithArgs(eventstore.AggregateType(t.Name()), "1201", "").
+					WillReturnRows(mock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).WillReturnRows(insertedRow(mock))
+				expectPushCommit(mock)
+			},
+		},
+		{
+			name: "update after creation in same push",
+			commands: []eventstore.Command{
+				generateEvent(t, "1202"),
+				&updateCommand{generateEvent(t, "1202")},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPushBegin(mock)
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).WillReturnRows(insertedRow(mock))
+				mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).WillReturnRows(insertedRow(mock))
+				expectPushCommit(mock)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+			tt.expect(mock)
+
+			db := client.crdb()
+			_, err := db.Push(eventstore.WithoutInstance(context.Background()), tt.commands...)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("CRDB.Push() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Errorf("CRDB.Push() error = %v, wrong type", err)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_Push_editorUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		command eventstore.Command
+		want    string
+	}{
+		{
+			name: "explicit creator",
+			ctx:  authz.SetCtxData(context.Background(), authz.CtxData{UserID: "ctx-user"}),
+			command: generateEvent(t, "900", func(e *repository.Event) {
+				e.EditorUser = "creator"
+			}),
+			want: "creator",
+		},
+		{
+			name: "creator from context",
+			ctx:  authz.SetCtxData(context.Background(), authz.CtxData{UserID: "ctx-user"}),
+			command: generateEvent(t, "901", func(e *repository.Event) {
+				e.EditorUser = ""
+			}),
+			want: "ctx-user",
+		},
+		{
+			name: "system fallback",
+			ctx:  context.Background(),
+			command: generateEvent(t, "902", func(e *repository.Event) {
+				e.EditorUser = ""
+			}),
+			want: authz.SystemEditorID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+
+			expectPushBegin(mock)
+			mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+				WithArgs(
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					tt.want,
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+				).
+				WillReturnRows(
+					mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+						AddRow("id", 1, time.Now(), "ro", "instance"),
+				)
+			expectPushCommit(mock)
+
+			db := client.crdb()
+			events, err := db.Push(eventstore.WithoutInstance(tt.ctx), tt.command)
+			if err != nil {
+				t.Errorf("CRDB.Push() error = %v", err)
+				return
+			}
+			if creator := events[0].Creator(); creator != tt.want {
+				t.Errorf("CRDB.Push() creator = %q, want %q", creator, tt.want)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_Push_sameAggregate(t *testing.T) {
+	client := newMockClient(t)
+	mock := client.mock
+
+	expectPushBegin(mock)
+	// the first event reads the previous sequence from the database,
+	// the following events use the sequence of the event pushed before
+	for i, previous := range []sql.NullInt64{{}, {Int64: 1, Valid: true}, {Int64: 2, Valid: true}} {
+		mock.ExpectQuery(regexp.QuoteMeta(crdbInsert)).
+			WithArgs(
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				"1000",
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				i,
+				previous,
+			).
+			WillReturnRows(
+				mock.NewRows([]string{"id", "event_sequence", "creation_date", "resource_owner", "instance_id"}).
+					AddRow("id", i+1, time.Now(), "ro", "instance"),
+			)
+	}
+	expectPushCommit(mock)
+
+	db := client.crdb()
+	events, err := db.Push(eventstore.WithoutInstance(context.Background()),
+		generateEvent(t, "1000"),
+		generateEvent(t, "1000"),
+		generateEvent(t, "1000"),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.Push() error = %v", err)
+	}
+	for i, event := range events {
+		if event.Sequence() != uint64(i+1) {
+			t.Errorf("event %d: sequence = %d, want %d", i, event.Sequence(), i+1)
+		}
+	}
+	client.assertExpectationsMet(t)
+}
+
+func TestCRDB_BackfillInstanceID(t *testing.T) {
+	type args struct {
+		instanceID string
+		batchSize  int
+	}
+	type res struct {
+		updated int64
+		wantErr func(error) bool
+	}
+	tests := []struct {
+		name   string
+		args   args
+		expect func(sqlmock.Sqlmock)
+		res    res
+	}{
+		{
+			name: "multiple batches",
+			args: args{instanceID: "instance", batchSize: 2},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			res: res{updated: 3},
+		},
+		{
+			name: "last batch full",
+			args: args{instanceID: "instance", batchSize: 2},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			res: res{updated: 2},
+		},
+		{
+			name: "no events without instance id",
+			args: args{instanceID: "instance", batchSize: 2},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			res: res{updated: 0},
+		},
+		{
+			name: "failed batch returns updated events",
+			args: args{instanceID: "instance", batchSize: 2},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(backfillInstanceIDStmt)).WithArgs("instance", 2).WillReturnError(sql.ErrConnDone)
+			},
+			res: res{
+				updated: 2,
+				wantErr: zerrors.IsInternal,
+			},
+		},
+		{
+			name:   "invalid batch size",
+			args:   args{instanceID: "instance", batchSize: 0},
+			expect: func(sqlmock.Sqlmock) {},
+			res: res{
+				wantErr: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name:   "missing instance id",
+			args:   args{batchSize: 2},
+			expect: func(sqlmock.Sqlmock) {},
+			res: res{
+				wantErr: zerrors.IsErrorInvalidArgument,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t)
+			mock := client.mock
+			tt.expect(mock)
+
+			db := client.crdb()
+			updated, err := db.BackfillInstanceID(context.Background(), tt.args.instanceID, tt.args.batchSize)
+			if tt.res.wantErr == nil && err != nil {
+				t.Errorf("CRDB.BackfillInstanceID() unexpected error = %v", err)
+			}
+			if tt.res.wantErr != nil && !tt.res.wantErr(err) {
+				t.Errorf("CRDB.BackfillInstanceID() error = %v, wrong type", err)
+			}
+			if updated != tt.res.updated {
+				t.Errorf("CRDB.BackfillInstanceID() updated = %d, want %d", updated, tt.res.updated)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_handleUniqueConstraints(t *testing.T) {
+	type want struct {
+		stmt  string
+		field string
+	}
+	tests := []struct {
+		name       string
+		constraint *eventstore.UniqueConstraint
+		want       want
+	}{
+		{
+			name:       "add lowercases field",
+			constraint: eventstore.NewAddEventUniqueConstraint("type", "User", "Errors.Unique"),
+			want: want{
+				stmt:  uniqueInsert,
+				field: "user",
+			},
+		},
+		{
+			name: "add case sensitive",
+			constraint: &eventstore.UniqueConstraint{
+				UniqueType:    "type",
+				UniqueField:   "User",
+				Action:        eventstore.UniqueConstraintAdd,
+				CaseSensitive: true,
+			},
+			want: want{
+				stmt:  uniqueInsert,
+				field: "User",
+			},
+		},
+		{
+			name:       "remove lowercases field",
+			constraint: eventstore.NewRemoveUniqueConstraint("type", "User"),
+			want: want{
+				stmt:  uniqueDelete,
+				field: "user",
+			},
+		},
+		{
+			name: "remove case sensitive",
+			constraint: &eventstore.UniqueConstraint{
+				UniqueType:    "type",
+				UniqueField:   "User",
+				Action:        eventstore.UniqueConstraintRemove,
+				CaseSensitive: true,
+			},
+			want: want{
+				stmt:  uniqueDelete,
+				field: "User",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClientWithMatcher(t, sqlmock.QueryMatcherEqual)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			mock.ExpectExec(tt.want.stmt).
+				WithArgs("type", tt.want.field, "instance").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, err := client.client.Begin()
+			if err != nil {
+				t.Fatalf("unable to begin transaction: %v", err)
+			}
+
+			db := client.crdb()
+			if err := db.handleUniqueConstraints(context.Background(), tx, "instance", tt.constraint); err != nil {
+				t.Errorf("CRDB.handleUniqueConstraints() unexpected error = %v", err)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_handleUniqueConstraints_violation(t *testing.T) {
+	client := newMockClientWithMatcher(t, sqlmock.QueryMatcherEqual)
+	mock := client.mock
+
+	mock.ExpectBegin()
+	mock.ExpectExec(uniqueInsert).
+		WithArgs("type", "user", "instance").
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+
+	tx, err := client.client.Begin()
+	if err != nil {
+		t.Fatalf("unable to begin transaction: %v", err)
+	}
+
+	db := client.crdb()
+	err = db.handleUniqueConstraints(
+		context.Background(),
+		tx,
+		"instance",
+		eventstore.NewAddEventUniqueConstraint("type", "User", "Errors.Unique"),
+	)
+	if !zerrors.IsErrorAlreadyExists(err) {
+		t.Errorf("CRDB.handleUniqueConstraints() error = %v, want already exists", err)
+	}
+	violation := new(eventstore.UniqueConstraintViolationError)
+	if !errors.As(err, &violation) {
+		t.Fatalf("CRDB.handleUniqueConstraints() error = %T, want %T", err, violation)
+	}
+	if violation.UniqueType != "type" || violation.UniqueField != "user" {
+		t.Errorf("collided constraint = %s/%s, want type/user", violation.UniqueType, violation.UniqueField)
+	}
+	if violation.GetMessage() != "Errors.Unique" {
+		t.Errorf("error message = %s, want Errors.Unique", violation.GetMessage())
+	}
+	client.assertExpectationsMet(t)
+}
+
+func TestCRDB_UniqueConstraintsExist(t *testing.T) {
+	type fields struct {
+		rows [][]driver.Value
+		err  error
+	}
+	type res struct {
+		exists  map[string]bool
+		wantErr bool
+	}
+	tests := []struct {
+		name        string
+		constraints []*eventstore.UniqueConstraint
+		fields      fields
+		res         res
+	}{
+		{
+			name: "no constraints",
+			res: res{
+				exists: map[string]bool{},
+			},
+		},
+		{
+			name: "taken and free fields",
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewAddEventUniqueConstraint("usernames", "Gigi", "Errors.Unique"),
+				eventstore.NewAddEventUniqueConstraint("usernames", "Rocky", "Errors.Unique"),
+				{
+					UniqueType:    "domains",
+					UniqueField:   "Zitadel.ch",
+					Action:        eventstore.UniqueConstraintAdd,
+					CaseSensitive: true,
+				},
+			},
+			fields: fields{
+				rows: [][]driver.Value{
+					{"usernames", "gigi"},
+					{"domains", "Zitadel.ch"},
+				},
+			},
+			res: res{
+				exists: map[string]bool{
+					"usernames:Gigi":     true,
+					"usernames:Rocky":    false,
+					"domains:Zitadel.ch": true,
+				},
+			},
+		},
+		{
+			name: "global constraint",
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewAddGlobalUniqueConstraint("instance_domain", "Zitadel.Cloud", "Errors.Unique"),
+				eventstore.NewAddEventUniqueConstraint("usernames", "gigi", "Errors.Unique"),
+			},
+			fields: fields{
+				rows: [][]driver.Value{
+					{"instance_domain", "zitadel.cloud"},
+				},
+			},
+			res: res{
+				exists: map[string]bool{
+					"instance_domain:Zitadel.Cloud": true,
+					"usernames:gigi":                false,
+				},
+			},
+		},
+		{
+			name: "query fails",
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewAddEventUniqueConstraint("usernames", "gigi", "Errors.Unique"),
+			},
+			fields: fields{
+				err: sql.ErrConnDone,
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClientWithMatcher(t, sqlmock.QueryMatcherEqual)
+			mock := client.mock
+
+			if len(tt.constraints) > 0 {
+				placeholders := make([]string, len(tt.constraints))
+				args := make([]driver.Value, 0, len(tt.constraints)*3)
+				for i, constraint := range tt.constraints {
+					placeholders[i] = fmt.Sprintf("($%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3)
+					instanceID := "instance"
+					if constraint.IsGlobal {
+						instanceID = ""
+					}
+					field := constraint.UniqueField
+					if !constraint.CaseSensitive {
+						field = strings.ToLower(field)
+					}
+					args = append(args, instanceID, constraint.UniqueType, field)
+				}
+				mock.ExpectBegin()
+				query := mock.ExpectQuery(fmt.Sprintf(uniqueExistsQuery, strings.Join(placeholders, ", "))).WithArgs(args...)
+				if tt.fields.err != nil {
+					query.WillReturnError(tt.fields.err)
+					mock.ExpectRollback()
+				} else {
+					rows := mock.NewRows([]string{"unique_type", "unique_field"})
+					for _, row := range tt.fields.rows {
+						rows.AddRow(row...)
+					}
+					query.WillReturnRows(rows)
+					mock.ExpectCommit()
+				}
+			}
+
+			db := client.crdb()
+			exists, err := db.UniqueConstraintsExist(authz.WithInstanceID(context.Background(), "instance"), tt.constraints...)
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.UniqueConstraintsExist() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(exists, tt.res.exists) {
+				t.Errorf("CRDB.UniqueConstraintsExist() = %v, want %v", exists, tt.res.exists)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_ListUniqueConstraints(t *testing.T) {
+	type fields struct {
+		rows [][]driver.Value
+		err  error
+	}
+	type args struct {
+		instanceID string
+		uniqueType string
+	}
+	type res struct {
+		constraints []*eventstore.UniqueConstraint
+		wantErr     bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no constraints",
+			fields: fields{
+				rows: [][]driver.Value{},
+			},
+			args: args{
+				instanceID: "instance",
+			},
+			res: res{
+				constraints: nil,
+			},
+		},
+		{
+			name: "constraints of instance",
+			fields: fields{
+				rows: [][]driver.Value{
+					{"domains", "zitadel.ch"},
+					{"usernames", "gigi"},
+				},
+			},
+			args: args{
+				instanceID: "instance",
+			},
+			res: res{
+				constraints: []*eventstore.UniqueConstraint{
+					{UniqueType: "domains", UniqueField: "zitadel.ch", Action: eventstore.UniqueConstraintAdd},
+					{UniqueType: "usernames", UniqueField: "gigi", Action: eventstore.UniqueConstraintAdd},
+				},
+			},
+		},
+		{
+			name: "constraints of type",
+			fields: fields{
+				rows: [][]driver.Value{
+					{"usernames", "gigi"},
+				},
+			},
+			args: args{
+				instanceID: "instance",
+				uniqueType: "usernames",
+			},
+			res: res{
+				constraints: []*eventstore.UniqueConstraint{
+					{UniqueType: "usernames", UniqueField: "gigi", Action: eventstore.UniqueConstraintAdd},
+				},
+			},
+		},
+		{
+			name: "query fails",
+			fields: fields{
+				err: sql.ErrConnDone,
+			},
+			args: args{
+				instanceID: "instance",
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClientWithMatcher(t, sqlmock.QueryMatcherEqual)
+			mock := client.mock
+
+			mock.ExpectBegin()
+			query := mock.ExpectQuery(uniqueListQuery).WithArgs(tt.args.instanceID, tt.args.uniqueType)
+			if tt.fields.err != nil {
+				query.WillReturnError(tt.fields.err)
+				mock.ExpectRollback()
+			} else {
+				rows := mock.NewRows([]string{"unique_type", "unique_field"})
+				for _, row := range tt.fields.rows {
+					rows.AddRow(row...)
+				}
+				query.WillReturnRows(rows)
+				mock.ExpectCommit()
+			}
+
+			db := client.crdb()
+			constraints, err := db.ListUniqueConstraints(context.Background(), tt.args.instanceID, tt.args.uniqueType)
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("CRDB.ListUniqueConstraints() error = %v, wantErr %v", err, tt.res.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(constraints, tt.res.constraints) {
+				t.Errorf("CRDB.ListUniqueConstraints() = %v, want %v", constraints, tt.res.constraints)
+			}
+			client.assertExpectationsMet(t)
+		})
+	}
+}
+
+func TestCRDB_ListUniqueConstraints_pushed(t *testing.T) {
+	db := &CRDB{
+		DB: &database.DB{
+			DB:       testCRDBClient,
+			Database: new(testDB),
+		},
+	}
+	instanceID := t.Name()
+	uniqueType := t.Name() + "_usernames"
+	command := func(aggregateID string, constraints ...*eventstore.UniqueConstraint) *constraintCommand {
+		return &constraintCommand{
+			Event: generateEvent(t, aggregateID, func(e *repository.Event) {
+				e.InstanceID = instanceID
+			}),
+			constraints: constraints,
+		}
+	}
+
+	_, err := db.Push(context.Background(),
+		command("900", eventstore.NewAddEventUniqueConstraint(uniqueType, "Gigi", "Errors.Unique")),
+		command("901", eventstore.NewAddEventUniqueConstraint(uniqueType, "Rocky", "Errors.Unique")),
+		command("902", eventstore.NewAddEventUniqueConstraint(t.Name()+"_domains", "zitadel.ch", "Errors.Unique")),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.Push() error = %v", err)
+	}
+	_, err = db.Push(context.Background(),
+		command("901", eventstore.NewRemoveUniqueConstraint(uniqueType, "Rocky")),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.Push() error = %v", err)
+	}
+
+	constraints, err := db.ListUniqueConstraints(context.Background(), instanceID, uniqueType)
+	if err != nil {
+		t.Fatalf("CRDB.ListUniqueConstraints() error = %v", err)
+	}
+	want := []*eventstore.UniqueConstraint{
+		{UniqueType: uniqueType, UniqueField: "gigi", Action: eventstore.UniqueConstraintAdd},
+	}
+	if !reflect.DeepEqual(constraints, want) {
+		t.Errorf("CRDB.ListUniqueConstraints() = %v, want %v", constraints, want)
+	}
+
+	constraints, err = db.ListUniqueConstraints(context.Background(), instanceID, "")
+	if err != nil {
+		t.Fatalf("CRDB.ListUniqueConstraints() error = %v", err)
+	}
+	if len(constraints) != 2 {
+		t.Errorf("CRDB.ListUniqueConstraints() returned %d constraints, want 2", len(constraints))
+	}
+}
@@ -0,0 +1,98 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ExportedEvent is an event as written by [CRDB.ExportEvents]
+type ExportedEvent struct {
+	InstanceID       string                   `json:"instanceId"`
+	AggregateType    eventstore.AggregateType `json:"aggregateType"`
+	AggregateID      string                   `json:"aggregateId"`
+	AggregateVersion eventstore.Version       `json:"aggregateVersion"`
+	ResourceOwner    string                   `json:"resourceOwner"`
+	EventType        eventstore.EventType     `json:"eventType"`
+	Sequence         uint64                   `json:"sequence"`
+	Position         float64                  `json:"position"`
+	CreatedAt        time.Time                `json:"createdAt"`
+	Creator          string                   `json:"creator"`
+	EditorService    string                   `json:"editorService,omitempty"`
+	Payload          json.RawMessage          `json:"payload,omitempty"`
+}
+
+// ExportRedactor returns the payload of an exported event without the data which must not leave the system, e.g. PII.
+type ExportRedactor func(payload json.RawMessage) (json.RawMessage, error)
+
+// ExportRedactors holds the [ExportRedactor] of each event type.
+// Payloads of event types without redactor are exported unchanged.
+type ExportRedactors map[eventstore.EventType]ExportRedactor
+
+// Register sets the redactor of the event types
+func (r ExportRedactors) Register(redactor ExportRedactor, eventTypes ...eventstore.EventType) {
+	for _, eventType := range eventTypes {
+		r[eventType] = redactor
+	}
+}
+
+func (r ExportRedactors) redact(exported *ExportedEvent) (err error) {
+	redactor, ok := r[exported.EventType]
+	if !ok || len(exported.Payload) == 0 {
+		return nil
+	}
+	exported.Payload, err = redactor(exported.Payload)
+	return err
+}
+
+func exportedEvent(event eventstore.Event) *ExportedEvent {
+	aggregate := event.Aggregate()
+	exported := &ExportedEvent{
+		InstanceID:       aggregate.InstanceID,
+		AggregateType:    aggregate.Type,
+		AggregateID:      aggregate.ID,
+		AggregateVersion: aggregate.Version,
+		ResourceOwner:    aggregate.ResourceOwner,
+		EventType:        event.Type(),
+		Sequence:         event.Sequence(),
+		Position:         event.Position(),
+		CreatedAt:        event.CreatedAt(),
+		Creator:          event.Creator(),
+		Payload:          event.DataAsBytes(),
+	}
+	if servicer, ok := event.(eventstore.EditorServicer); ok {
+		exported.EditorService = servicer.EditorService()
+	}
+	return exported
+}
+
+// ExportEvents writes the events matching the search query to w as newline delimited json, one [ExportedEvent] per line.
+// The events are streamed from the database, so exports of large amounts of events don't need to fit into memory.
+// The payloads are redacted by the [ExportRedactors] set using [WithExportRedactors].
+// count is the number of events written, also if an error occurred.
+func (db *CRDB) ExportEvents(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder, w io.Writer) (count int64, err error) {
+	encoder := json.NewEncoder(w)
+	var redactErr, writeErr error
+	err = db.FilterToReducer(ctx, searchQuery, func(event eventstore.Event) error {
+		exported := exportedEvent(event)
+		if redactErr = db.exportRedactors.redact(exported); redactErr != nil {
+			return redactErr
+		}
+		if writeErr = encoder.Encode(exported); writeErr != nil {
+			return writeErr
+		}
+		count++
+		return nil
+	})
+	if redactErr != nil {
+		return count, zerrors.ThrowInternal(redactErr, "SQL-Ex2rd", "unable to redact event payload")
+	}
+	if writeErr != nil {
+		return count, zerrors.ThrowInternal(writeErr, "SQL-Ex1pw", "unable to write events")
+	}
+	return count, err
+}
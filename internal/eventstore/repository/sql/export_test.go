@@ -0,0 +1,233 @@
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestCRDB_ExportEvents(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock client: %v", err)
+	}
+	defer client.Close()
+
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC)
+	want := []*ExportedEvent{
+		{
+			InstanceID:       "instance",
+			AggregateType:    "user",
+			AggregateID:      "1",
+			AggregateVersion: "v1",
+			ResourceOwner:    "org",
+			EventType:        "user.added",
+			Sequence:         1,
+			Position:         1705917122.0000102,
+			CreatedAt:        createdAt,
+			Creator:          "creator",
+			Payload:          json.RawMessage(`{"userName":"gigi","emails":["gigi@zitadel.com"]}`),
+		},
+		{
+			InstanceID:       "instance",
+			AggregateType:    "user",
+			AggregateID:      "1",
+			AggregateVersion: "v2",
+			ResourceOwner:    "org",
+			EventType:        "user.removed",
+			Sequence:         2,
+			Position:         1705917123.5,
+			CreatedAt:        createdAt.Add(time.Hour),
+			Creator:          "admin",
+		},
+	}
+	rows := mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"})
+	for i, event := range want {
+		rows.AddRow(event.CreatedAt, event.EventType, event.Sequence, event.Position, []byte(event.Payload), event.Creator, event.ResourceOwner, event.InstanceID, event.AggregateType, event.AggregateID, i+1)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE aggregate_type = $1`)).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	db := &CRDB{DB: &database.DB{DB: client, Database: new(testDB)}}
+	var buf bytes.Buffer
+	count, err := db.ExportEvents(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&buf,
+	)
+	if err != nil {
+		t.Fatalf("CRDB.ExportEvents() unexpected error = %v", err)
+	}
+	if count != int64(len(want)) {
+		t.Errorf("CRDB.ExportEvents() count = %d, want %d", count, len(want))
+	}
+
+	var got []*ExportedEvent
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		event := new(ExportedEvent)
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			t.Fatalf("unable to parse exported line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, event)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exported events differ\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectations met: %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCRDB_ExportEvents_writeErr(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock client: %v", err)
+	}
+	defer client.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE aggregate_type = $1`)).
+		WillReturnRows(mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"}).
+			AddRow(time.Time{}, "user.added", 1, 1.1, nil, "creator", "org", "instance", "user", "1", 1))
+	mock.ExpectRollback()
+
+	db := &CRDB{DB: &database.DB{DB: client, Database: new(testDB)}}
+	count, err := db.ExportEvents(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		failingWriter{},
+	)
+	if err == nil {
+		t.Error("CRDB.ExportEvents() expected error")
+	}
+	if count != 0 {
+		t.Errorf("CRDB.ExportEvents() count = %d, want 0", count)
+	}
+}
+
+func maskEmail(payload json.RawMessage) (json.RawMessage, error) {
+	data := make(map[string]any)
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	if _, ok := data["email"]; ok {
+		data["email"] = "***"
+	}
+	return json.Marshal(data)
+}
+
+func TestCRDB_ExportEvents_redacted(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock client: %v", err)
+	}
+	defer client.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE aggregate_type = $1`)).
+		WillReturnRows(mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"}).
+			AddRow(time.Time{}, "user.human.added", 1, 1.1, []byte(`{"userName":"gigi","email":"gigi@zitadel.com"}`), "creator", "org", "instance", "user", "1", 1).
+			AddRow(time.Time{}, "user.human.email.changed", 2, 1.2, []byte(`{"email":"gigi@zitadel.ch"}`), "creator", "org", "instance", "user", "1", 1).
+			AddRow(time.Time{}, "user.human.email.verified", 3, 1.3, nil, "creator", "org", "instance", "user", "1", 1).
+			AddRow(time.Time{}, "user.username.changed", 4, 1.4, []byte(`{"userName":"gigi@zitadel.com"}`), "creator", "org", "instance", "user", "1", 1))
+	mock.ExpectCommit()
+
+	redactors := make(ExportRedactors)
+	redactors.Register(maskEmail, "user.human.added", "user.human.email.changed", "user.human.email.verified")
+	db := NewCRDB(&database.DB{DB: client, Database: new(testDB)}, WithExportRedactors(redactors))
+	var buf bytes.Buffer
+	count, err := db.ExportEvents(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&buf,
+	)
+	if err != nil {
+		t.Fatalf("CRDB.ExportEvents() unexpected error = %v", err)
+	}
+	if count != 4 {
+		t.Errorf("CRDB.ExportEvents() count = %d, want 4", count)
+	}
+
+	want := []string{
+		`{"email":"***","userName":"gigi"}`,
+		`{"email":"***"}`,
+		``,
+		// event types without redactor are not changed
+		`{"userName":"gigi@zitadel.com"}`,
+	}
+	var got []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		event := new(ExportedEvent)
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			t.Fatalf("unable to parse exported line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, string(event.Payload))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exported payloads differ\ngot:  %q\nwant: %q", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectations met: %v", err)
+	}
+}
+
+func TestCRDB_ExportEvents_redactErr(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock client: %v", err)
+	}
+	defer client.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM eventstore.events2 WHERE aggregate_type = $1`)).
+		WillReturnRows(mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision"}).
+			AddRow(time.Time{}, "user.human.added", 1, 1.1, []byte(`{"email":"gigi@zitadel.com"}`), "creator", "org", "instance", "user", "1", 1))
+	mock.ExpectRollback()
+
+	redactors := make(ExportRedactors)
+	redactors.Register(func(json.RawMessage) (json.RawMessage, error) {
+		return nil, errors.New("invalid payload")
+	}, "user.human.added")
+	db := NewCRDB(&database.DB{DB: client, Database: new(testDB)}, WithExportRedactors(redactors))
+	var buf bytes.Buffer
+	count, err := db.ExportEvents(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&buf,
+	)
+	if err == nil {
+		t.Error("CRDB.ExportEvents() expected error")
+	}
+	if count != 0 || buf.Len() > 0 {
+		t.Errorf("CRDB.ExportEvents() count = %d, written %q, want nothing", count, buf.String())
+	}
+}
@@ -0,0 +1,149 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// DefaultPruneSafetyWindow is the minimal age of the events deleted by [CRDB.PruneEvents]
+	DefaultPruneSafetyWindow = 30 * 24 * time.Hour
+
+	// pruneBatchSize is the maximum count of aggregates pruned in a single transaction
+	pruneBatchSize = 1000
+
+	// prunableEventsCondition matches events created before $1 of aggregates without newer events,
+	// so aggregates are always pruned as a whole
+	prunableEventsCondition = " WHERE e.created_at < $1" +
+		" AND NOT EXISTS (SELECT 1 FROM eventstore.events2 n" +
+		" WHERE n.instance_id = e.instance_id AND n.aggregate_type = e.aggregate_type AND n.aggregate_id = e.aggregate_id" +
+		" AND n.created_at >= $1)"
+
+	pruneCountQuery = "SELECT COUNT(*) FROM eventstore.events2 e" + prunableEventsCondition
+
+	pruneAggregatesQuery = "SELECT DISTINCT e.instance_id, e.aggregate_type, e.aggregate_id FROM eventstore.events2 e" + prunableEventsCondition +
+		" LIMIT $2"
+
+	pruneStmt = "DELETE FROM eventstore.events2 WHERE created_at < $1 AND (instance_id, aggregate_type, aggregate_id) IN (%s)"
+)
+
+// prunedAggregate identifies an aggregate whose events are pruned
+type prunedAggregate struct {
+	instanceID    string
+	aggregateType string
+	aggregateID   string
+}
+
+// PruneEvents deletes the events created before the cutoff, use [CRDB.ExportEvents] to archive them first.
+// Only aggregates without events newer than the cutoff are pruned, so the event streams of the remaining aggregates stay complete.
+// Unique constraints are not touched, the constraints of pruned aggregates stay reserved.
+//
+// The events are deleted in batches of whole aggregates, each batch is deleted in its own transaction,
+// so no aggregate is left with a partial event stream.
+// If the prune is interrupted it continues with the remaining aggregates on the next call.
+// The cutoff must be older than the safety window, see [WithPruneSafetyWindow].
+// If dryRun is set, the count of events which would be deleted is returned and nothing is deleted.
+func (db *CRDB) PruneEvents(ctx context.Context, before time.Time, dryRun bool) (deleted int64, err error) {
+	window := DefaultPruneSafetyWindow
+	if db.pruneSafetyWindow != nil {
+		window = *db.pruneSafetyWindow
+	}
+	if before.After(time.Now().Add(-window)) {
+		return 0, zerrors.ThrowPreconditionFailedf(nil, "SQL-Pr1nw", "cutoff %s is within the safety window of %s", before, window)
+	}
+
+	if dryRun {
+		err = db.DB.QueryRowContext(ctx, func(row *sql.Row) error {
+			return row.Scan(&deleted)
+		}, pruneCountQuery, before)
+		if err != nil {
+			return 0, zerrors.ThrowInternal(err, "SQL-Pr2nc", "unable to count prunable events")
+		}
+		return deleted, nil
+	}
+
+	for {
+		aggregates, rows, err := db.pruneBatch(ctx, before)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += rows
+		logging.WithFields("before", before, "aggregates", aggregates, "batch", rows, "deleted", deleted).Info("events pruned")
+		if aggregates < pruneBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// pruneBatch deletes the events of up to [pruneBatchSize] prunable aggregates in a single transaction
+// and returns the count of pruned aggregates and deleted events
+func (db *CRDB) pruneBatch(ctx context.Context, before time.Time) (aggregates int, deleted int64, err error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, zerrors.ThrowInternal(err, "SQL-Pr5nt", "unable to begin prune transaction")
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			logging.OnError(rollbackErr).Debug("unable to rollback prune transaction")
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = zerrors.ThrowInternal(err, "SQL-Pr6nc", "unable to commit prune transaction")
+		}
+	}()
+
+	pruned, err := prunableAggregates(ctx, tx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(pruned) == 0 {
+		return 0, 0, nil
+	}
+
+	args := make([]any, 0, len(pruned)*3+1)
+	args = append(args, before)
+	placeholders := make([]string, len(pruned))
+	for i, aggregate := range pruned {
+		placeholders[i] = fmt.Sprintf("($%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3)
+		args = append(args, aggregate.instanceID, aggregate.aggregateType, aggregate.aggregateID)
+	}
+	res, err := tx.ExecContext(ctx, fmt.Sprintf(pruneStmt, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return 0, 0, zerrors.ThrowInternal(err, "SQL-Pr3nd", "unable to prune events")
+	}
+	deleted, err = res.RowsAffected()
+	if err != nil {
+		return 0, 0, zerrors.ThrowInternal(err, "SQL-Pr4nd", "unable to prune events")
+	}
+	return len(pruned), deleted, nil
+}
+
+// prunableAggregates returns up to [pruneBatchSize] aggregates without events newer than before
+func prunableAggregates(ctx context.Context, tx *sql.Tx, before time.Time) (_ []*prunedAggregate, err error) {
+	rows, err := tx.QueryContext(ctx, pruneAggregatesQuery, before, pruneBatchSize)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Pr7na", "unable to query prunable aggregates")
+	}
+	defer rows.Close()
+
+	aggregates := make([]*prunedAggregate, 0, pruneBatchSize)
+	for rows.Next() {
+		aggregate := new(prunedAggregate)
+		if err = rows.Scan(&aggregate.instanceID, &aggregate.aggregateType, &aggregate.aggregateID); err != nil {
+			return nil, zerrors.ThrowInternal(err, "SQL-Pr8ns", "unable to scan prunable aggregates")
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Pr9nr", "unable to query prunable aggregates")
+	}
+	return aggregates, nil
+}
@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCRDB_PruneEvents(t *testing.T) {
+	before := time.Now().Add(-2 * DefaultPruneSafetyWindow)
+	tests := []struct {
+		name        string
+		before      time.Time
+		dryRun      bool
+		opts        []CRDBOption
+		expect      func(mock sqlmock.Sqlmock)
+		wantDeleted int64
+		wantErr     func(error) bool
+	}{
+		{
+			name:    "within safety window",
+			before:  time.Now().Add(-24 * time.Hour),
+			expect:  func(sqlmock.Sqlmock) {},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+		{
+			name:    "within overridden safety window",
+			before:  time.Now().Add(-24 * time.Hour),
+			opts:    []CRDBOption{WithPruneSafetyWindow(48 * time.Hour)},
+			expect:  func(sqlmock.Sqlmock) {},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+		{
+			name:   "safety window overridden",
+			before: time.Now().Add(-24 * time.Hour),
+			opts:   []CRDBOption{WithPruneSafetyWindow(time.Hour)},
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPruneBatch(mock, sqlmock.AnyArg(), 1, 3)
+			},
+			wantDeleted: 3,
+		},
+		{
+			name:   "dry run",
+			before: before,
+			dryRun: true,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(pruneCountQuery)).
+					WithArgs(before).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(2500))
+				mock.ExpectCommit()
+			},
+			wantDeleted: 2500,
+		},
+		{
+			name:   "batches",
+			before: before,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPruneBatch(mock, before, pruneBatchSize, 2500)
+				expectPruneBatch(mock, before, 2, 5)
+			},
+			wantDeleted: 2505,
+		},
+		{
+			name:   "nothing to prune",
+			before: before,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(pruneAggregatesQuery)).
+					WithArgs(before, pruneBatchSize).
+					WillReturnRows(mock.NewRows([]string{"instance_id", "aggregate_type", "aggregate_id"}))
+				mock.ExpectCommit()
+			},
+			wantDeleted: 0,
+		},
+		{
+			name:   "delete fails",
+			before: before,
+			expect: func(mock sqlmock.Sqlmock) {
+				expectPruneBatch(mock, before, pruneBatchSize, 2500)
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(pruneAggregatesQuery)).
+					WithArgs(before, pruneBatchSize).
+					WillReturnRows(mock.NewRows([]string{"instance_id", "aggregate_type", "aggregate_id"}).AddRow("instance", "user", "1"))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM eventstore.events2")).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantDeleted: 2500,
+			wantErr:     zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("unable to create mock client: %v", err)
+			}
+			defer client.Close()
+			tt.expect(mock)
+
+			db := &CRDB{DB: &database.DB{DB: client}}
+			for _, opt := range tt.opts {
+				opt(db)
+			}
+			deleted, err := db.PruneEvents(context.Background(), tt.before, tt.dryRun)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CRDB.PruneEvents() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Fatalf("CRDB.PruneEvents() unexpected error = %v", err)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("CRDB.PruneEvents() deleted = %d, want %d", deleted, tt.wantDeleted)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("not all expectations met: %v", err)
+			}
+		})
+	}
+}
+
+// expectPruneBatch expects a transaction pruning the given count of aggregates
+func expectPruneBatch(mock sqlmock.Sqlmock, before driver.Value, aggregates int, deleted int64) {
+	rows := mock.NewRows([]string{"instance_id", "aggregate_type", "aggregate_id"})
+	args := []driver.Value{before}
+	placeholders := make([]string, aggregates)
+	for i := 0; i < aggregates; i++ {
+		rows.AddRow("instance", "user", strconv.Itoa(i))
+		args = append(args, "instance", "user", strconv.Itoa(i))
+		placeholders[i] = fmt.Sprintf("($%d, $%d, $%d)", i*3+2, i*3+3, i*3+4)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(pruneAggregatesQuery)).
+		WithArgs(before, pruneBatchSize).
+		WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(pruneStmt, strings.Join(placeholders, ", ")))).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, deleted))
+	mock.ExpectCommit()
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zitadel/logging"
 
@@ -27,7 +29,9 @@ type querier interface {
 	eventQuery(useV1 bool) string
 	maxSequenceQuery(useV1 bool) string
 	instanceIDsQuery(useV1 bool) string
+	existsQuery(useV1 bool) string
 	db() *database.DB
+	slowQueryThreshold() time.Duration
 	orderByEventSequence(desc, useV1 bool) string
 	dialect.Database
 }
@@ -60,11 +64,15 @@ func query(ctx context.Context, criteria querier, searchQuery *eventstore.Search
 		return err
 	}
 	query, rowScanner := prepareColumns(criteria, q.Columns, useV1)
-	where, values := prepareConditions(criteria, q, useV1)
+	where, values, err := prepareConditions(criteria, q, useV1)
+	if err != nil {
+		return err
+	}
 	if where == "" || query == "" {
 		return zerrors.ThrowInvalidArgument(nil, "SQL-rWeBw", "invalid query factory")
 	}
-	if q.Tx == nil {
+	// time travel is not allowed in sub queries
+	if q.Tx == nil && q.Columns != eventstore.ColumnsExists {
 		if travel := prepareTimeTravel(ctx, criteria, q.AllowTimeTravel); travel != "" {
 			query += travel
 		}
@@ -94,6 +102,10 @@ func query(ctx context.Context, criteria querier, searchQuery *eventstore.Search
 		query += " OFFSET ?"
 	}
 
+	if q.Columns == eventstore.ColumnsExists {
+		query = "SELECT EXISTS(" + query + ")"
+	}
+
 	query = criteria.placeholder(query)
 
 	var contextQuerier interface {
@@ -104,6 +116,9 @@ func query(ctx context.Context, criteria querier, searchQuery *eventstore.Search
 		contextQuerier = &tx{Tx: q.Tx}
 	}
 
+	start := time.Now()
+	defer logSlowQuery(criteria.slowQueryThreshold(), start, query, values)
+
 	err = contextQuerier.QueryContext(ctx,
 		func(rows *sql.Rows) error {
 			for rows.Next() {
@@ -122,12 +137,37 @@ func query(ctx context.Context, criteria querier, searchQuery *eventstore.Search
 	return nil
 }
 
+// logSlowQuery logs the statement if it took longer than threshold
+// payload arguments are redacted
+func logSlowQuery(threshold time.Duration, start time.Time, query string, args []any) {
+	elapsed := time.Since(start)
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		redacted[i] = arg
+		// payload filters are marshalled to json
+		if _, ok := arg.([]byte); ok {
+			redacted[i] = "[REDACTED]"
+		}
+	}
+	logging.WithFields(
+		"query", query,
+		"args", redacted,
+		"elapsed", elapsed,
+		"threshold", threshold,
+	).Warn("slow filter query")
+}
+
 func prepareColumns(criteria querier, columns eventstore.Columns, useV1 bool) (string, func(s scan, dest interface{}) error) {
 	switch columns {
 	case eventstore.ColumnsMaxSequence:
 		return criteria.maxSequenceQuery(useV1), maxSequenceScanner
 	case eventstore.ColumnsInstanceIDs:
 		return criteria.instanceIDsQuery(useV1), instanceIDsScanner
+	case eventstore.ColumnsExists:
+		return criteria.existsQuery(useV1), existsScanner
 	case eventstore.ColumnsEvent:
 		return criteria.eventQuery(useV1), eventsScanner(useV1)
 	default:
@@ -155,6 +195,18 @@ func maxSequenceScanner(row scan, dest interface{}) (err error) {
 	return zerrors.ThrowInternal(err, "SQL-bN5xg", "something went wrong")
 }
 
+func existsScanner(scanner scan, dest interface{}) (err error) {
+	exists, ok := dest.(*bool)
+	if !ok {
+		return zerrors.ThrowInvalidArgumentf(nil, "SQL-Xi3ob", "type must be *bool got: %T", dest)
+	}
+	err = scanner(exists)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "SQL-Xi3oc", "unable to scan row")
+	}
+	return nil
+}
+
 func instanceIDsScanner(scanner scan, dest interface{}) (err error) {
 	ids, ok := dest.(*[]string)
 	if !ok {
@@ -187,6 +239,7 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 				&event.Seq,
 				&event.Data,
 				&event.EditorUser,
+				&event.Service,
 				&event.ResourceOwner,
 				&event.InstanceID,
 				&event.AggregateType,
@@ -216,22 +269,31 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 			return zerrors.ThrowInternal(err, "SQL-M0dsf", "unable to scan row")
 		}
 		event.Pos = position.Float64
+		if event.Data, err = eventstore.DecompressPayload(event.Data); err != nil {
+			return zerrors.ThrowInternal(err, "SQL-Gz8dq", "unable to decompress payload")
+		}
 		return reduce(event)
 	}
 }
 
-func prepareConditions(criteria querier, query *repository.SearchQuery, useV1 bool) (string, []any) {
-	clauses, args := prepareQuery(criteria, useV1, query.InstanceID, query.InstanceIDs, query.ExcludedInstances)
+func prepareConditions(criteria querier, query *repository.SearchQuery, useV1 bool) (string, []any, error) {
+	clauses, args, err := prepareQuery(criteria, useV1, query.InstanceID, query.InstanceIDs, query.ExcludedInstances)
+	if err != nil {
+		return "", nil, err
+	}
 	if clauses != "" && len(query.SubQueries) > 0 {
 		clauses += " AND "
 	}
 	subClauses := make([]string, len(query.SubQueries))
 	for i, filters := range query.SubQueries {
 		var subArgs []any
-		subClauses[i], subArgs = prepareQuery(criteria, useV1, filters...)
+		subClauses[i], subArgs, err = prepareQuery(criteria, useV1, filters...)
+		if err != nil {
+			return "", nil, err
+		}
 		// an error is thrown in [query]
 		if subClauses[i] == "" {
-			return "", nil
+			return "", nil, nil
 		}
 		if len(query.SubQueries) > 1 && len(subArgs) > 1 {
 			subClauses[i] = "(" + subClauses[i] + ")"
@@ -244,14 +306,22 @@ func prepareConditions(criteria querier, query *repository.SearchQuery, useV1 bo
 		clauses += "(" + strings.Join(subClauses, " OR ") + ")"
 	}
 
-	additionalClauses, additionalArgs := prepareQuery(criteria, useV1,
+	additionalClauses, additionalArgs, err := prepareQuery(criteria, useV1,
 		query.Position,
+		query.PositionAtMost,
 		query.Owner,
 		query.Sequence,
 		query.CreatedAfter,
 		query.CreatedBefore,
 		query.Creator,
+		query.CreatorPrefix,
+		query.EditorService,
+		query.ExcludedServices,
+		query.EventTypes,
 	)
+	if err != nil {
+		return "", nil, err
+	}
 	if additionalClauses != "" {
 		if clauses != "" {
 			clauses += " AND "
@@ -265,24 +335,37 @@ func prepareConditions(criteria querier, query *repository.SearchQuery, useV1 bo
 	}
 
 	if clauses == "" {
-		return "", nil
+		return "", nil, nil
 	}
 
-	return " WHERE " + clauses, args
+	return " WHERE " + clauses, args, nil
 }
 
-func prepareQuery(criteria querier, useV1 bool, filters ...*repository.Filter) (_ string, args []any) {
+// prepareQuery joins the conditions of the filters.
+// An error is returned if a filter can't be mapped to a column of the table, e.g. the editor service of events2,
+// otherwise the filter would be dropped silently and more events would be returned.
+func prepareQuery(criteria querier, useV1 bool, filters ...*repository.Filter) (_ string, args []any, err error) {
 	clauses := make([]string, 0, len(filters))
 	args = make([]any, 0, len(filters))
 	for _, filter := range filters {
 		if filter == nil {
 			continue
 		}
+		if criteria.columnName(filter.Field, useV1) == "" {
+			return "", nil, zerrors.ThrowInvalidArgumentf(nil, "SQL-Fd7nm", "field %d can't be filtered in this table", filter.Field)
+		}
+		if condition, ok := emptyListCondition(filter); ok {
+			clauses = append(clauses, condition)
+			continue
+		}
 		arg := filter.Value
 
+		if filter.Operation == repository.OperationStartsWith {
+			arg = likePrefix(reflect.ValueOf(arg).String())
+		}
+
 		// marshal if payload filter
 		if filter.Field == repository.FieldEventData {
-			var err error
 			arg, err = json.Marshal(arg)
 			if err != nil {
 				logging.WithError(err).Warn("unable to marshal search value")
@@ -292,14 +375,42 @@ func prepareQuery(criteria querier, useV1 bool, filters ...*repository.Filter) (
 		}
 
 		clauses = append(clauses, getCondition(criteria, filter, useV1))
-		// if mapping failed an error is thrown in [query]
+		// if the operation can't be mapped an error is thrown in [query]
 		if clauses[len(clauses)-1] == "" {
-			return "", nil
+			return "", nil, nil
 		}
 		args = append(args, arg)
 	}
 
-	return strings.Join(clauses, " AND "), args
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+var likeEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`%`, `\%`,
+	`_`, `\_`,
+)
+
+// likePrefix returns the LIKE pattern matching all texts starting with prefix,
+// the wildcards of the prefix are escaped so they are matched literally
+func likePrefix(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
+}
+
+// emptyListCondition returns a constant condition if the value of an in or not in filter is an empty list
+// because the behaviour of ANY and ALL on empty arrays differs between the drivers
+func emptyListCondition(filter *repository.Filter) (string, bool) {
+	if filter.Operation != repository.OperationIn && filter.Operation != repository.OperationNotIn {
+		return "", false
+	}
+	value := reflect.ValueOf(filter.Value)
+	if value.Kind() != reflect.Slice || value.Len() > 0 {
+		return "", false
+	}
+	if filter.Operation == repository.OperationIn {
+		return "1 = 0", true
+	}
+	return "1 = 1", true
 }
 
 func getCondition(cond querier, filter *repository.Filter, useV1 bool) (condition string) {
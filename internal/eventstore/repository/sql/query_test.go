@@ -1,16 +1,20 @@
 package sql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/database/cockroach"
@@ -44,11 +48,21 @@ func Test_getCondition(t *testing.T) {
 			args: args{filter: repository.NewFilter(repository.FieldSequence, 5000, repository.OperationLess)},
 			want: `"sequence" < ?`,
 		},
+		{
+			name: "less or equal",
+			args: args{filter: repository.NewFilter(repository.FieldPosition, 42.5, repository.OperationLessOrEqual)},
+			want: `"position" <= ?`,
+		},
 		{
 			name: "in list",
 			args: args{filter: repository.NewFilter(repository.FieldAggregateType, []eventstore.AggregateType{"movies", "actors"}, repository.OperationIn)},
 			want: "aggregate_type = ANY(?)",
 		},
+		{
+			name: "starts with",
+			args: args{filter: repository.NewFilter(repository.FieldEditorUser, "system", repository.OperationStartsWith)},
+			want: "creator LIKE ?",
+		},
 		{
 			name: "invalid operation",
 			args: args{filter: repository.NewFilter(repository.FieldAggregateType, []eventstore.AggregateType{"movies", "actors"}, repository.Operation(-1))},
@@ -146,6 +160,31 @@ func Test_prepareColumns(t *testing.T) {
 				dbErr: zerrors.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "exists",
+			args: args{
+				columns: eventstore.ColumnsExists,
+				dest:    new(bool),
+			},
+			res: res{
+				query:    `SELECT 1 FROM eventstore.events2`,
+				expected: true,
+			},
+			fields: fields{
+				dbRow: []interface{}{true},
+			},
+		},
+		{
+			name: "exists wrong dest type",
+			args: args{
+				columns: eventstore.ColumnsExists,
+				dest:    new(uint64),
+			},
+			res: res{
+				query: `SELECT 1 FROM eventstore.events2`,
+				dbErr: zerrors.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "events",
 			args: args{
@@ -157,13 +196,13 @@ func Test_prepareColumns(t *testing.T) {
 				useV1: true,
 			},
 			res: res{
-				query: `SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events`,
+				query: `SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events`,
 				expected: []eventstore.Event{
-					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Data: nil},
+					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Data: nil, Service: "zitadel"},
 				},
 			},
 			fields: fields{
-				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", eventstore.Version("")},
+				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.RawBytes(nil), "", "zitadel", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", eventstore.Version("")},
 			},
 		},
 		{
@@ -212,7 +251,7 @@ func Test_prepareColumns(t *testing.T) {
 				useV1:   true,
 			},
 			res: res{
-				query: `SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events`,
+				query: `SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events`,
 				dbErr: zerrors.IsErrorInvalidArgument,
 			},
 		},
@@ -228,7 +267,7 @@ func Test_prepareColumns(t *testing.T) {
 				useV1: true,
 			},
 			res: res{
-				query: `SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events`,
+				query: `SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events`,
 				dbErr: zerrors.IsInternal,
 			},
 		},
@@ -301,8 +340,9 @@ func Test_prepareCondition(t *testing.T) {
 		useV1 bool
 	}
 	type res struct {
-		clause string
-		values []interface{}
+		clause  string
+		values  []interface{}
+		wantErr bool
 	}
 	tests := []struct {
 		name string
@@ -388,6 +428,22 @@ func Test_prepareCondition(t *testing.T) {
 				values: nil,
 			},
 		},
+		{
+			name: "unmapped field v2",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, "user", repository.OperationEquals),
+						},
+					},
+					EditorService: repository.NewFilter(repository.FieldEditorService, "zitadel", repository.OperationEquals),
+				},
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
 		{
 			name: "array as condition value",
 			args: args{
@@ -462,11 +518,144 @@ func Test_prepareCondition(t *testing.T) {
 				values: []interface{}{[]eventstore.AggregateType{"user", "org"}, "1234", []eventstore.EventType{"user.created", "org.created"}},
 			},
 		},
+		{
+			name: "empty in list text array",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, database.TextArray[eventstore.AggregateType]{}, repository.OperationIn),
+							repository.NewFilter(repository.FieldAggregateID, "1234", repository.OperationEquals),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE 1 = 0 AND aggregate_id = ?`,
+				values: []interface{}{"1234"},
+			},
+		},
+		{
+			name: "empty not in list text array",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, database.TextArray[eventstore.AggregateType]{}, repository.OperationNotIn),
+							repository.NewFilter(repository.FieldAggregateID, "1234", repository.OperationEquals),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE 1 = 1 AND aggregate_id = ?`,
+				values: []interface{}{"1234"},
+			},
+		},
+		{
+			name: "empty in list slice",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, []eventstore.AggregateType{}, repository.OperationIn),
+							repository.NewFilter(repository.FieldAggregateID, "1234", repository.OperationEquals),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE 1 = 0 AND aggregate_id = ?`,
+				values: []interface{}{"1234"},
+			},
+		},
+		{
+			name: "empty not in list slice",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, []eventstore.AggregateType{}, repository.OperationNotIn),
+							repository.NewFilter(repository.FieldAggregateID, "1234", repository.OperationEquals),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE 1 = 1 AND aggregate_id = ?`,
+				values: []interface{}{"1234"},
+			},
+		},
+		{
+			name: "starts with",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldEventType, "user.human.", repository.OperationStartsWith),
+						},
+					},
+					CreatorPrefix: repository.NewFilter(repository.FieldEditorUser, "system", repository.OperationStartsWith),
+				},
+			},
+			res: res{
+				clause: ` WHERE event_type LIKE ? AND creator LIKE ?`,
+				values: []interface{}{"user.human.%", "system%"},
+			},
+		},
+		{
+			name: "starts with v1",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldEditorService, "zitadel", repository.OperationStartsWith),
+						},
+					},
+				},
+				useV1: true,
+			},
+			res: res{
+				clause: ` WHERE editor_service LIKE ?`,
+				values: []interface{}{"zitadel%"},
+			},
+		},
+		{
+			name: "starts with typed value",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldEventType, eventstore.EventType("org."), repository.OperationStartsWith),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE event_type LIKE ?`,
+				values: []interface{}{"org.%"},
+			},
+		},
+		{
+			name: "starts with wildcards",
+			args: args{
+				query: &repository.SearchQuery{
+					CreatorPrefix: repository.NewFilter(repository.FieldEditorUser, `50%_off\`, repository.OperationStartsWith),
+				},
+			},
+			res: res{
+				clause: ` WHERE creator LIKE ?`,
+				values: []interface{}{`50\%\_off\\%`},
+			},
+		},
 	}
 	crdb := NewCRDB(&database.DB{Database: new(cockroach.Config)})
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotClause, gotValues := prepareConditions(crdb, tt.args.query, tt.args.useV1)
+			gotClause, gotValues, err := prepareConditions(crdb, tt.args.query, tt.args.useV1)
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("prepareCondition() err = %v, wantErr %v", err, tt.res.wantErr)
+			}
 			if gotClause != tt.res.clause {
 				t.Errorf("prepareCondition() gotClause = %v, want %v", gotClause, tt.res.clause)
 			}
@@ -687,7 +876,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQuery(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC`,
 					[]driver.Value{eventstore.AggregateType("user")},
 				),
 			},
@@ -709,7 +898,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQuery(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence LIMIT \$2`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence LIMIT \$2`,
 					[]driver.Value{eventstore.AggregateType("user"), uint64(5)},
 				),
 			},
@@ -731,7 +920,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQuery(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC LIMIT \$2`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC LIMIT \$2`,
 					[]driver.Value{eventstore.AggregateType("user"), uint64(5)},
 				),
 			},
@@ -754,7 +943,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQuery(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events AS OF SYSTEM TIME '-1 ms' WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC LIMIT \$2`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events AS OF SYSTEM TIME '-1 ms' WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC LIMIT \$2`,
 					[]driver.Value{eventstore.AggregateType("user"), uint64(5)},
 				),
 			},
@@ -762,6 +951,153 @@ func Test_query_events_mocked(t *testing.T) {
 				wantErr: false,
 			},
 		},
+		{
+			name: "with aggregate type and event types",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					OrderAsc().
+					AwaitOpenTransactions().
+					ForEventTypes("user", "user.created", "user.updated").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND event_type = ANY\(\$2\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), []eventstore.EventType{"user.created", "user.updated"}},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with editor service",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					OrderAsc().
+					EditorService("migration-tool").
+					AddQuery().
+					AggregateTypes("user").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND editor_service = \$2 ORDER BY event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), "migration-tool"},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with excluded editor services",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					OrderAsc().
+					ExcludeEditorServices("zitadel", "migration-tool").
+					AddQuery().
+					AggregateTypes("user").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND editor_service <> ALL\(\$2\) ORDER BY event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), []string{"zitadel", "migration-tool"}},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with included and excluded event types",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					OrderAsc().
+					AwaitOpenTransactions().
+					AddQuery().
+					AggregateTypes("user").
+					EventTypes("user.created", "user.updated", "user.token.added").
+					ExcludeEventTypes("user.token.added").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND event_type = ANY\(\$2\) AND event_type <> ALL\(\$3\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), []eventstore.EventType{"user.created", "user.updated", "user.token.added"}, []eventstore.EventType{"user.token.added"}},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with aggregate event types",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					OrderAsc().
+					AwaitOpenTransactions().
+					AggregateEventTypes("user.added", "org.added").
+					AddQuery().
+					AggregateTypes("user", "org").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = ANY\(\$1\) AND event_type = ANY\(\$2\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence`,
+					[]driver.Value{[]eventstore.AggregateType{"user", "org"}, []eventstore.EventType{"user.added", "org.added"}},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with payload contains nested object",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					OrderAsc().
+					AwaitOpenTransactions().
+					AddQuery().
+					AggregateTypes("user").
+					EventPayloadContains(map[string]interface{}{
+						"profile": map[string]interface{}{
+							"firstName": "hodor",
+						},
+					}).
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND event_data @> \$2 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), []byte(`{"profile":{"firstName":"hodor"}}`)},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with payload contains scalar",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AddQuery().
+					AggregateTypes("user").
+					EventPayloadContains("hodor").
+					Builder(),
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
 		{
 			name: "error sql conn closed",
 			args: args{
@@ -776,7 +1112,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQueryErr(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC`,
 					[]driver.Value{eventstore.AggregateType("user")},
 					sql.ErrConnDone),
 			},
@@ -798,7 +1134,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQueryScanErr(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC`,
 					[]driver.Value{eventstore.AggregateType("user")},
 					&repository.Event{Seq: 100}),
 			},
@@ -832,7 +1168,7 @@ func Test_query_events_mocked(t *testing.T) {
 			},
 			fields: fields{
 				mock: newMockClient(t).expectQuery(t,
-					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE \(aggregate_type = \$1 OR \(aggregate_type = \$2 AND aggregate_id = \$3\)\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC LIMIT \$4`,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, editor_service, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE \(aggregate_type = \$1 OR \(aggregate_type = \$2 AND aggregate_id = \$3\)\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence DESC LIMIT \$4`,
 					[]driver.Value{eventstore.AggregateType("user"), eventstore.AggregateType("org"), "asdf42", uint64(5)},
 				),
 			},
@@ -901,15 +1237,113 @@ func (m *dbMock) expectQueryErr(t *testing.T, expectedQuery string, args []drive
 
 func newMockClient(t *testing.T) *dbMock {
 	t.Helper()
-	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+	return newMockClientWithMatcher(t, sqlmock.QueryMatcherRegexp)
+}
+
+// newMockClientWithMatcher returns a mock client comparing the expected queries with the matcher
+func newMockClientWithMatcher(t *testing.T, matcher sqlmock.QueryMatcher) *dbMock {
+	t.Helper()
+	db, mock, err := sqlmock.New(
+		sqlmock.ValueConverterOption(new(db_mock.TypeConverter)),
+		sqlmock.QueryMatcherOption(matcher),
+	)
 	if err != nil {
 		t.Errorf("unable to create mock client: %v", err)
 		t.FailNow()
 		return nil
 	}
+	t.Cleanup(func() { db.Close() })
 
 	return &dbMock{
 		mock:   mock,
 		client: db,
 	}
 }
+
+// crdb returns the eventstore querying the mock client
+func (m *dbMock) crdb() *CRDB {
+	return &CRDB{
+		DB: &database.DB{
+			DB:       m.client,
+			Database: new(testDB),
+		},
+	}
+}
+
+// assertExpectationsMet reports the expectations which were not met
+func (m *dbMock) assertExpectationsMet(t *testing.T) {
+	t.Helper()
+	if err := m.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectations met: %v", err)
+	}
+}
+
+// expectPushBegin expects the start of the transaction of [CRDB.Push]
+func expectPushBegin(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectPushCommit expects the commit of the transaction of [CRDB.Push]
+func expectPushCommit(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("RELEASE SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+}
+
+func Test_query_slowQueryLogged(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		wantLog   bool
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+			wantLog:   false,
+		},
+		{
+			name:      "below threshold",
+			threshold: time.Hour,
+			wantLog:   false,
+		},
+		{
+			name:      "exceeds threshold",
+			threshold: time.Millisecond,
+			wantLog:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logging.SetOutput(&out)
+			defer logging.SetOutput(os.Stderr)
+
+			mock := newMockClient(t)
+			mock.mock.ExpectBegin()
+			mock.mock.ExpectQuery(`SELECT creation_date, event_type`).
+				WillDelayFor(10 * time.Millisecond).
+				WillReturnRows(mock.mock.NewRows([]string{"sequence"}))
+			mock.mock.ExpectCommit()
+
+			crdb := NewCRDB(&database.DB{DB: mock.client, Database: new(testDB)}, WithSlowQueryThreshold(tt.threshold))
+			err := query(context.Background(), crdb,
+				eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AddQuery().
+					AggregateTypes("user").
+					EventPayloadContains(map[string]interface{}{"secret": "hodor"}).
+					Builder(),
+				&[]*repository.Event{}, true)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.mock.ExpectationsWereMet())
+
+			logged := out.String()
+			assert.Equal(t, tt.wantLog, strings.Contains(logged, "slow filter query"))
+			assert.NotContains(t, logged, "hodor")
+			if tt.wantLog {
+				assert.Contains(t, logged, "level=warning")
+				assert.Contains(t, logged, "[REDACTED]")
+				assert.Contains(t, logged, "FROM eventstore.events")
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package eventstore
 import (
 	"context"
 	"database/sql"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
@@ -20,14 +22,19 @@ type SearchQueryBuilder struct {
 	instanceID            *string
 	instanceIDs           []string
 	editorUser            string
+	editorUserPrefix      string
+	editorService         string
+	excludedServices      []string
 	queries               []*SearchQuery
 	tx                    *sql.Tx
 	allowTimeTravel       bool
 	positionAfter         float64
+	positionAtMost        float64
 	awaitOpenTransactions bool
 	creationDateAfter     time.Time
 	creationDateBefore    time.Time
 	eventSequenceGreater  uint64
+	eventTypes            []EventType
 }
 
 func (b *SearchQueryBuilder) GetColumns() Columns {
@@ -62,6 +69,18 @@ func (b *SearchQueryBuilder) GetEditorUser() string {
 	return b.editorUser
 }
 
+func (b *SearchQueryBuilder) GetEditorUserPrefix() string {
+	return b.editorUserPrefix
+}
+
+func (b *SearchQueryBuilder) GetEditorService() string {
+	return b.editorService
+}
+
+func (b *SearchQueryBuilder) GetExcludedEditorServices() []string {
+	return b.excludedServices
+}
+
 func (b *SearchQueryBuilder) GetQueries() []*SearchQuery {
 	return b.queries
 }
@@ -78,6 +97,10 @@ func (b SearchQueryBuilder) GetPositionAfter() float64 {
 	return b.positionAfter
 }
 
+func (b SearchQueryBuilder) GetPositionAtMost() float64 {
+	return b.positionAtMost
+}
+
 func (b SearchQueryBuilder) GetAwaitOpenTransactions() bool {
 	return b.awaitOpenTransactions
 }
@@ -94,6 +117,10 @@ func (q SearchQueryBuilder) GetCreationDateBefore() time.Time {
 	return q.creationDateBefore
 }
 
+func (q SearchQueryBuilder) GetEventTypes() []EventType {
+	return q.eventTypes
+}
+
 // ensureInstanceID makes sure that the instance id is always set
 func (b *SearchQueryBuilder) ensureInstanceID(ctx context.Context) {
 	if b.instanceID == nil && len(b.instanceIDs) == 0 && authz.GetInstance(ctx).InstanceID() != "" {
@@ -102,11 +129,14 @@ func (b *SearchQueryBuilder) ensureInstanceID(ctx context.Context) {
 }
 
 type SearchQuery struct {
-	builder        *SearchQueryBuilder
-	aggregateTypes []AggregateType
-	aggregateIDs   []string
-	eventTypes     []EventType
-	eventData      map[string]interface{}
+	builder         *SearchQueryBuilder
+	aggregateTypes  []AggregateType
+	aggregateIDs    []string
+	eventTypes      []EventType
+	excludedTypes   []EventType
+	eventTypePrefix string
+	eventData       map[string]interface{}
+	eventPayload    any
 }
 
 func (q SearchQuery) GetAggregateTypes() []AggregateType {
@@ -121,10 +151,22 @@ func (q SearchQuery) GetEventTypes() []EventType {
 	return q.eventTypes
 }
 
+func (q SearchQuery) GetEventTypePrefix() string {
+	return q.eventTypePrefix
+}
+
+func (q SearchQuery) GetExcludedEventTypes() []EventType {
+	return q.excludedTypes
+}
+
 func (q SearchQuery) GetEventData() map[string]interface{} {
 	return q.eventData
 }
 
+func (q SearchQuery) GetEventPayload() any {
+	return q.eventPayload
+}
+
 // Columns defines which fields of the event are needed for the query
 type Columns int8
 
@@ -135,6 +177,8 @@ const (
 	ColumnsMaxSequence
 	// ColumnsInstanceIDs represents the instance ids of the filtered events
 	ColumnsInstanceIDs
+	// ColumnsExists represents if at least one event matches the filter
+	ColumnsExists
 
 	columnsCount
 )
@@ -183,6 +227,15 @@ func (builder *SearchQueryBuilder) matchCommand(command Command) bool {
 	if command.Aggregate().InstanceID != "" && builder.instanceID != nil && *builder.instanceID != "" && command.Aggregate().InstanceID != *builder.instanceID {
 		return false
 	}
+	if len(builder.eventTypes) > 0 && !isEventTypes(command, builder.eventTypes...) {
+		return false
+	}
+	if builder.editorService != "" && EditorService(command) != builder.editorService {
+		return false
+	}
+	if len(builder.excludedServices) > 0 && slices.Contains(builder.excludedServices, EditorService(command)) {
+		return false
+	}
 	if seq, ok := command.(sequencer); ok {
 		if builder.eventSequenceGreater > 0 && seq.Sequence() <= builder.eventSequenceGreater {
 			return false
@@ -259,6 +312,30 @@ func (builder *SearchQueryBuilder) EditorUser(id string) *SearchQueryBuilder {
 	return builder
 }
 
+// EditorUserStartsWith filters for events created by users whose id starts with prefix
+func (builder *SearchQueryBuilder) EditorUserStartsWith(prefix string) *SearchQueryBuilder {
+	builder.editorUserPrefix = prefix
+	return builder
+}
+
+// EditorService filters for events written by the service, see [EditorService].
+// The editor service is only stored in eventstore.events,
+// queries of eventstore.events2 return an invalid argument error.
+// Unlike [SearchQueryBuilder.EditorUserStartsWith] there is no prefix filter on the service,
+// because the events pushed since eventstore.events2 don't store it.
+func (builder *SearchQueryBuilder) EditorService(service string) *SearchQueryBuilder {
+	builder.editorService = service
+	return builder
+}
+
+// ExcludeEditorServices filters out events written by the services, see [EditorService].
+// The editor service is only stored in eventstore.events,
+// queries of eventstore.events2 return an invalid argument error.
+func (builder *SearchQueryBuilder) ExcludeEditorServices(services ...string) *SearchQueryBuilder {
+	builder.excludedServices = services
+	return builder
+}
+
 // AllowTimeTravel activates the time travel feature of the database if supported
 // The queries will be made based on the call time
 func (builder *SearchQueryBuilder) AllowTimeTravel() *SearchQueryBuilder {
@@ -272,6 +349,12 @@ func (builder *SearchQueryBuilder) PositionAfter(position float64) *SearchQueryB
 	return builder
 }
 
+// PositionAtMost filters for events with a position less or equal the specified position
+func (builder *SearchQueryBuilder) PositionAtMost(position float64) *SearchQueryBuilder {
+	builder.positionAtMost = position
+	return builder
+}
+
 // AwaitOpenTransactions filters for events which are older than the oldest transaction of the database
 func (builder *SearchQueryBuilder) AwaitOpenTransactions() *SearchQueryBuilder {
 	builder.awaitOpenTransactions = true
@@ -302,6 +385,13 @@ func (builder *SearchQueryBuilder) CreationDateBefore(creationDate time.Time) *S
 	return builder
 }
 
+// AggregateEventTypes filters for events with the given event types.
+// In contrast to [SearchQuery.EventTypes] the filter applies to all sub queries.
+func (builder *SearchQueryBuilder) AggregateEventTypes(types ...EventType) *SearchQueryBuilder {
+	builder.eventTypes = types
+	return builder
+}
+
 // AddQuery creates a new sub query.
 // All fields in the sub query are AND-connected in the storage request.
 // Multiple sub queries are OR-connected in the storage request.
@@ -314,6 +404,14 @@ func (builder *SearchQueryBuilder) AddQuery() *SearchQuery {
 	return query
 }
 
+// ForEventTypes creates a new sub query which filters for events
+// of the given aggregate type with one of the given event types.
+func (builder *SearchQueryBuilder) ForEventTypes(aggregateType AggregateType, eventTypes ...EventType) *SearchQuery {
+	return builder.AddQuery().
+		AggregateTypes(aggregateType).
+		EventTypes(eventTypes...)
+}
+
 // Or creates a new sub query on the search query builder
 func (query SearchQuery) Or() *SearchQuery {
 	return query.builder.AddQuery()
@@ -337,13 +435,36 @@ func (query *SearchQuery) EventTypes(types ...EventType) *SearchQuery {
 	return query
 }
 
+// EventTypeStartsWith filters for events whose type starts with prefix, e.g. "user.human."
+func (query *SearchQuery) EventTypeStartsWith(prefix string) *SearchQuery {
+	query.eventTypePrefix = prefix
+	return query
+}
+
+// ExcludeEventTypes filters out events with the given event types.
+// It can be combined with [SearchQuery.EventTypes].
+func (query *SearchQuery) ExcludeEventTypes(types ...EventType) *SearchQuery {
+	query.excludedTypes = types
+	return query
+}
+
 // EventData filters for events with the given event data.
+// Payloads stored compressed (see [CompressPayload]) never match.
 // Use this call with care as it will be slower than the other filters.
 func (query *SearchQuery) EventData(data map[string]interface{}) *SearchQuery {
 	query.eventData = data
 	return query
 }
 
+// EventPayloadContains filters for events whose payload contains the given object.
+// obj must be marshalable to a json object, otherwise the filter fails.
+// Payloads stored compressed (see [CompressPayload]) never match.
+// Use this call with care as it will be slower than the other filters.
+func (query *SearchQuery) EventPayloadContains(obj any) *SearchQuery {
+	query.eventPayload = obj
+	return query
+}
+
 // Builder returns the SearchQueryBuilder of the sub query
 func (query *SearchQuery) Builder() *SearchQueryBuilder {
 	return query.builder
@@ -359,5 +480,11 @@ func (query *SearchQuery) matches(command Command) bool {
 	if ok := isEventTypes(command, query.eventTypes...); len(query.eventTypes) > 0 && !ok {
 		return false
 	}
+	if query.eventTypePrefix != "" && !strings.HasPrefix(string(command.Type()), query.eventTypePrefix) {
+		return false
+	}
+	if isEventTypes(command, query.excludedTypes...) {
+		return false
+	}
 	return true
 }
@@ -168,6 +168,42 @@ func TestSearchQuerybuilderSetters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "set aggregate type and eventTypes",
+			args: args{
+				setters: []func(*SearchQueryBuilder) *SearchQueryBuilder{
+					func(builder *SearchQueryBuilder) *SearchQueryBuilder {
+						return builder.ForEventTypes("user", "user.created", "user.updated").Builder()
+					},
+				},
+			},
+			res: &SearchQueryBuilder{
+				queries: []*SearchQuery{
+					{
+						aggregateTypes: []AggregateType{"user"},
+						eventTypes:     []EventType{"user.created", "user.updated"},
+					},
+				},
+			},
+		},
+		{
+			name: "set excluded eventTypes",
+			args: args{
+				setters: []func(*SearchQueryBuilder) *SearchQueryBuilder{
+					func(builder *SearchQueryBuilder) *SearchQueryBuilder {
+						return builder.AddQuery().AggregateTypes("user").ExcludeEventTypes("user.token.added").Builder()
+					},
+				},
+			},
+			res: &SearchQueryBuilder{
+				queries: []*SearchQuery{
+					{
+						aggregateTypes: []AggregateType{"user"},
+						excludedTypes:  []EventType{"user.token.added"},
+					},
+				},
+			},
+		},
 		{
 			name: "set resource owner",
 			args: args{
@@ -247,6 +283,9 @@ func assertQuery(t *testing.T, i int, want, got *SearchQuery) {
 	if !reflect.DeepEqual(got.eventTypes, want.eventTypes) {
 		t.Errorf("wrong eventTypes in query %d : got: %v want: %v", i, got.eventTypes, want.eventTypes)
 	}
+	if !reflect.DeepEqual(got.excludedTypes, want.excludedTypes) {
+		t.Errorf("wrong excludedTypes in query %d : got: %v want: %v", i, got.excludedTypes, want.excludedTypes)
+	}
 }
 
 func TestSearchQuery_matches(t *testing.T) {
@@ -283,6 +322,28 @@ func TestSearchQuery_matches(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name:  "event type prefix",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().EventTypeStartsWith("event.searched."),
+			event: &matcherCommand{
+				BaseEvent{
+					EventType: "event.searched.type",
+					Agg:       &Aggregate{},
+				},
+			},
+			want: true,
+		},
+		{
+			name:  "wrong event type prefix",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().EventTypeStartsWith("event.searched."),
+			event: &matcherCommand{
+				BaseEvent{
+					EventType: "event.actual.type",
+					Agg:       &Aggregate{},
+				},
+			},
+			want: false,
+		},
 		{
 			name:  "wrong event type",
 			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().EventTypes("event.searched.type"),
@@ -294,6 +355,31 @@ func TestSearchQuery_matches(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name:  "excluded event type",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().ExcludeEventTypes("event.noisy.type"),
+			event: &matcherCommand{
+				BaseEvent{
+					EventType: "event.noisy.type",
+					Agg:       &Aggregate{},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "included and excluded event types",
+			query: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventTypes("event.actual.type", "event.noisy.type").
+				ExcludeEventTypes("event.noisy.type"),
+			event: &matcherCommand{
+				BaseEvent{
+					EventType: "event.actual.type",
+					Agg:       &Aggregate{},
+				},
+			},
+			want: true,
+		},
 		{
 			name: "matching",
 			query: NewSearchQueryBuilder(ColumnsEvent).
@@ -332,10 +418,12 @@ func TestSearchQuery_matches(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			query := &SearchQuery{
-				aggregateTypes: tt.query.aggregateTypes,
-				aggregateIDs:   tt.query.aggregateIDs,
-				eventTypes:     tt.query.eventTypes,
-				eventData:      tt.query.eventData,
+				aggregateTypes:  tt.query.aggregateTypes,
+				aggregateIDs:    tt.query.aggregateIDs,
+				eventTypes:      tt.query.eventTypes,
+				excludedTypes:   tt.query.excludedTypes,
+				eventTypePrefix: tt.query.eventTypePrefix,
+				eventData:       tt.query.eventData,
 			}
 			if got := query.matches(tt.event); got != tt.want {
 				t.Errorf("SearchQuery.matches() = %v, want %v", got, tt.want)
@@ -417,6 +505,82 @@ func TestSearchQueryBuilder_Matches(t *testing.T) {
 			},
 			wantedLen: 2,
 		},
+		{
+			name: "only allowed event types",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AggregateEventTypes("user.added", "user.removed"),
+			args: args{
+				commands: []Command{
+					&matcherCommand{
+						BaseEvent{
+							Agg: &Aggregate{
+								InstanceID: "instance",
+							},
+							EventType: "user.added",
+						},
+					},
+					&matcherCommand{
+						BaseEvent{
+							Agg: &Aggregate{
+								InstanceID: "instance",
+							},
+							EventType: "user.changed",
+						},
+					},
+					&matcherCommand{
+						BaseEvent{
+							Agg: &Aggregate{
+								InstanceID: "instance",
+							},
+							EventType: "user.removed",
+						},
+					},
+				},
+			},
+			wantedLen: 2,
+		},
+		{
+			name: "only editor service",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				EditorService("migration-tool"),
+			args: args{
+				commands: []Command{
+					&matcherCommand{
+						BaseEvent{
+							Agg:     &Aggregate{InstanceID: "instance"},
+							Service: "migration-tool",
+						},
+					},
+					&matcherCommand{
+						BaseEvent{
+							Agg: &Aggregate{InstanceID: "instance"},
+						},
+					},
+				},
+			},
+			wantedLen: 1,
+		},
+		{
+			name: "excluded editor services",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				ExcludeEditorServices("zitadel"),
+			args: args{
+				commands: []Command{
+					&matcherCommand{
+						BaseEvent{
+							Agg:     &Aggregate{InstanceID: "instance"},
+							Service: "migration-tool",
+						},
+					},
+					&matcherCommand{
+						BaseEvent{
+							Agg: &Aggregate{InstanceID: "instance"},
+						},
+					},
+				},
+			},
+			wantedLen: 1,
+		},
 		{
 			name: "wrong resource owner",
 			builder: NewSearchQueryBuilder(ColumnsEvent).
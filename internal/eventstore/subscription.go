@@ -4,8 +4,13 @@ import (
 	"sync"
 
 	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
+// subscriptionBufferSize is the capacity of the event channel created by [Eventstore.Subscribe]
+const subscriptionBufferSize = 100
+
 var (
 	subscriptions = map[AggregateType][]*Subscription{}
 	subsMutext    sync.Mutex
@@ -14,17 +19,42 @@ var (
 type Subscription struct {
 	Events chan Event
 	types  map[AggregateType][]EventType
+	closed bool
+	// dropOnFull drops events if Events is full instead of blocking the pusher
+	dropOnFull bool
+	// dropped counts the events dropped because Events was full
+	dropped uint64
+}
+
+// Subscribe subscribes for all events on the given aggregate types.
+// The events are delivered on a buffered channel after they were committed,
+// events of a failed push are never delivered.
+// If the buffer is full, events are dropped instead of blocking the pusher, see [Subscription.Dropped].
+// The caller must call [Subscription.Unsubscribe] if the events are not needed anymore.
+func (es *Eventstore) Subscribe(aggregateTypes ...AggregateType) (*Subscription, error) {
+	if len(aggregateTypes) == 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "V2-Sub1s", "Errors.Internal")
+	}
+	return subscribeAggregates(make(chan Event, subscriptionBufferSize), true, aggregateTypes...), nil
 }
 
 // SubscribeAggregates subscribes for all events on the given aggregates
+// the pusher blocks until the events are received from eventQueue
 func SubscribeAggregates(eventQueue chan Event, aggregates ...AggregateType) *Subscription {
+	return subscribeAggregates(eventQueue, false, aggregates...)
+}
+
+// subscribeAggregates registers the subscription,
+// dropOnFull must be set before the registration because the pushers read it as soon as it is registered
+func subscribeAggregates(eventQueue chan Event, dropOnFull bool, aggregates ...AggregateType) *Subscription {
 	types := make(map[AggregateType][]EventType, len(aggregates))
 	for _, aggregate := range aggregates {
 		types[aggregate] = nil
 	}
 	sub := &Subscription{
-		Events: eventQueue,
-		types:  types,
+		Events:     eventQueue,
+		types:      types,
+		dropOnFull: dropOnFull,
 	}
 
 	subsMutext.Lock()
@@ -39,10 +69,12 @@ func SubscribeAggregates(eventQueue chan Event, aggregates ...AggregateType) *Su
 
 // SubscribeEventTypes subscribes for the given event types
 // if no event types are provided the subscription is for all events of the aggregate
+// events are dropped if eventQueue is full
 func SubscribeEventTypes(eventQueue chan Event, types map[AggregateType][]EventType) *Subscription {
 	sub := &Subscription{
-		Events: eventQueue,
-		types:  types,
+		Events:     eventQueue,
+		types:      types,
+		dropOnFull: true,
 	}
 
 	subsMutext.Lock()
@@ -67,17 +99,13 @@ func (es *Eventstore) notify(events []Event) {
 			eventTypes := sub.types[event.Aggregate().Type]
 			//subscription for all events
 			if len(eventTypes) == 0 {
-				sub.Events <- event
+				sub.send(event)
 				continue
 			}
 			//subscription for certain events
 			for _, eventType := range eventTypes {
 				if event.Type() == eventType {
-					select {
-					case sub.Events <- event:
-					default:
-						logging.Debug("unable to push event")
-					}
+					sub.send(event)
 					break
 				}
 			}
@@ -85,9 +113,40 @@ func (es *Eventstore) notify(events []Event) {
 	}
 }
 
+// send passes the event to the subscription,
+// if the subscription drops events on a full channel the dropped events are counted and logged
+func (s *Subscription) send(event Event) {
+	if !s.dropOnFull {
+		s.Events <- event
+		return
+	}
+	select {
+	case s.Events <- event:
+	default:
+		s.dropped++
+		logging.WithFields(
+			"aggregate_type", event.Aggregate().Type,
+			"event_type", event.Type(),
+			"dropped", s.dropped,
+		).Warn("subscription buffer full, event dropped")
+	}
+}
+
+// Dropped returns the amount of events dropped because the buffer of the subscription was full
+func (s *Subscription) Dropped() uint64 {
+	subsMutext.Lock()
+	defer subsMutext.Unlock()
+	return s.dropped
+}
+
+// Unsubscribe removes the subscription and closes its event channel.
+// Calling it more than once has no effect.
 func (s *Subscription) Unsubscribe() {
 	subsMutext.Lock()
 	defer subsMutext.Unlock()
+	if s.closed {
+		return
+	}
 	for aggregate := range s.types {
 		subs, ok := subscriptions[aggregate]
 		if !ok {
@@ -100,9 +159,12 @@ func (s *Subscription) Unsubscribe() {
 				subs = subs[:len(subs)-1]
 			}
 		}
+		if len(subs) == 0 {
+			delete(subscriptions, aggregate)
+			continue
+		}
+		subscriptions[aggregate] = subs
 	}
-	_, ok := <-s.Events
-	if ok {
-		close(s.Events)
-	}
+	s.closed = true
+	close(s.Events)
 }
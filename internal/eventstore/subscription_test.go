@@ -0,0 +1,200 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEventstore_Subscribe(t *testing.T) {
+	type fields struct {
+		pusher *testPusher
+	}
+	type args struct {
+		aggregateTypes []AggregateType
+	}
+	type res struct {
+		subscribeErr bool
+		pushErr      bool
+		eventCount   int
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no aggregate types",
+			fields: fields{
+				pusher: &testPusher{t: t},
+			},
+			args: args{},
+			res: res{
+				subscribeErr: true,
+			},
+		},
+		{
+			name: "pushed event delivered",
+			fields: fields{
+				pusher: &testPusher{
+					t: t,
+					events: []Event{
+						&BaseEvent{
+							Agg: &Aggregate{
+								ID:            "1",
+								Type:          "test.aggregate",
+								ResourceOwner: "caos",
+								InstanceID:    "zitadel",
+							},
+							Data:      []byte(nil),
+							User:      "editorUser",
+							EventType: "test.event",
+						},
+					},
+				},
+			},
+			args: args{
+				aggregateTypes: []AggregateType{"test.aggregate"},
+			},
+			res: res{
+				eventCount: 1,
+			},
+		},
+		{
+			name: "other aggregate type not delivered",
+			fields: fields{
+				pusher: &testPusher{
+					t: t,
+					events: []Event{
+						&BaseEvent{
+							Agg: &Aggregate{
+								ID:            "1",
+								Type:          "test.aggregate",
+								ResourceOwner: "caos",
+								InstanceID:    "zitadel",
+							},
+							Data:      []byte(nil),
+							User:      "editorUser",
+							EventType: "test.event",
+						},
+					},
+				},
+			},
+			args: args{
+				aggregateTypes: []AggregateType{"other.aggregate"},
+			},
+			res: res{
+				eventCount: 0,
+			},
+		},
+		{
+			name: "failed push not delivered",
+			fields: fields{
+				pusher: &testPusher{
+					t:    t,
+					errs: []error{errors.New("rollback")},
+				},
+			},
+			args: args{
+				aggregateTypes: []AggregateType{"test.aggregate"},
+			},
+			res: res{
+				pushErr:    true,
+				eventCount: 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventInterceptors = map[EventType]eventTypeInterceptors{}
+			es := &Eventstore{
+				pusher: tt.fields.pusher,
+			}
+			sub, err := es.Subscribe(tt.args.aggregateTypes...)
+			if (err != nil) != tt.res.subscribeErr {
+				t.Fatalf("Eventstore.Subscribe() error = %v, wantErr %v", err, tt.res.subscribeErr)
+			}
+			if tt.res.subscribeErr {
+				return
+			}
+			defer sub.Unsubscribe()
+
+			_, err = es.Push(context.Background(),
+				newTestEvent(
+					"1",
+					"",
+					func() interface{} {
+						return []byte(nil)
+					},
+					false,
+				),
+			)
+			if (err != nil) != tt.res.pushErr {
+				t.Fatalf("Eventstore.Push() error = %v, wantErr %v", err, tt.res.pushErr)
+			}
+			if len(sub.Events) != tt.res.eventCount {
+				t.Errorf("expected %d events, got %d", tt.res.eventCount, len(sub.Events))
+			}
+		})
+	}
+}
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	es := new(Eventstore)
+	first, err := es.Subscribe("test.unsubscribe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := es.Subscribe("test.unsubscribe")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.Unsubscribe()
+	// a second call must not panic on the closed channel
+	first.Unsubscribe()
+
+	if _, ok := <-first.Events; ok {
+		t.Error("expected events channel to be closed")
+	}
+	subsMutext.Lock()
+	subs := subscriptions["test.unsubscribe"]
+	subsMutext.Unlock()
+	if len(subs) != 1 || subs[0] != second {
+		t.Errorf("expected only the second subscription to remain, got %v", subs)
+	}
+
+	second.Unsubscribe()
+	subsMutext.Lock()
+	_, ok := subscriptions["test.unsubscribe"]
+	subsMutext.Unlock()
+	if ok {
+		t.Error("expected aggregate type to be removed from subscriptions")
+	}
+}
+
+func TestSubscription_Dropped(t *testing.T) {
+	es := new(Eventstore)
+	sub, err := es.Subscribe("test.dropped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	events := make([]Event, subscriptionBufferSize+2)
+	for i := range events {
+		events[i] = &BaseEvent{
+			Agg:       &Aggregate{ID: "1", Type: "test.dropped"},
+			EventType: "test.event",
+		}
+	}
+	es.notify(events)
+
+	if len(sub.Events) != subscriptionBufferSize {
+		t.Errorf("expected %d buffered events, got %d", subscriptionBufferSize, len(sub.Events))
+	}
+	if dropped := sub.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 dropped events, got %d", dropped)
+	}
+}
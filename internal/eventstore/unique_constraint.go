@@ -1,5 +1,12 @@
 package eventstore
 
+import (
+	"context"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
 type UniqueConstraint struct {
 	// UniqueType is the table name for the unique constraint
 	UniqueType string
@@ -11,6 +18,9 @@ type UniqueConstraint struct {
 	ErrorMessage string
 	// IsGlobal defines if the unique constraint is globally unique or just within a single instance
 	IsGlobal bool
+	// CaseSensitive defines if the unique field is stored as is
+	// by default the unique field is lowercased so that the constraint is case insensitive
+	CaseSensitive bool
 }
 
 type UniqueConstraintAction int8
@@ -55,6 +65,40 @@ func NewRemoveInstanceUniqueConstraints() *UniqueConstraint {
 	}
 }
 
+// NewAddCompositeUniqueConstraint creates a unique constraint over the combination of the fields
+// e.g. organisation and username
+func NewAddCompositeUniqueConstraint(
+	uniqueType string,
+	uniqueFields []string,
+	errMessage string) *UniqueConstraint {
+	return NewAddEventUniqueConstraint(uniqueType, CompositeUniqueField(uniqueFields...), errMessage)
+}
+
+// NewRemoveCompositeUniqueConstraint removes the unique constraint
+// created by [NewAddCompositeUniqueConstraint]
+func NewRemoveCompositeUniqueConstraint(
+	uniqueType string,
+	uniqueFields ...string) *UniqueConstraint {
+	return NewRemoveUniqueConstraint(uniqueType, CompositeUniqueField(uniqueFields...))
+}
+
+const compositeUniqueFieldSeparator = "|"
+
+var compositeUniqueFieldEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	compositeUniqueFieldSeparator, `\`+compositeUniqueFieldSeparator,
+)
+
+// CompositeUniqueField combines the fields into a single unique field.
+// The separator is escaped inside the fields, so different tuples never result in the same unique field.
+func CompositeUniqueField(fields ...string) string {
+	escaped := make([]string, len(fields))
+	for i, field := range fields {
+		escaped[i] = compositeUniqueFieldEscaper.Replace(field)
+	}
+	return strings.Join(escaped, compositeUniqueFieldSeparator)
+}
+
 func NewAddGlobalUniqueConstraint(
 	uniqueType,
 	uniqueField,
@@ -78,3 +122,41 @@ func NewRemoveGlobalUniqueConstraint(
 		Action:      UniqueConstraintRemove,
 	}
 }
+
+var _ zerrors.AlreadyExists = (*UniqueConstraintViolationError)(nil)
+
+// UniqueConstraintViolationError is returned if an added unique constraint already exists.
+// It contains the type and field of the collided constraint.
+type UniqueConstraintViolationError struct {
+	*zerrors.AlreadyExistsError
+	UniqueType  string
+	UniqueField string
+}
+
+// ThrowUniqueConstraintViolation returns a [UniqueConstraintViolationError] with the error message of the constraint
+func ThrowUniqueConstraintViolation(parent error, id string, constraint *UniqueConstraint) error {
+	return &UniqueConstraintViolationError{
+		AlreadyExistsError: &zerrors.AlreadyExistsError{ZitadelError: zerrors.CreateZitadelError(parent, id, constraint.ErrorMessage)},
+		UniqueType:         constraint.UniqueType,
+		UniqueField:        constraint.UniqueField,
+	}
+}
+
+func (err *UniqueConstraintViolationError) Unwrap() error {
+	return err.AlreadyExistsError
+}
+
+type importModeKey struct{}
+
+// WithImportMode returns a context in which pushed commands skip the handling of their unique constraints.
+// The constraints are neither added nor removed, so duplicates are not detected.
+// It must only be used for trusted imports, e.g. bulk imports of data already validated by its source.
+func WithImportMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, importModeKey{}, true)
+}
+
+// IsImportMode returns true if the context was created by [WithImportMode]
+func IsImportMode(ctx context.Context) bool {
+	importMode, _ := ctx.Value(importModeKey{}).(bool)
+	return importMode
+}
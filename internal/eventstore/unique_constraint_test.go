@@ -0,0 +1,90 @@
+package eventstore
+
+import (
+	"testing"
+)
+
+func TestCompositeUniqueField(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "single field",
+			fields: []string{"username"},
+			want:   "username",
+		},
+		{
+			name:   "multiple fields",
+			fields: []string{"org", "username"},
+			want:   "org|username",
+		},
+		{
+			name:   "separator in field",
+			fields: []string{"org|a", "username"},
+			want:   `org\|a|username`,
+		},
+		{
+			name:   "escape character in field",
+			fields: []string{`org\`, "username"},
+			want:   `org\\|username`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompositeUniqueField(tt.fields...); got != tt.want {
+				t.Errorf("CompositeUniqueField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompositeUniqueField_collisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    []string
+		collide bool
+	}{
+		{
+			name:    "same tuple",
+			a:       []string{"org", "username"},
+			b:       []string{"org", "username"},
+			collide: true,
+		},
+		{
+			name: "different tuple",
+			a:    []string{"org", "username"},
+			b:    []string{"org2", "username"},
+		},
+		{
+			name: "separator moved between fields",
+			a:    []string{"a|b", "c"},
+			b:    []string{"a", "b|c"},
+		},
+		{
+			name: "escape character moved between fields",
+			a:    []string{`a\`, "b"},
+			b:    []string{"a", `\b`},
+		},
+		{
+			name: "escaped separator and escape character",
+			a:    []string{`a\|`, "b"},
+			b:    []string{`a\`, "|b"},
+		},
+		{
+			name: "concatenated fields",
+			a:    []string{"ab", "c"},
+			b:    []string{"a", "bc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAddCompositeUniqueConstraint("type", tt.a, "Errors.Unique")
+			b := NewAddCompositeUniqueConstraint("type", tt.b, "Errors.Unique")
+			if collide := a.UniqueField == b.UniqueField; collide != tt.collide {
+				t.Errorf("collision of %q (%s) and %q (%s) = %v, want %v", tt.a, a.UniqueField, tt.b, b.UniqueField, collide, tt.collide)
+			}
+		})
+	}
+}
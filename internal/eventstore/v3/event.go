@@ -1,6 +1,7 @@
 package eventstore
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -25,7 +26,7 @@ type event struct {
 	payload   Payload
 }
 
-func commandToEvent(sequence *latestSequence, command eventstore.Command) (_ *event, err error) {
+func commandToEvent(ctx context.Context, sequence *latestSequence, command eventstore.Command) (_ *event, err error) {
 	var payload Payload
 	if command.Payload() != nil {
 		payload, err = json.Marshal(command.Payload())
@@ -34,9 +35,12 @@ func commandToEvent(sequence *latestSequence, command eventstore.Command) (_ *ev
 			return nil, zerrors.ThrowInternal(err, "V3-MInPK", "Errors.Internal")
 		}
 	}
+	if err = eventstore.ValidatePayload(command.Type(), payload); err != nil {
+		return nil, err
+	}
 	return &event{
 		aggregate: sequence.aggregate,
-		creator:   command.Creator(),
+		creator:   eventstore.EditorUser(ctx, command),
 		revision:  command.Revision(),
 		typ:       command.Type(),
 		payload:   payload,
@@ -1,12 +1,14 @@
 package eventstore
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/eventstore"
 )
 
@@ -119,10 +121,45 @@ func Test_commandToEvent(t *testing.T) {
 			}
 		}
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := commandToEvent(tt.args.sequence, tt.args.command)
+			got, err := commandToEvent(context.Background(), tt.args.sequence, tt.args.command)
 
 			tt.want.err(t, err)
 			assert.Equal(t, tt.want.event, got)
 		})
 	}
 }
+
+func Test_commandToEvent_editorUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		command eventstore.Command
+		want    string
+	}{
+		{
+			name:    "explicit creator",
+			ctx:     authz.SetCtxData(context.Background(), authz.CtxData{UserID: "ctx-user"}),
+			command: &mockCommand{aggregate: mockAggregate("V3-Ed1tr")},
+			want:    "creator",
+		},
+		{
+			name:    "creator from context",
+			ctx:     authz.SetCtxData(context.Background(), authz.CtxData{UserID: "ctx-user"}),
+			command: &mockAnonymousCommand{mockCommand{aggregate: mockAggregate("V3-Ed1tr")}},
+			want:    "ctx-user",
+		},
+		{
+			name:    "system fallback",
+			ctx:     context.Background(),
+			command: &mockAnonymousCommand{mockCommand{aggregate: mockAggregate("V3-Ed1tr")}},
+			want:    authz.SystemEditorID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commandToEvent(tt.ctx, &latestSequence{aggregate: mockAggregate("V3-Ed1tr")}, tt.command)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Creator())
+		})
+	}
+}
@@ -3,7 +3,10 @@ package eventstore
 import (
 	"context"
 
+	"github.com/zitadel/logging"
+
 	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
 )
 
 var (
@@ -15,19 +18,66 @@ var (
 
 type Eventstore struct {
 	client *database.DB
+	// creationDate defines the timestamp used as creation date of the pushed events
+	creationDate string
+	// compressionThreshold is the size in bytes above which payloads are stored gzipped, disabled if not positive
+	compressionThreshold int
 }
 
-func NewEventstore(client *database.DB) *Eventstore {
+type Option func(*Eventstore)
+
+// WithCreationDate defines which timestamp is used as creation date of pushed events,
+// see [eventstore.CreationDateDefault], [eventstore.CreationDateStatement] and [eventstore.CreationDateTransaction].
+// All events of a push are inserted by a single statement.
+// Unknown values fall back to the default.
+func WithCreationDate(creationDate string) Option {
+	return func(es *Eventstore) {
+		switch creationDate {
+		case eventstore.CreationDateDefault, eventstore.CreationDateStatement, eventstore.CreationDateTransaction:
+			es.creationDate = creationDate
+		default:
+			logging.WithFields("creationDate", creationDate).Warn("unknown creation date of events, default is used")
+		}
+	}
+}
+
+// WithPayloadCompression stores payloads larger than threshold bytes gzipped,
+// see [eventstore.CompressPayload]. The querier decompresses them while scanning the events.
+// Compression is disabled if threshold is not positive.
+func WithPayloadCompression(threshold int) Option {
+	return func(es *Eventstore) {
+		es.compressionThreshold = threshold
+	}
+}
+
+func NewEventstore(client *database.DB, opts ...Option) *Eventstore {
+	es := &Eventstore{client: client}
+	for _, opt := range opts {
+		opt(es)
+	}
+
 	switch client.Type() {
 	case "cockroach":
-		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d)"
+		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, " + creationDateExpression(es.creationDate, "hlc_to_timestamp(cluster_logical_timestamp())") + ", cluster_logical_timestamp(), $%d)"
 		uniqueConstraintPlaceholderFmt = "('%s', '%s', '%s')"
 	case "postgres":
-		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d)"
+		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, " + creationDateExpression(es.creationDate, "statement_timestamp()") + ", EXTRACT(EPOCH FROM clock_timestamp()), $%d)"
 		uniqueConstraintPlaceholderFmt = "(%s, %s, %s)"
 	}
 
-	return &Eventstore{client: client}
+	return es
+}
+
+// creationDateExpression returns the sql expression of the creation date, defaultExpression is used for [eventstore.CreationDateDefault]
+func creationDateExpression(creationDate, defaultExpression string) string {
+	switch creationDate {
+	case eventstore.CreationDateStatement:
+		return "statement_timestamp()"
+	case eventstore.CreationDateTransaction:
+		return "transaction_timestamp()"
+	default:
+		return defaultExpression
+	}
 }
 
 func (es *Eventstore) Health(ctx context.Context) error {
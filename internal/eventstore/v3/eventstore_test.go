@@ -0,0 +1,67 @@
+package eventstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/dialect"
+	"github.com/zitadel/zitadel/internal/database/postgres"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestNewEventstore_creationDate(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           dialect.Database
+		creationDate string
+		want         string
+	}{
+		{
+			name:         "cockroach default",
+			db:           new(cockroach.Config),
+			creationDate: eventstore.CreationDateDefault,
+			want:         "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d)",
+		},
+		{
+			name:         "cockroach statement",
+			db:           new(cockroach.Config),
+			creationDate: eventstore.CreationDateStatement,
+			want:         "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), cluster_logical_timestamp(), $%d)",
+		},
+		{
+			name:         "cockroach transaction",
+			db:           new(cockroach.Config),
+			creationDate: eventstore.CreationDateTransaction,
+			want:         "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, transaction_timestamp(), cluster_logical_timestamp(), $%d)",
+		},
+		{
+			name:         "cockroach unknown",
+			db:           new(cockroach.Config),
+			creationDate: "unknown",
+			want:         "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d)",
+		},
+		{
+			name:         "postgres default",
+			db:           new(postgres.Config),
+			creationDate: eventstore.CreationDateDefault,
+			want:         "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d)",
+		},
+		{
+			name:         "postgres transaction",
+			db:           new(postgres.Config),
+			creationDate: eventstore.CreationDateTransaction,
+			want:         "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, transaction_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NewEventstore(&database.DB{Database: tt.db}, WithCreationDate(tt.creationDate))
+			assert.Equal(t, tt.want, pushPlaceholderFmt)
+		})
+	}
+	// reset the format for the other tests
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+}
@@ -42,6 +42,37 @@ func (m *mockCommand) UniqueConstraints() []*eventstore.UniqueConstraint {
 	return m.constraints
 }
 
+// mockRequirerCommand must only be pushed if its aggregate exists
+type mockRequirerCommand struct {
+	mockCommand
+}
+
+// RequiresExistingAggregate implements [eventstore.AggregateRequirer]
+func (m *mockRequirerCommand) RequiresExistingAggregate() bool {
+	return true
+}
+
+// mockTypedCommand is a [mockCommand] of the given event type
+type mockTypedCommand struct {
+	mockCommand
+	typ eventstore.EventType
+}
+
+// Type implements [eventstore.Command]
+func (m *mockTypedCommand) Type() eventstore.EventType {
+	return m.typ
+}
+
+// mockAnonymousCommand is a [mockCommand] without creator
+type mockAnonymousCommand struct {
+	mockCommand
+}
+
+// Creator implements [eventstore.Command]
+func (m *mockAnonymousCommand) Creator() string {
+	return ""
+}
+
 func mockEvent(aggregate *eventstore.Aggregate, sequence uint64, payload Payload) eventstore.Event {
 	return &event{
 		aggregate: aggregate,
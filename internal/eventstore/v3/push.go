@@ -4,22 +4,41 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/zitadel/logging"
 
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
+// Push stores the commands as events in a single transaction.
+// Commands of aggregates with an unknown or outdated version are rejected before the transaction is started,
+// see [eventstore.RegisterAggregateVersion].
+// The push fails if a payload is rejected by the validator of its event type, see [eventstore.RegisterPayloadValidator].
+// Unique constraints are not handled if the context is in import mode, see [eventstore.WithImportMode].
+// The transaction is started with the options of [eventstore.WithPushTxOptions].
 func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command) (events []eventstore.Event, err error) {
-	tx, err := es.client.BeginTx(ctx, nil)
+	txOpts := eventstore.PushTxOptions(ctx)
+	if txOpts != nil && txOpts.ReadOnly {
+		return nil, zerrors.ThrowInvalidArgument(nil, "V3-Tx0ro", "events cannot be pushed in a read-only transaction")
+	}
+	for _, command := range commands {
+		instanceID, err := eventstore.PushInstanceID(ctx, command)
+		if err != nil {
+			return nil, err
+		}
+		command.Aggregate().InstanceID = instanceID
+		if err = eventstore.ValidateAggregateVersion(command.Aggregate().Type, command.Aggregate().Version); err != nil {
+			return nil, err
+		}
+	}
+	tx, err := es.client.BeginTx(ctx, txOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -37,11 +56,15 @@ func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command)
 			return err
 		}
 
-		events, err = insertEvents(ctx, tx, sequences, commands)
+		events, err = insertEvents(ctx, tx, sequences, commands, es.compressionThreshold)
 		if err != nil {
 			return err
 		}
 
+		// the constraints are neither added nor removed during imports
+		if eventstore.IsImportMode(ctx) {
+			return nil
+		}
 		return handleUniqueConstraints(ctx, tx, commands)
 	})
 
@@ -55,8 +78,8 @@ func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command)
 //go:embed push.sql
 var pushStmt string
 
-func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence, commands []eventstore.Command) ([]eventstore.Event, error) {
-	events, placeholders, args, err := mapCommands(commands, sequences)
+func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence, commands []eventstore.Command, compressionThreshold int) ([]eventstore.Event, error) {
+	events, placeholders, args, err := mapCommands(ctx, commands, sequences, compressionThreshold)
 	if err != nil {
 		return nil, err
 	}
@@ -76,14 +99,11 @@ func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence,
 	}
 
 	if err := rows.Err(); err != nil {
-		pgErr := new(pgconn.PgError)
-		if errors.As(err, &pgErr) {
-			// Check if push tries to write an event just written
-			// by another transaction
-			if pgErr.Code == "40001" {
-				// TODO: @livio-a should we return the parent or not?
-				return nil, zerrors.ThrowInvalidArgument(err, "V3-p5xAn", "Errors.AlreadyExists")
-			}
+		// Check if push tries to write an event just written
+		// by another transaction
+		if database.IsPGErrorCode(err, database.PGCodeSerializationFailure) {
+			// TODO: @livio-a should we return the parent or not?
+			return nil, zerrors.ThrowInvalidArgument(err, "V3-p5xAn", "Errors.AlreadyExists")
 		}
 		logging.WithError(rows.Err()).Warn("failed to push events")
 		return nil, zerrors.ThrowInternal(err, "V3-VGnZY", "Errors.Internal")
@@ -94,7 +114,9 @@ func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence,
 
 const argsPerCommand = 10
 
-func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (events []eventstore.Event, placeholders []string, args []any, err error) {
+// mapCommands maps the commands to the events and the arguments of the insert statement.
+// Payloads larger than compressionThreshold bytes are stored gzipped, the returned events keep the original payload.
+func mapCommands(ctx context.Context, commands []eventstore.Command, sequences []*latestSequence, compressionThreshold int) (events []eventstore.Event, placeholders []string, args []any, err error) {
 	events = make([]eventstore.Event, len(commands))
 	args = make([]any, 0, len(commands)*argsPerCommand)
 	placeholders = make([]string, len(commands))
@@ -110,9 +132,13 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 			// added return for linting
 			return nil, nil, nil, nil
 		}
+		// the sequence was increased if the aggregate was created by a previous command of the push
+		if sequence.sequence == 0 && eventstore.RequiresExistingAggregate(command) {
+			return nil, nil, nil, zerrors.ThrowNotFoundf(nil, "V3-Rq7xe", "aggregate %s %s not found", command.Aggregate().Type, command.Aggregate().ID)
+		}
 		sequence.sequence++
 
-		events[i], err = commandToEvent(sequence, command)
+		events[i], err = commandToEvent(ctx, sequence, command)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -134,6 +160,10 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 		if err != nil {
 			return nil, nil, nil, zerrors.ThrowInternal(err, "V3-JoZEp", "Errors.Internal")
 		}
+		payload, err := eventstore.CompressPayload(events[i].(*event).payload, compressionThreshold)
+		if err != nil {
+			return nil, nil, nil, zerrors.ThrowInternal(err, "V3-Gz7pq", "Errors.Internal")
+		}
 		args = append(args,
 			events[i].(*event).aggregate.InstanceID,
 			events[i].(*event).aggregate.ResourceOwner,
@@ -142,7 +172,7 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 			revision,
 			events[i].(*event).creator,
 			events[i].(*event).typ,
-			events[i].(*event).payload,
+			Payload(payload),
 			events[i].(*event).sequence,
 			i,
 		)
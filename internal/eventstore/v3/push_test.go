@@ -1,15 +1,25 @@
 package eventstore
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	_ "embed"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/database/cockroach"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 func Test_mapCommands(t *testing.T) {
@@ -242,7 +252,7 @@ func Test_mapCommands(t *testing.T) {
 				cause := recover()
 				assert.Equal(t, tt.want.shouldPanic, cause != nil)
 			}()
-			gotEvents, gotPlaceHolders, gotArgs, err := mapCommands(tt.args.commands, tt.args.sequences)
+			gotEvents, gotPlaceHolders, gotArgs, err := mapCommands(context.Background(), tt.args.commands, tt.args.sequences, 0)
 			tt.want.err(t, err)
 
 			assert.ElementsMatch(t, tt.want.events, gotEvents)
@@ -251,3 +261,330 @@ func Test_mapCommands(t *testing.T) {
 		})
 	}
 }
+
+func Test_mapCommands_requiresExistingAggregate(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []eventstore.Command
+		sequence uint64
+		wantErr  func(error) bool
+	}{
+		{
+			name: "aggregate exists",
+			commands: []eventstore.Command{
+				&mockRequirerCommand{mockCommand{aggregate: mockAggregate("V3-Rq7xe")}},
+			},
+			sequence: 3,
+		},
+		{
+			name: "aggregate created in same push",
+			commands: []eventstore.Command{
+				&mockCommand{aggregate: mockAggregate("V3-Rq7xe")},
+				&mockRequirerCommand{mockCommand{aggregate: mockAggregate("V3-Rq7xe")}},
+			},
+		},
+		{
+			name: "aggregate not found",
+			commands: []eventstore.Command{
+				&mockRequirerCommand{mockCommand{aggregate: mockAggregate("V3-Rq7xe")}},
+			},
+			wantErr: zerrors.IsNotFound,
+		},
+	}
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequences := []*latestSequence{
+				{aggregate: mockAggregate("V3-Rq7xe"), sequence: tt.sequence},
+			}
+			events, _, _, err := mapCommands(context.Background(), tt.commands, sequences, 0)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, events, len(tt.commands))
+		})
+	}
+}
+
+func Test_mapCommands_compression(t *testing.T) {
+	large := map[string]string{"attributes": strings.Repeat("a", 100)}
+	tests := []struct {
+		name           string
+		threshold      int
+		wantCompressed bool
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+		},
+		{
+			name:      "below threshold",
+			threshold: 1000,
+		},
+		{
+			name:           "above threshold",
+			threshold:      10,
+			wantCompressed: true,
+		},
+	}
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequences := []*latestSequence{
+				{aggregate: mockAggregate("V3-Gz7pq")},
+			}
+			events, _, args, err := mapCommands(context.Background(), []eventstore.Command{
+				&mockCommand{aggregate: mockAggregate("V3-Gz7pq"), payload: large},
+			}, sequences, tt.threshold)
+			require.NoError(t, err)
+
+			stored, ok := args[7].(Payload)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantCompressed, eventstore.IsCompressedPayload(stored))
+			payload, err := eventstore.DecompressPayload(stored)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(events[0].DataAsBytes()), string(payload))
+			// the pushed event keeps the original payload
+			assert.False(t, eventstore.IsCompressedPayload(events[0].DataAsBytes()))
+		})
+	}
+}
+
+func TestEventstore_Push_rejected(t *testing.T) {
+	eventstore.RegisterAggregateVersion("versioned", "v2")
+	versioned := func(version eventstore.Version) *eventstore.Aggregate {
+		aggregate := mockAggregate("V3-Ln3ka")
+		aggregate.Type = "versioned"
+		aggregate.Version = version
+		return aggregate
+	}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		commands []eventstore.Command
+		wantErr  func(error) bool
+	}{
+		{
+			name: "outdated aggregate version",
+			ctx:  context.Background(),
+			commands: []eventstore.Command{
+				&mockCommand{aggregate: versioned("v1")},
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name: "read-only transaction",
+			ctx:  eventstore.WithPushTxOptions(context.Background(), &sql.TxOptions{ReadOnly: true}),
+			commands: []eventstore.Command{
+				&mockCommand{aggregate: mockAggregate("V3-Tx0ro")},
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name: "unknown aggregate version",
+			ctx:  context.Background(),
+			commands: []eventstore.Command{
+				&mockCommand{aggregate: versioned("v3")},
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer client.Close()
+
+			es := NewEventstore(&database.DB{DB: client, Database: new(cockroach.Config)})
+			_, err = es.Push(authz.WithInstanceID(tt.ctx, "instance"), tt.commands...)
+			assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			// the transaction must not be started
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEventstore_Push_payloadValidation(t *testing.T) {
+	eventType := eventstore.EventType(t.Name() + ".added")
+	eventstore.RegisterPayloadValidator(eventType, func(payload []byte) error {
+		var fields map[string]any
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return err
+		}
+		if _, ok := fields["name"]; !ok {
+			return errors.New("name is required")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name    string
+		payload any
+		wantErr func(error) bool
+	}{
+		{
+			name:    "missing required field",
+			payload: map[string]any{"other": "value"},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:    "valid payload",
+			payload: map[string]any{"name": "value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer client.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec("SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("WITH existing AS").
+				WillReturnRows(sqlmock.NewRows([]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"}))
+			if tt.wantErr != nil {
+				// the invalid event must not be inserted
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery("INSERT INTO eventstore.events2").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "position"}).AddRow(time.Now(), 1.1))
+				mock.ExpectExec("RELEASE SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			}
+
+			es := NewEventstore(&database.DB{DB: client, Database: new(cockroach.Config)})
+			_, err = es.Push(context.Background(), &mockTypedCommand{
+				mockCommand: mockCommand{aggregate: mockAggregate("V3-Gq0tk"), payload: tt.payload},
+				typ:         eventType,
+			})
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEventstore_Push_importMode(t *testing.T) {
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		wantConstraints bool
+	}{
+		{
+			name:            "constraints handled",
+			ctx:             context.Background(),
+			wantConstraints: true,
+		},
+		{
+			name: "import mode",
+			ctx:  eventstore.WithImportMode(context.Background()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer client.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec("SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("WITH existing AS").
+				WillReturnRows(sqlmock.NewRows([]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"}))
+			mock.ExpectQuery("INSERT INTO eventstore.events2").
+				WillReturnRows(sqlmock.NewRows([]string{"created_at", "position"}).AddRow(time.Now(), 1.1))
+			if tt.wantConstraints {
+				mock.ExpectExec("INSERT INTO eventstore.unique_constraints").WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+			mock.ExpectExec("RELEASE SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectCommit()
+
+			es := NewEventstore(&database.DB{DB: client, Database: new(cockroach.Config)})
+			_, err = es.Push(tt.ctx, &mockCommand{
+				aggregate: mockAggregate("V3-Im7rt"),
+				constraints: []*eventstore.UniqueConstraint{
+					eventstore.NewAddEventUniqueConstraint("type", "field", "Errors.Unique"),
+				},
+			})
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// txOptionsConn records the options of the started transaction
+type txOptionsConn struct {
+	driver.Conn
+	opts *driver.TxOptions
+}
+
+func (c *txOptionsConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.opts = &opts
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *txOptionsConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+type txOptionsConnector struct {
+	conn *txOptionsConn
+	drv  driver.Driver
+}
+
+func (c *txOptionsConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c *txOptionsConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+func TestEventstore_Push_txOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *sql.TxOptions
+		wantOpts driver.TxOptions
+	}{
+		{
+			name:     "default",
+			wantOpts: driver.TxOptions{},
+		},
+		{
+			name:     "isolation level",
+			opts:     &sql.TxOptions{Isolation: sql.LevelReadCommitted},
+			wantOpts: driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelReadCommitted)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.NewWithDSN("v3_push_tx_options_" + tt.name)
+			require.NoError(t, err)
+			defer client.Close()
+			mockConn, err := client.Driver().Open("v3_push_tx_options_" + tt.name)
+			require.NoError(t, err)
+			conn := &txOptionsConn{Conn: mockConn}
+			txClient := sql.OpenDB(&txOptionsConnector{conn: conn, drv: client.Driver()})
+			defer txClient.Close()
+
+			// the push is aborted after the transaction was started
+			mock.ExpectBegin()
+			mock.ExpectExec("SAVEPOINT cockroach_restart").WillReturnError(sql.ErrConnDone)
+			mock.ExpectRollback()
+
+			ctx := context.Background()
+			if tt.opts != nil {
+				ctx = eventstore.WithPushTxOptions(ctx, tt.opts)
+			}
+			es := NewEventstore(&database.DB{DB: txClient, Database: new(cockroach.Config)})
+			_, err = es.Push(ctx, &mockCommand{aggregate: mockAggregate("V3-Tx1ro")})
+			require.Error(t, err)
+			require.NotNil(t, conn.opts, "transaction not started")
+			assert.Equal(t, tt.wantOpts, *conn.opts)
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/zitadel/logging"
 
-	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -23,7 +22,7 @@ type latestSequence struct {
 var latestSequencesStmt string
 
 func latestSequences(ctx context.Context, tx *sql.Tx, commands []eventstore.Command) ([]*latestSequence, error) {
-	sequences := commandsToSequences(ctx, commands)
+	sequences := commandsToSequences(commands)
 
 	conditions, args := sequencesToSql(sequences)
 	rows, err := tx.QueryContext(ctx, fmt.Sprintf(latestSequencesStmt, strings.Join(conditions, " UNION ALL ")), args...)
@@ -66,17 +65,13 @@ func searchSequence(sequences []*latestSequence, aggregateType eventstore.Aggreg
 	return nil
 }
 
-func commandsToSequences(ctx context.Context, commands []eventstore.Command) []*latestSequence {
+func commandsToSequences(commands []eventstore.Command) []*latestSequence {
 	sequences := make([]*latestSequence, 0, len(commands))
 
 	for _, command := range commands {
 		if searchSequenceByCommand(sequences, command) != nil {
 			continue
 		}
-
-		if command.Aggregate().InstanceID == "" {
-			command.Aggregate().InstanceID = authz.GetInstance(ctx).InstanceID()
-		}
 		sequences = append(sequences, &latestSequence{
 			aggregate: command.Aggregate(),
 		})
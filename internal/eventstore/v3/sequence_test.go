@@ -1,14 +1,12 @@
 package eventstore
 
 import (
-	"context"
 	_ "embed"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/eventstore"
 )
 
@@ -89,7 +87,6 @@ func Test_searchSequence(t *testing.T) {
 func Test_commandsToSequences(t *testing.T) {
 	aggregate := mockAggregate("V3-MKHTF")
 	type args struct {
-		ctx      context.Context
 		commands []eventstore.Command
 	}
 	tests := []struct {
@@ -100,7 +97,6 @@ func Test_commandsToSequences(t *testing.T) {
 		{
 			name: "no command",
 			args: args{
-				ctx:      context.Background(),
 				commands: []eventstore.Command{},
 			},
 			want: []*latestSequence{},
@@ -108,7 +104,6 @@ func Test_commandsToSequences(t *testing.T) {
 		{
 			name: "one command",
 			args: args{
-				ctx: context.Background(),
 				commands: []eventstore.Command{
 					&mockCommand{
 						aggregate: aggregate,
@@ -124,7 +119,6 @@ func Test_commandsToSequences(t *testing.T) {
 		{
 			name: "two commands same aggregate",
 			args: args{
-				ctx: context.Background(),
 				commands: []eventstore.Command{
 					&mockCommand{
 						aggregate: aggregate,
@@ -143,7 +137,6 @@ func Test_commandsToSequences(t *testing.T) {
 		{
 			name: "two commands different aggregates",
 			args: args{
-				ctx: context.Background(),
 				commands: []eventstore.Command{
 					&mockCommand{
 						aggregate: aggregate,
@@ -165,7 +158,6 @@ func Test_commandsToSequences(t *testing.T) {
 		{
 			name: "instance set in command",
 			args: args{
-				ctx: authz.WithInstanceID(context.Background(), "V3-ANV4p"),
 				commands: []eventstore.Command{
 					&mockCommand{
 						aggregate: &eventstore.Aggregate{
@@ -190,37 +182,10 @@ func Test_commandsToSequences(t *testing.T) {
 				},
 			},
 		},
-		{
-			name: "instance from context",
-			args: args{
-				ctx: authz.WithInstanceID(context.Background(), "V3-ANV4p"),
-				commands: []eventstore.Command{
-					&mockCommand{
-						aggregate: &eventstore.Aggregate{
-							ID:            "V3-bF0Sa",
-							Type:          "type",
-							ResourceOwner: "to",
-							Version:       "v1",
-						},
-					},
-				},
-			},
-			want: []*latestSequence{
-				{
-					aggregate: &eventstore.Aggregate{
-						ID:            "V3-bF0Sa",
-						Type:          "type",
-						ResourceOwner: "to",
-						InstanceID:    "V3-ANV4p",
-						Version:       "v1",
-					},
-				},
-			},
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := commandsToSequences(tt.args.ctx, tt.args.commands)
+			got := commandsToSequences(tt.args.commands)
 			assert.ElementsMatch(t, tt.want, got)
 		})
 	}
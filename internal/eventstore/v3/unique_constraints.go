@@ -20,6 +20,8 @@ var (
 	deleteConstraintStmt string
 	//go:embed unique_constraints_delete_placeholders.sql
 	deleteConstraintPlaceholdersStmt string
+	//go:embed unique_constraints_delete_case_sensitive_placeholders.sql
+	deleteCaseSensitiveConstraintPlaceholdersStmt string
 	//go:embed unique_constraints_add.sql
 	addConstraintStmt string
 )
@@ -41,12 +43,18 @@ func handleUniqueConstraints(ctx context.Context, tx *sql.Tx, commands []eventst
 			}
 			switch constraint.Action {
 			case eventstore.UniqueConstraintAdd:
-				constraint.UniqueField = strings.ToLower(constraint.UniqueField)
+				if !constraint.CaseSensitive {
+					constraint.UniqueField = strings.ToLower(constraint.UniqueField)
+				}
 				addPlaceholders = append(addPlaceholders, fmt.Sprintf("($%d, $%d, $%d)", len(addArgs)+1, len(addArgs)+2, len(addArgs)+3))
 				addArgs = append(addArgs, instanceID, constraint.UniqueType, constraint.UniqueField)
 				addConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint
 			case eventstore.UniqueConstraintRemove:
-				deletePlaceholders = append(deletePlaceholders, fmt.Sprintf(deleteConstraintPlaceholdersStmt, len(deleteArgs)+1, len(deleteArgs)+2, len(deleteArgs)+3))
+				placeholderStmt := deleteConstraintPlaceholdersStmt
+				if constraint.CaseSensitive {
+					placeholderStmt = deleteCaseSensitiveConstraintPlaceholdersStmt
+				}
+				deletePlaceholders = append(deletePlaceholders, fmt.Sprintf(placeholderStmt, len(deleteArgs)+1, len(deleteArgs)+2, len(deleteArgs)+3))
 				deleteArgs = append(deleteArgs, instanceID, constraint.UniqueType, constraint.UniqueField)
 				deleteConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint
 			case eventstore.UniqueConstraintInstanceRemove:
@@ -72,11 +80,10 @@ func handleUniqueConstraints(ctx context.Context, tx *sql.Tx, commands []eventst
 		_, err := tx.ExecContext(ctx, fmt.Sprintf(addConstraintStmt, strings.Join(addPlaceholders, ", ")), addArgs...)
 		if err != nil {
 			logging.WithError(err).Warn("add unique constraint failed")
-			errMessage := "Errors.Internal"
 			if constraint := constraintFromErr(err, addConstraints); constraint != nil {
-				errMessage = constraint.ErrorMessage
+				return eventstore.ThrowUniqueConstraintViolation(err, "V3-DKcYh", constraint)
 			}
-			return zerrors.ThrowAlreadyExists(err, "V3-DKcYh", errMessage)
+			return zerrors.ThrowAlreadyExists(err, "V3-DKcYh", "Errors.Internal")
 		}
 	}
 	return nil
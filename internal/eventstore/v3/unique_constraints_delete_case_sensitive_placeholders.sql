@@ -0,0 +1 @@
+(instance_id = $%[1]d AND unique_type = $%[2]d AND unique_field = $%[3]d)
\ No newline at end of file
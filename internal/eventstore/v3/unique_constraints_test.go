@@ -0,0 +1,201 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func Test_handleUniqueConstraints(t *testing.T) {
+	uniqueViolation := &pgconn.PgError{Code: "23505"}
+	type args struct {
+		constraints []*eventstore.UniqueConstraint
+	}
+	type want struct {
+		stmt string
+		args []driver.Value
+		// dbErr is returned by the database
+		dbErr error
+		err   func(error) bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "add lowercases field",
+			args: args{
+				constraints: []*eventstore.UniqueConstraint{
+					eventstore.NewAddEventUniqueConstraint("type", "User", "Errors.Unique"),
+				},
+			},
+			want: want{
+				stmt: fmt.Sprintf(addConstraintStmt, "($1, $2, $3)"),
+				args: []driver.Value{"instance", "type", "user"},
+			},
+		},
+		{
+			name: "add case sensitive keeps field",
+			args: args{
+				constraints: []*eventstore.UniqueConstraint{
+					{
+						UniqueType:    "type",
+						UniqueField:   "User",
+						ErrorMessage:  "Errors.Unique",
+						Action:        eventstore.UniqueConstraintAdd,
+						CaseSensitive: true,
+					},
+				},
+			},
+			want: want{
+				stmt: fmt.Sprintf(addConstraintStmt, "($1, $2, $3)"),
+				args: []driver.Value{"instance", "type", "User"},
+			},
+		},
+		{
+			name: "User and user collide",
+			args: args{
+				constraints: []*eventstore.UniqueConstraint{
+					eventstore.NewAddEventUniqueConstraint("type", "User", "Errors.Unique"),
+					eventstore.NewAddEventUniqueConstraint("type", "user", "Errors.Unique"),
+				},
+			},
+			want: want{
+				stmt:  fmt.Sprintf(addConstraintStmt, "($1, $2, $3), ($4, $5, $6)"),
+				args:  []driver.Value{"instance", "type", "user", "instance", "type", "user"},
+				dbErr: uniqueViolation,
+				err:   zerrors.IsErrorAlreadyExists,
+			},
+		},
+		{
+			name: "User and user case sensitive don't collide",
+			args: args{
+				constraints: []*eventstore.UniqueConstraint{
+					{
+						UniqueType:    "type",
+						UniqueField:   "User",
+						Action:        eventstore.UniqueConstraintAdd,
+						CaseSensitive: true,
+					},
+					{
+						UniqueType:    "type",
+						UniqueField:   "user",
+						Action:        eventstore.UniqueConstraintAdd,
+						CaseSensitive: true,
+					},
+				},
+			},
+			want: want{
+				stmt: fmt.Sprintf(addConstraintStmt, "($1, $2, $3), ($4, $5, $6)"),
+				args: []driver.Value{"instance", "type", "User", "instance", "type", "user"},
+			},
+		},
+		{
+			name: "remove",
+			args: args{
+				constraints: []*eventstore.UniqueConstraint{
+					eventstore.NewRemoveUniqueConstraint("type", "User"),
+				},
+			},
+			want: want{
+				stmt: fmt.Sprintf(deleteConstraintStmt, fmt.Sprintf(deleteConstraintPlaceholdersStmt, 1, 2, 3)),
+				args: []driver.Value{"instance", "type", "User"},
+			},
+		},
+		{
+			name: "remove case sensitive",
+			args: args{
+				constraints: []*eventstore.UniqueConstraint{
+					{
+						UniqueType:    "type",
+						UniqueField:   "User",
+						Action:        eventstore.UniqueConstraintRemove,
+						CaseSensitive: true,
+					},
+				},
+			},
+			want: want{
+				stmt: fmt.Sprintf(deleteConstraintStmt, "(instance_id = $1 AND unique_type = $2 AND unique_field = $3)"),
+				args: []driver.Value{"instance", "type", "User"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer client.Close()
+
+			mock.ExpectBegin()
+			exec := mock.ExpectExec(tt.want.stmt).WithArgs(tt.want.args...)
+			if tt.want.dbErr != nil {
+				exec.WillReturnError(tt.want.dbErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, int64(len(tt.args.constraints))))
+			}
+
+			tx, err := client.Begin()
+			require.NoError(t, err)
+
+			err = handleUniqueConstraints(context.Background(), tx, []eventstore.Command{
+				&mockCommand{
+					aggregate:   mockAggregate("id"),
+					constraints: tt.args.constraints,
+				},
+			})
+			if tt.want.err != nil {
+				require.True(t, tt.want.err(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_handleUniqueConstraints_violatedConstraint(t *testing.T) {
+	previousFmt := uniqueConstraintPlaceholderFmt
+	uniqueConstraintPlaceholderFmt = "('%s', '%s', '%s')"
+	defer func() { uniqueConstraintPlaceholderFmt = previousFmt }()
+
+	client, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer client.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf(addConstraintStmt, "($1, $2, $3), ($4, $5, $6)")).
+		WithArgs("instance", "type", "user", "instance", "other", "value").
+		WillReturnError(&pgconn.PgError{
+			Code:   "23505",
+			Detail: "Key (instance_id, unique_type, unique_field)=('instance', 'other', 'value') already exists.",
+		})
+
+	tx, err := client.Begin()
+	require.NoError(t, err)
+
+	err = handleUniqueConstraints(context.Background(), tx, []eventstore.Command{
+		&mockCommand{
+			aggregate: mockAggregate("id"),
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewAddEventUniqueConstraint("type", "user", "Errors.Type.AlreadyExists"),
+				eventstore.NewAddEventUniqueConstraint("other", "value", "Errors.Other.AlreadyExists"),
+			},
+		},
+	})
+	require.True(t, zerrors.IsErrorAlreadyExists(err), "unexpected error: %v", err)
+	violation := new(eventstore.UniqueConstraintViolationError)
+	require.ErrorAs(t, err, &violation)
+	require.Equal(t, "other", violation.UniqueType)
+	require.Equal(t, "value", violation.UniqueField)
+	require.Equal(t, "Errors.Other.AlreadyExists", violation.GetMessage())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,8 @@ package eventstore
 
 import (
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -16,3 +18,58 @@ func (v Version) Validate() error {
 	}
 	return nil
 }
+
+// aggregateVersions are the current versions of the registered aggregate types
+var aggregateVersions = map[AggregateType]Version{}
+
+// RegisterAggregateVersion registers the current version of the aggregate type.
+// Pushes of events of the aggregate type are rejected if their version is unknown or lower than the current version.
+func RegisterAggregateVersion(aggregateType AggregateType, version Version) {
+	if aggregateType == "" || version.Validate() != nil {
+		return
+	}
+	aggregateVersions[aggregateType] = version
+}
+
+// ValidateAggregateVersion checks the version against the registered version of the aggregate type.
+// Any version is valid if the aggregate type is not registered.
+func ValidateAggregateVersion(aggregateType AggregateType, version Version) error {
+	current, ok := aggregateVersions[aggregateType]
+	if !ok {
+		return nil
+	}
+	if version.Validate() != nil {
+		return zerrors.ThrowInvalidArgumentf(nil, "V2-Ln3ka", "unknown version %s of aggregate %s", version, aggregateType)
+	}
+	switch compare := version.compare(current); {
+	case compare < 0:
+		return zerrors.ThrowInvalidArgumentf(nil, "V2-Ln3kb", "version %s of aggregate %s is lower than the current version %s", version, aggregateType, current)
+	case compare > 0:
+		return zerrors.ThrowInvalidArgumentf(nil, "V2-Ln3kc", "unknown version %s of aggregate %s, current version is %s", version, aggregateType, current)
+	}
+	return nil
+}
+
+// compare returns -1 if v is lower than other, 1 if it's higher and 0 if they are equal.
+// missing minor and patch versions are treated as 0
+// both versions must be valid
+func (v Version) compare(other Version) int {
+	parts, otherParts := v.parts(), other.parts()
+	for i := range parts {
+		if parts[i] < otherParts[i] {
+			return -1
+		}
+		if parts[i] > otherParts[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) parts() [3]int {
+	var parts [3]int
+	for i, part := range strings.Split(strings.TrimPrefix(string(v), "v"), ".") {
+		parts[i], _ = strconv.Atoi(part)
+	}
+	return parts
+}
@@ -1,6 +1,10 @@
 package eventstore
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
 
 func TestVersion_Validate(t *testing.T) {
 	tests := []struct {
@@ -37,3 +41,60 @@ func TestVersion_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAggregateVersion(t *testing.T) {
+	RegisterAggregateVersion("versioned", "v2.1")
+	defer delete(aggregateVersions, "versioned")
+
+	tests := []struct {
+		name          string
+		aggregateType AggregateType
+		version       Version
+		wantErr       bool
+	}{
+		{
+			name:          "current version",
+			aggregateType: "versioned",
+			version:       "v2.1",
+		},
+		{
+			name:          "current version with patch",
+			aggregateType: "versioned",
+			version:       "v2.1.0",
+		},
+		{
+			name:          "downgrade",
+			aggregateType: "versioned",
+			version:       "v1",
+			wantErr:       true,
+		},
+		{
+			name:          "unknown newer version",
+			aggregateType: "versioned",
+			version:       "v3",
+			wantErr:       true,
+		},
+		{
+			name:          "invalid version",
+			aggregateType: "versioned",
+			version:       "2.1",
+			wantErr:       true,
+		},
+		{
+			name:          "not registered",
+			aggregateType: "unversioned",
+			version:       "v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAggregateVersion(tt.aggregateType, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAggregateVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !zerrors.IsErrorInvalidArgument(err) {
+				t.Errorf("ValidateAggregateVersion() error = %v, want invalid argument", err)
+			}
+		})
+	}
+}
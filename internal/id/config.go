@@ -1,15 +1,35 @@
 package id
 
+import (
+	"errors"
+	"fmt"
+)
+
 const (
 	DefaultWebhookPath = "http://metadata.google.internal/computeMetadata/v1/instance/id"
 )
 
+// Strategy defines how ids are generated
+type Strategy string
+
+const (
+	// StrategySnowflake generates time ordered numeric ids which are unique per machine.
+	// It's the default strategy and requires the machine to be identified uniquely.
+	StrategySnowflake Strategy = "snowflake"
+	// StrategyUUID generates random version 4 UUIDs, no identification of the machine is required.
+	StrategyUUID Strategy = "uuid"
+)
+
 type Config struct {
+	// Strategy used to generate ids, snowflake is used if empty
+	Strategy Strategy
 	// Configuration for the identification of machines.
 	Identification Identification
 }
 
 type Identification struct {
+	// Configuration for using an environment variable to identify a machine.
+	Env Env
 	// Configuration for using private IP to identify a machine.
 	PrivateIp PrivateIp
 	// Configuration for using hostname to identify a machine.
@@ -18,6 +38,15 @@ type Identification struct {
 	Webhook Webhook
 }
 
+type Env struct {
+	// Try to use the value of the environment variable when identifying the machine uniquely,
+	// e.g. a value provided by the kubernetes downward API
+	Enabled bool
+	// Name of the environment variable.
+	// Numeric values up to 65535 are used as machine id, other values are hashed.
+	Name string
+}
+
 type PrivateIp struct {
 	// Try to use private IP when identifying the machine uniquely
 	Enabled bool
@@ -39,8 +68,36 @@ type Webhook struct {
 	Headers *map[string]string
 }
 
-func Configure(config *Config) {
-	if config != nil {
-		GeneratorConfig = config
+// Configure sets the config used by [DefaultGenerator] and [SonyFlakeGenerator].
+// An error is returned if the config is invalid.
+func Configure(config *Config) error {
+	if config == nil {
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+	GeneratorConfig = config
+	return nil
+}
+
+func (c *Config) validate() error {
+	switch c.Strategy {
+	case "", StrategySnowflake:
+		return c.Identification.validate()
+	case StrategyUUID:
+		return nil
+	default:
+		return fmt.Errorf("unknown id strategy %q", c.Strategy)
+	}
+}
+
+func (i *Identification) validate() error {
+	if i.Env.Enabled && i.Env.Name == "" {
+		return errors.New("name of the environment variable to identify the machine is missing")
+	}
+	if !i.Env.Enabled && !i.PrivateIp.Enabled && !i.Hostname.Enabled && !i.Webhook.Enabled {
+		return errors.New("no machine identification method enabled")
 	}
+	return nil
 }
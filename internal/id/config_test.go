@@ -0,0 +1,77 @@
+package id
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{
+			name: "nil config",
+		},
+		{
+			name: "default strategy",
+			config: &Config{
+				Identification: Identification{Hostname: Hostname{Enabled: true}},
+			},
+		},
+		{
+			name: "snowflake",
+			config: &Config{
+				Strategy:       StrategySnowflake,
+				Identification: Identification{Env: Env{Enabled: true, Name: "MACHINE_ID"}},
+			},
+		},
+		{
+			name: "snowflake without identification",
+			config: &Config{
+				Strategy: StrategySnowflake,
+			},
+			wantErr: true,
+		},
+		{
+			name: "env without name",
+			config: &Config{
+				Identification: Identification{Env: Env{Enabled: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "uuid without identification",
+			config: &Config{
+				Strategy: StrategyUUID,
+			},
+		},
+		{
+			name: "unknown strategy",
+			config: &Config{
+				Strategy:       "ulid",
+				Identification: Identification{Hostname: Hostname{Enabled: true}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := GeneratorConfig
+			t.Cleanup(func() { GeneratorConfig = previous })
+
+			err := Configure(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, previous, GeneratorConfig)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.config != nil {
+				assert.Equal(t, tt.config, GeneratorConfig)
+			}
+		})
+	}
+}
@@ -1,5 +1,34 @@
 package id
 
+import (
+	"github.com/google/uuid"
+	"github.com/zitadel/logging"
+)
+
 type Generator interface {
 	Next() (string, error)
 }
+
+var uuidGen Generator = new(uuidGenerator)
+
+// DefaultGenerator returns the generator of the configured strategy
+// the function panics if the generator cannot be created
+func DefaultGenerator() Generator {
+	if GeneratorConfig == nil {
+		logging.Panic("cannot create a generator, generator has not been configured")
+	}
+	if GeneratorConfig.Strategy == StrategyUUID {
+		return uuidGen
+	}
+	return SonyFlakeGenerator()
+}
+
+type uuidGenerator struct{}
+
+func (*uuidGenerator) Next() (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,137 @@
+package id
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	snowflakeIDPattern = regexp.MustCompile(`^[0-9]+$`)
+	uuidIDPattern      = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+)
+
+func Test_envID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    uint16
+		wantErr bool
+	}{
+		{
+			name:  "ordinal",
+			value: "3",
+			want:  3,
+		},
+		{
+			name:  "hashed",
+			value: "9f2e5b6c-1d4a-4e0f-8c3b-7a6d5e4f3a2b",
+			want:  0xbbd4,
+		},
+		{
+			name:  "out of range hashed",
+			value: "65536",
+			want:  0xbf7c,
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ZITADEL_TEST_MACHINE_ID", tt.value)
+			got, err := envID("ZITADEL_TEST_MACHINE_ID")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_newSonyflakeGenerator(t *testing.T) {
+	t.Run("shape", func(t *testing.T) {
+		t.Setenv("ZITADEL_TEST_MACHINE_ID", "1")
+		generator, err := newSonyflakeGenerator(Identification{Env: Env{Enabled: true, Name: "ZITADEL_TEST_MACHINE_ID"}})
+		require.NoError(t, err)
+		id, err := generator.Next()
+		require.NoError(t, err)
+		assert.Regexp(t, snowflakeIDPattern, id)
+	})
+	t.Run("no identification", func(t *testing.T) {
+		_, err := newSonyflakeGenerator(Identification{Env: Env{Enabled: true, Name: "ZITADEL_TEST_MACHINE_ID_MISSING"}})
+		assert.Error(t, err)
+	})
+}
+
+func Test_uuidGenerator(t *testing.T) {
+	id, err := new(uuidGenerator).Next()
+	require.NoError(t, err)
+	assert.Regexp(t, uuidIDPattern, id)
+}
+
+func TestGenerators_concurrent(t *testing.T) {
+	const idsPerGenerator = 500
+
+	t.Setenv("ZITADEL_TEST_MACHINE_ID_1", "1")
+	t.Setenv("ZITADEL_TEST_MACHINE_ID_2", "2")
+	snowflake1, err := newSonyflakeGenerator(Identification{Env: Env{Enabled: true, Name: "ZITADEL_TEST_MACHINE_ID_1"}})
+	require.NoError(t, err)
+	snowflake2, err := newSonyflakeGenerator(Identification{Env: Env{Enabled: true, Name: "ZITADEL_TEST_MACHINE_ID_2"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		generators []Generator
+		pattern    *regexp.Regexp
+	}{
+		{
+			name:       "snowflake on different machines",
+			generators: []Generator{snowflake1, snowflake2},
+			pattern:    snowflakeIDPattern,
+		},
+		{
+			name:       "uuid",
+			generators: []Generator{new(uuidGenerator), new(uuidGenerator), new(uuidGenerator)},
+			pattern:    uuidIDPattern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu  sync.Mutex
+				wg  sync.WaitGroup
+				ids = make(map[string]bool, len(tt.generators)*idsPerGenerator)
+			)
+			for _, generator := range tt.generators {
+				wg.Add(1)
+				go func(generator Generator) {
+					defer wg.Done()
+					for i := 0; i < idsPerGenerator; i++ {
+						id, err := generator.Next()
+						if !assert.NoError(t, err) {
+							return
+						}
+						mu.Lock()
+						assert.False(t, ids[id], "duplicate id %s", id)
+						ids[id] = true
+						mu.Unlock()
+					}
+				}(generator)
+			}
+			wg.Wait()
+
+			assert.Len(t, ids, len(tt.generators)*idsPerGenerator)
+			for id := range ids {
+				assert.Regexp(t, tt.pattern, id)
+			}
+		})
+	}
+}
@@ -40,12 +40,11 @@ var (
 // the function panics if the generator cannot be created
 func SonyFlakeGenerator() Generator {
 	if sonyFlakeGenerator == nil {
-		sfg := Generator(&sonyflakeGenerator{
-			sonyflake.NewSonyflake(sonyflake.Settings{
-				MachineID: machineID,
-				StartTime: time.Date(2019, 4, 29, 0, 0, 0, 0, time.UTC),
-			}),
-		})
+		if GeneratorConfig == nil {
+			logging.Panic("cannot create a unique id for the machine, generator has not been configured")
+		}
+		sfg, err := newSonyflakeGenerator(GeneratorConfig.Identification)
+		logging.OnError(err).Panic("none of the enabled methods for identifying the machine succeeded")
 
 		sonyFlakeGenerator = sfg
 	}
@@ -53,6 +52,22 @@ func SonyFlakeGenerator() Generator {
 	return sonyFlakeGenerator
 }
 
+func newSonyflakeGenerator(identification Identification) (Generator, error) {
+	var machineIDErr error
+	flake := sonyflake.NewSonyflake(sonyflake.Settings{
+		MachineID: func() (uint16, error) {
+			var id uint16
+			id, machineIDErr = machineID(identification)
+			return id, machineIDErr
+		},
+		StartTime: time.Date(2019, 4, 29, 0, 0, 0, 0, time.UTC),
+	})
+	if flake == nil {
+		return nil, machineIDErr
+	}
+	return &sonyflakeGenerator{flake}, nil
+}
+
 // the following is a copy of sonyflake (https://github.com/sony/sonyflake/blob/master/sonyflake.go)
 // with the change of using the "POD-IP" if no private ip is found
 func privateIPv4() (net.IP, error) {
@@ -89,43 +104,63 @@ func isPrivateIPv4(ip net.IP) bool {
 		(ip[0] == 10 || ip[0] == 172 && (ip[1] >= 16 && ip[1] < 32) || ip[0] == 192 && ip[1] == 168)
 }
 
-func machineID() (uint16, error) {
-	if GeneratorConfig == nil {
-		logging.Panic("cannot create a unique id for the machine, generator has not been configured")
+func machineID(identification Identification) (uint16, error) {
+	errs := []string{}
+	if identification.Env.Enabled {
+		id, err := envID(identification.Env.Name)
+		if err == nil {
+			return id, nil
+		}
+		errs = append(errs, fmt.Sprintf("failed to get machine id from environment %s", err))
 	}
 
-	errors := []string{}
-	if GeneratorConfig.Identification.PrivateIp.Enabled {
+	if identification.PrivateIp.Enabled {
 		ip, err := lower16BitPrivateIP()
 		if err == nil {
 			return ip, nil
 		}
-		errors = append(errors, fmt.Sprintf("failed to get Private IP address %s", err))
+		errs = append(errs, fmt.Sprintf("failed to get Private IP address %s", err))
 	}
 
-	if GeneratorConfig.Identification.Hostname.Enabled {
+	if identification.Hostname.Enabled {
 		hn, err := hostname()
 		if err == nil {
 			return hn, nil
 		}
-		errors = append(errors, fmt.Sprintf("failed to get Hostname %s", err))
+		errs = append(errs, fmt.Sprintf("failed to get Hostname %s", err))
 	}
 
-	if GeneratorConfig.Identification.Webhook.Enabled {
-		cid, err := metadataWebhookID()
+	if identification.Webhook.Enabled {
+		cid, err := metadataWebhookID(identification.Webhook)
 		if err == nil {
 			return cid, nil
 		}
-		errors = append(errors, fmt.Sprintf("failed to query metadata webhook %s", err))
+		errs = append(errs, fmt.Sprintf("failed to query metadata webhook %s", err))
+	}
+
+	if len(errs) == 0 {
+		errs = append(errs, "No machine identification method enabled.")
 	}
 
-	if len(errors) == 0 {
-		errors = append(errors, "No machine identification method enabled.")
+	return 0, errors.New(strings.Join(errs, ", "))
+}
+
+// envID uses numeric values of the environment variable, e.g. the ordinal of a stateful set, as machine id
+// and hashes all other values, e.g. the uid of a pod
+func envID(name string) (uint16, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, fmt.Errorf("environment variable %s is empty", name)
+	}
+	if id, err := strconv.ParseUint(value, 10, 16); err == nil {
+		return uint16(id), nil
 	}
 
-	logging.WithFields("errors", strings.Join(errors, ", ")).Panic("none of the enabled methods for identifying the machine succeeded")
-	//this return will never happen because of panic one line before
-	return 0, nil
+	h := fnv.New32()
+	if _, err := h.Write([]byte(value)); err != nil {
+		return 0, err
+	}
+	return uint16(h.Sum32()), nil
 }
 
 func lower16BitPrivateIP() (uint16, error) {
@@ -152,8 +187,7 @@ func hostname() (uint16, error) {
 	return uint16(h.Sum32()), nil
 }
 
-func metadataWebhookID() (uint16, error) {
-	webhook := GeneratorConfig.Identification.Webhook
+func metadataWebhookID(webhook Webhook) (uint16, error) {
 	url, err := envsubst.EvalEnv(webhook.Url)
 	if err != nil {
 		url = webhook.Url
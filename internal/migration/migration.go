@@ -58,7 +58,8 @@ func Migrate(ctx context.Context, es *eventstore.Eventstore, migration Migration
 		return nil
 	}
 
-	startedEvent, err := es.Push(ctx, setupStartedCmd(ctx, migration))
+	// the setup steps are system events which don't belong to an instance
+	startedEvent, err := es.Push(eventstore.WithoutInstance(ctx), setupStartedCmd(ctx, migration))
 	if err != nil && !continueOnErr(err) {
 		return err
 	}
@@ -67,7 +68,7 @@ func Migrate(ctx context.Context, es *eventstore.Eventstore, migration Migration
 	err = migration.Execute(ctx, startedEvent[0])
 	logging.WithFields("name", migration.String()).OnError(err).Error("migration failed")
 
-	_, pushErr := es.Push(ctx, setupDoneCmd(ctx, migration, err))
+	_, pushErr := es.Push(eventstore.WithoutInstance(ctx), setupDoneCmd(ctx, migration, err))
 	logging.WithFields("name", migration.String()).OnError(pushErr).Error("migration finish failed")
 	if err != nil {
 		return err
@@ -108,7 +109,7 @@ func (m *cancelMigration) String() string {
 var errCancelStep = zerrors.ThrowError(nil, "MIGRA-zo86K", "migration canceled manually")
 
 func CancelStep(ctx context.Context, es *eventstore.Eventstore, step *SetupStep) error {
-	_, err := es.Push(ctx, setupDoneCmd(ctx, &cancelMigration{name: step.Name}, errCancelStep))
+	_, err := es.Push(eventstore.WithoutInstance(ctx), setupDoneCmd(ctx, &cancelMigration{name: step.Name}, errCancelStep))
 	return err
 }
 
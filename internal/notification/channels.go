@@ -28,13 +28,17 @@ type deliveryMetrics struct {
 }
 
 type channels struct {
-	q        *handlers.NotificationQueries
-	counters counters
+	q           *handlers.NotificationQueries
+	counters    counters
+	emailDryRun bool
+	// suppressions are the recipients which don't receive emails, suppression is disabled if nil
+	suppressions senders.SuppressionStore
 }
 
-func newChannels(q *handlers.NotificationQueries) *channels {
+func newChannels(q *handlers.NotificationQueries, emailDryRun bool, suppressedEmails []string) *channels {
 	c := &channels{
-		q: q,
+		q:           q,
+		emailDryRun: emailDryRun,
 		counters: counters{
 			success: deliveryMetrics{
 				email: "successful_deliveries_email",
@@ -48,6 +52,9 @@ func newChannels(q *handlers.NotificationQueries) *channels {
 			},
 		},
 	}
+	if len(suppressedEmails) > 0 {
+		c.suppressions = senders.NewStaticSuppressions(suppressedEmails)
+	}
 	registerCounter(c.counters.success.email, "Successfully delivered emails")
 	registerCounter(c.counters.failed.email, "Failed email deliveries")
 	registerCounter(c.counters.success.sms, "Successfully delivered SMS")
@@ -70,8 +77,11 @@ func (c *channels) Email(ctx context.Context) (*senders.Chain, *smtp.Config, err
 	chain, err := senders.EmailChannels(
 		ctx,
 		smtpCfg,
+		c.emailDryRun,
+		c.suppressions,
 		c.q.GetFileSystemProvider,
 		c.q.GetLogProvider,
+		c.q.GetObjectStoreProvider,
 		c.counters.success.email,
 		c.counters.failed.email,
 	)
@@ -0,0 +1,69 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/notification/channels"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const emailContentType = "message/rfc822"
+
+// InitChannel returns a channel uploading emails to the configured bucket of an S3 compatible object storage.
+// The bucket must already exist.
+func InitChannel(ctx context.Context, config Config) (channels.NotificationChannel, error) {
+	if config.Bucket == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "NOTIF-Obj0b", "object store bucket is missing")
+	}
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, ""),
+		Secure: config.SSL,
+		Region: config.Location,
+	})
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "NOTIF-Obj1c", "unable to initialize object store client")
+	}
+
+	logging.Debug("successfully initialized object store email channel")
+
+	return channels.HandleMessageFunc(func(message channels.Message) error {
+		msg, ok := message.(*messages.Email)
+		if !ok {
+			return zerrors.ThrowUnimplementedf(nil, "NOTIF-Obj2u", "object store provider doesn't support message type %T", message)
+		}
+		content, err := msg.GetContent()
+		if err != nil {
+			return err
+		}
+		_, err = client.PutObject(
+			ctx,
+			config.Bucket,
+			objectName(config.Prefix, time.Now(), msg),
+			strings.NewReader(content),
+			int64(len(content)),
+			minio.PutObjectOptions{ContentType: emailContentType},
+		)
+		if err != nil {
+			return zerrors.ThrowInternal(err, "NOTIF-Obj3p", "unable to upload email to object store")
+		}
+		return nil
+	}), nil
+}
+
+// objectName is built like the file names of the filesystem channel,
+// the timestamp has nanosecond precision so emails to the same recipients within a second don't overwrite each other
+func objectName(prefix string, now time.Time, msg *messages.Email) string {
+	recipients := make([]string, len(msg.Recipients))
+	copy(recipients, msg.Recipients)
+	sort.Strings(recipients)
+	return fmt.Sprintf("%s%d_mail_to_%s.eml", prefix, now.UnixNano(), strings.Join(recipients, "_"))
+}
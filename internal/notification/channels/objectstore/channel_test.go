@@ -0,0 +1,144 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+type uploadedObject struct {
+	path        string
+	contentType string
+	body        string
+}
+
+func mockS3Server(t *testing.T, status int) (*httptest.Server, *[]uploadedObject) {
+	uploads := make([]uploadedObject, 0, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if r.Header.Get("X-Amz-Content-Sha256") == "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+			body = decodeChunks(t, body)
+		}
+		uploads = append(uploads, uploadedObject{
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			body:        string(body),
+		})
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &uploads
+}
+
+// decodeChunks removes the chunk signatures of payloads signed in streaming mode
+func decodeChunks(t *testing.T, body []byte) []byte {
+	var decoded []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		require.True(t, ok, "invalid chunk")
+		sizeHex, _, _ := bytes.Cut(header, []byte(";"))
+		size, err := strconv.ParseInt(string(sizeHex), 16, 64)
+		require.NoError(t, err)
+		if size == 0 {
+			return decoded
+		}
+		decoded = append(decoded, rest[:size]...)
+		body = rest[size+2:]
+	}
+}
+
+func testConfig(t *testing.T, server *httptest.Server) Config {
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return Config{
+		Enabled:         true,
+		Endpoint:        endpoint.Host,
+		Bucket:          "mails",
+		Prefix:          "archive/",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		// the location prevents the client from querying the bucket location
+		Location: "us-east-1",
+	}
+}
+
+func TestInitChannel_email(t *testing.T) {
+	server, uploads := mockS3Server(t, http.StatusOK)
+	channel, err := InitChannel(context.Background(), testConfig(t, server))
+	require.NoError(t, err)
+
+	msg := &messages.Email{
+		Recipients:  []string{"user2@example.com", "user1@example.com"},
+		SenderEmail: "zitadel@example.com",
+		Subject:     "subject",
+		Content:     "<p>content</p>",
+	}
+	require.NoError(t, channel.HandleMessage(msg))
+
+	require.Len(t, *uploads, 1)
+	upload := (*uploads)[0]
+	assert.Regexp(t, regexp.MustCompile(`^/mails/archive/\d+_mail_to_user1@example.com_user2@example.com\.eml$`), upload.path)
+	assert.Equal(t, emailContentType, upload.contentType)
+	assert.Contains(t, upload.body, "Subject: subject\r\n")
+	assert.Contains(t, upload.body, "To: user2@example.com, user1@example.com\r\n")
+	assert.Regexp(t, regexp.MustCompile(`\r\n\r\n<p>content</p>$`), upload.body)
+}
+
+func TestInitChannel_sameSecond(t *testing.T) {
+	server, uploads := mockS3Server(t, http.StatusOK)
+	channel, err := InitChannel(context.Background(), testConfig(t, server))
+	require.NoError(t, err)
+
+	msg := &messages.Email{
+		Recipients: []string{"user@example.com"},
+		Content:    "content",
+	}
+	require.NoError(t, channel.HandleMessage(msg))
+	require.NoError(t, channel.HandleMessage(msg))
+
+	require.Len(t, *uploads, 2)
+	assert.NotEqual(t, (*uploads)[0].path, (*uploads)[1].path)
+}
+
+func TestInitChannel_errors(t *testing.T) {
+	t.Run("missing bucket", func(t *testing.T) {
+		_, err := InitChannel(context.Background(), Config{Endpoint: "localhost:9000"})
+		assert.Error(t, err)
+	})
+	t.Run("unsupported message", func(t *testing.T) {
+		server, uploads := mockS3Server(t, http.StatusOK)
+		channel, err := InitChannel(context.Background(), testConfig(t, server))
+		require.NoError(t, err)
+
+		assert.Error(t, channel.HandleMessage(&messages.SMS{RecipientPhoneNumber: "+41791234567"}))
+		assert.Empty(t, *uploads)
+	})
+	t.Run("upload failed", func(t *testing.T) {
+		server, _ := mockS3Server(t, http.StatusForbidden)
+		channel, err := InitChannel(context.Background(), testConfig(t, server))
+		require.NoError(t, err)
+
+		assert.Error(t, channel.HandleMessage(&messages.Email{
+			Recipients: []string{"user@example.com"},
+			Content:    "content",
+		}))
+	})
+}
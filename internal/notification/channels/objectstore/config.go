@@ -0,0 +1,13 @@
+package objectstore
+
+type Config struct {
+	Enabled  bool
+	Endpoint string
+	Bucket   string
+	// Prefix is prepended to the name of each uploaded object
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SSL             bool
+	Location        string
+}
@@ -16,29 +16,34 @@ import (
 var _ channels.NotificationChannel = (*Email)(nil)
 
 type Email struct {
-	smtpClient     *smtp.Client
+	config         *Config
 	senderAddress  string
 	senderName     string
 	replyToAddress string
 }
 
+// InitChannel checks the connection to the smtp server and keeps it in the pool.
+// The connection used to send a message is only taken from the pool by [Email.HandleMessage],
+// so channels which never handle a message don't hold a connection.
 func InitChannel(cfg *Config) (*Email, error) {
-	client, err := cfg.SMTP.connectToSMTP(cfg.Tls)
+	client, err := connections.get(cfg)
 	if err != nil {
 		logging.New().WithError(err).Error("could not connect to smtp")
 		return nil, err
 	}
+	connections.put(cfg, client)
 	logging.New().Debug("successfully initialized smtp email channel")
 	return &Email{
-		smtpClient:     client,
+		config:         cfg,
 		senderName:     cfg.FromName,
 		senderAddress:  cfg.From,
 		replyToAddress: cfg.ReplyToAddress,
 	}, nil
 }
 
-func (email *Email) HandleMessage(message channels.Message) error {
-	defer email.smtpClient.Close()
+// HandleMessage takes a connection from the pool and sends the message,
+// the connection is returned to the pool if the message was sent successfully
+func (email *Email) HandleMessage(message channels.Message) (err error) {
 	emailMsg, ok := message.(*messages.Email)
 	if !ok {
 		return zerrors.ThrowInternal(nil, "EMAIL-s8JLs", "message is not EmailMessage")
@@ -47,21 +52,32 @@ func (email *Email) HandleMessage(message channels.Message) error {
 	if emailMsg.Content == "" || emailMsg.Subject == "" || len(emailMsg.Recipients) == 0 {
 		return zerrors.ThrowInternalf(nil, "EMAIL-zGemZ", "subject, recipients and content must be set but got subject %s, recipients length %d and content length %d", emailMsg.Subject, len(emailMsg.Recipients), len(emailMsg.Content))
 	}
+	client, err := connections.get(email.config)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			client.Close()
+			return
+		}
+		connections.put(email.config, client)
+	}()
 	emailMsg.SenderEmail = email.senderAddress
 	emailMsg.SenderName = email.senderName
 	emailMsg.ReplyToAddress = email.replyToAddress
 	// To && From
-	if err := email.smtpClient.Mail(emailMsg.SenderEmail); err != nil {
+	if err := client.Mail(emailMsg.SenderEmail); err != nil {
 		return zerrors.ThrowInternalf(err, "EMAIL-s3is3", "could not set sender: %v", emailMsg.SenderEmail)
 	}
 	for _, recp := range append(append(emailMsg.Recipients, emailMsg.CC...), emailMsg.BCC...) {
-		if err := email.smtpClient.Rcpt(recp); err != nil {
+		if err := client.Rcpt(recp); err != nil {
 			return zerrors.ThrowInternalf(err, "EMAIL-s4is4", "could not set recipient: %v", recp)
 		}
 	}
 
 	// Data
-	w, err := email.smtpClient.Data()
+	w, err := client.Data()
 	if err != nil {
 		return err
 	}
@@ -76,12 +92,7 @@ func (email *Email) HandleMessage(message channels.Message) error {
 		return err
 	}
 
-	err = w.Close()
-	if err != nil {
-		return err
-	}
-
-	return email.smtpClient.Quit()
+	return w.Close()
 }
 
 func (smtpConfig SMTP) connectToSMTP(tlsRequired bool) (client *smtp.Client, err error) {
@@ -0,0 +1,123 @@
+package smtp
+
+import (
+	"net/smtp"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+)
+
+const (
+	poolMaxIdle     = 2
+	poolIdleTimeout = 30 * time.Second
+)
+
+// connections keeps warm connections to the smtp servers,
+// so that not every notification opens a new connection
+var connections = newPool(poolMaxIdle, poolIdleTimeout)
+
+type pool struct {
+	mu          sync.Mutex
+	maxIdle     int
+	idleTimeout time.Duration
+	idle        map[poolKey][]*idleClient
+}
+
+// poolKey identifies the server and credentials a connection was opened with
+type poolKey struct {
+	host     string
+	user     string
+	password string
+	tls      bool
+}
+
+type idleClient struct {
+	client *smtp.Client
+	since  time.Time
+}
+
+func newPool(maxIdle int, idleTimeout time.Duration) *pool {
+	return &pool{
+		maxIdle:     maxIdle,
+		idleTimeout: idleTimeout,
+		idle:        make(map[poolKey][]*idleClient),
+	}
+}
+
+func newPoolKey(cfg *Config) poolKey {
+	return poolKey{
+		host:     cfg.SMTP.Host,
+		user:     cfg.SMTP.User,
+		password: cfg.SMTP.Password,
+		tls:      cfg.Tls,
+	}
+}
+
+// get returns a healthy idle connection for the config
+// or opens a new connection if none is available
+func (p *pool) get(cfg *Config) (*smtp.Client, error) {
+	key := newPoolKey(cfg)
+	for client := p.takeIdle(key); client != nil; client = p.takeIdle(key) {
+		if err := client.Noop(); err != nil {
+			logging.WithError(err).Debug("pooled smtp connection is dead")
+			client.Close()
+			continue
+		}
+		return client, nil
+	}
+	return cfg.SMTP.connectToSMTP(cfg.Tls)
+}
+
+// put returns the connection to the pool
+// the connection is closed if the pool is full
+func (p *pool) put(cfg *Config, client *smtp.Client) {
+	if err := client.Reset(); err != nil {
+		client.Close()
+		return
+	}
+	key := newPoolKey(cfg)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removeExpired(key)
+	if len(p.idle[key]) >= p.maxIdle {
+		closeClient(client)
+		return
+	}
+	p.idle[key] = append(p.idle[key], &idleClient{client: client, since: time.Now()})
+}
+
+func (p *pool) takeIdle(key poolKey) *smtp.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removeExpired(key)
+	clients := p.idle[key]
+	if len(clients) == 0 {
+		return nil
+	}
+	idle := clients[len(clients)-1]
+	p.idle[key] = clients[:len(clients)-1]
+	return idle.client
+}
+
+// removeExpired closes the connections which were idle longer than the idle timeout
+func (p *pool) removeExpired(key poolKey) {
+	clients := p.idle[key][:0]
+	for _, idle := range p.idle[key] {
+		if time.Since(idle.since) > p.idleTimeout {
+			closeClient(idle.client)
+			continue
+		}
+		clients = append(clients, idle)
+	}
+	p.idle[key] = clients
+}
+
+func closeClient(client *smtp.Client) {
+	if err := client.Quit(); err != nil {
+		client.Close()
+	}
+}
@@ -0,0 +1,184 @@
+package smtp
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+// fakeServer is a minimal smtp server counting the opened connections and received messages
+type fakeServer struct {
+	listener    net.Listener
+	connections atomic.Int32
+	messages    atomic.Int32
+	// closeAfterMessage closes the connection after a message was received
+	closeAfterMessage bool
+	// rejectHello answers EHLO and HELO with an error
+	rejectHello atomic.Bool
+	wg          sync.WaitGroup
+}
+
+func newFakeServer(t *testing.T, closeAfterMessage bool) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeServer{
+		listener:          listener,
+		closeAfterMessage: closeAfterMessage,
+	}
+	go server.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		server.wg.Wait()
+	})
+	return server
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.connections.Add(1)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	write := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+	write("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			if s.rejectHello.Load() {
+				write("550 not allowed")
+				continue
+			}
+			write("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			write("354 start mail input")
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+			}
+			s.messages.Add(1)
+			write("250 OK")
+			if s.closeAfterMessage {
+				return
+			}
+		case strings.HasPrefix(command, "QUIT"):
+			write("221 bye")
+			return
+		default:
+			write("250 OK")
+		}
+	}
+}
+
+func (s *fakeServer) config() *Config {
+	return &Config{
+		SMTP: SMTP{Host: s.listener.Addr().String()},
+		From: "zitadel@example.com",
+	}
+}
+
+func sendTestMail(t *testing.T, cfg *Config) {
+	channel, err := InitChannel(cfg)
+	require.NoError(t, err)
+	err = channel.HandleMessage(&messages.Email{
+		Recipients: []string{"user@example.com"},
+		Subject:    "subject",
+		Content:    "content",
+	})
+	require.NoError(t, err)
+}
+
+func usePool(t *testing.T, p *pool) {
+	previous := connections
+	connections = p
+	t.Cleanup(func() {
+		connections = previous
+		for _, clients := range p.idle {
+			for _, idle := range clients {
+				closeClient(idle.client)
+			}
+		}
+	})
+}
+
+func TestEmail_HandleMessage_reusesConnection(t *testing.T) {
+	server := newFakeServer(t, false)
+	usePool(t, newPool(poolMaxIdle, time.Minute))
+
+	sendTestMail(t, server.config())
+	sendTestMail(t, server.config())
+
+	assert.Equal(t, int32(2), server.messages.Load())
+	assert.Equal(t, int32(1), server.connections.Load())
+}
+
+func TestEmail_HandleMessage_deadConnection(t *testing.T) {
+	server := newFakeServer(t, true)
+	usePool(t, newPool(poolMaxIdle, time.Minute))
+
+	sendTestMail(t, server.config())
+	sendTestMail(t, server.config())
+
+	assert.Equal(t, int32(2), server.messages.Load())
+	assert.Equal(t, int32(2), server.connections.Load())
+}
+
+func TestEmail_HandleMessage_idleTimeout(t *testing.T) {
+	server := newFakeServer(t, false)
+	usePool(t, newPool(poolMaxIdle, 0))
+
+	sendTestMail(t, server.config())
+	sendTestMail(t, server.config())
+
+	assert.Equal(t, int32(2), server.messages.Load())
+	// the connections checked by InitChannel expire as well
+	assert.Equal(t, int32(4), server.connections.Load())
+}
+
+func TestInitChannel_unusedChannel(t *testing.T) {
+	server := newFakeServer(t, false)
+	p := newPool(poolMaxIdle, time.Minute)
+	usePool(t, p)
+
+	// e.g. a duplicate or suppressed email never reaches the channel
+	_, err := InitChannel(server.config())
+	require.NoError(t, err)
+	_, err = InitChannel(server.config())
+	require.NoError(t, err)
+
+	assert.Len(t, p.idle[newPoolKey(server.config())], 1)
+	assert.Equal(t, int32(1), server.connections.Load())
+}
@@ -0,0 +1,99 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+const (
+	ProbeStepConnect  = "connect"
+	ProbeStepHello    = "ehlo"
+	ProbeStepStartTLS = "starttls"
+
+	// probeTimeout limits the probe if the context has no deadline
+	probeTimeout = 10 * time.Second
+)
+
+// ProbeError is returned by [Config.Probe] if the smtp server is not reachable
+type ProbeError struct {
+	Host string
+	// Step is the failed step of the probe, see [ProbeStepConnect], [ProbeStepHello] and [ProbeStepStartTLS]
+	Step string
+	Err  error
+}
+
+func (err *ProbeError) Error() string {
+	return fmt.Sprintf("smtp probe of %s failed on %s: %v", err.Host, err.Step, err.Err)
+}
+
+func (err *ProbeError) Unwrap() error {
+	return err.Err
+}
+
+// Probe checks if the smtp server of the channel is reachable
+func (email *Email) Probe(ctx context.Context) error {
+	return email.config.Probe(ctx)
+}
+
+// Probe opens a new connection to the smtp server and issues EHLO,
+// followed by STARTTLS if tls is required and the server doesn't accept tls connections directly.
+// The server is not authenticated and no message is sent.
+func (cfg *Config) Probe(ctx context.Context) (err error) {
+	host, _, err := net.SplitHostPort(cfg.SMTP.Host)
+	if err != nil {
+		return &ProbeError{Host: cfg.SMTP.Host, Step: ProbeStepConnect, Err: err}
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, probeTimeout)
+		defer cancel()
+	}
+
+	conn, startTLS, err := cfg.dialProbe(ctx, host)
+	if err != nil {
+		return &ProbeError{Host: cfg.SMTP.Host, Step: ProbeStepConnect, Err: err}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return &ProbeError{Host: cfg.SMTP.Host, Step: ProbeStepConnect, Err: err}
+		}
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return &ProbeError{Host: cfg.SMTP.Host, Step: ProbeStepConnect, Err: err}
+	}
+	defer client.Close()
+	if err = client.Hello("localhost"); err != nil {
+		return &ProbeError{Host: cfg.SMTP.Host, Step: ProbeStepHello, Err: err}
+	}
+	if startTLS {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return &ProbeError{Host: cfg.SMTP.Host, Step: ProbeStepStartTLS, Err: err}
+		}
+	}
+	return client.Quit()
+}
+
+// dialProbe connects the same way as [SMTP.connectToSMTP]:
+// tls is tried first if required, STARTTLS is used if the server doesn't accept tls connections.
+func (cfg *Config) dialProbe(ctx context.Context, host string) (conn net.Conn, startTLS bool, err error) {
+	dialer := new(net.Dialer)
+	if !cfg.Tls {
+		conn, err = dialer.DialContext(ctx, "tcp", cfg.SMTP.Host)
+		return conn, false, err
+	}
+	tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}
+	conn, err = tlsDialer.DialContext(ctx, "tcp", cfg.SMTP.Host)
+	if !errors.As(err, new(tls.RecordHeaderError)) {
+		return conn, false, err
+	}
+	conn, err = dialer.DialContext(ctx, "tcp", cfg.SMTP.Host)
+	return conn, true, err
+}
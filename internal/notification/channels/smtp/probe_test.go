@@ -0,0 +1,66 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Probe(t *testing.T) {
+	tests := []struct {
+		name        string
+		rejectHello bool
+		tls         bool
+		wantStep    string
+	}{
+		{
+			name: "server accepts",
+		},
+		{
+			name:        "server rejects ehlo",
+			rejectHello: true,
+			wantStep:    ProbeStepHello,
+		},
+		{
+			name:     "server without starttls",
+			tls:      true,
+			wantStep: ProbeStepStartTLS,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t, false)
+			server.rejectHello.Store(tt.rejectHello)
+			cfg := server.config()
+			cfg.Tls = tt.tls
+
+			err := cfg.Probe(context.Background())
+			if tt.wantStep == "" {
+				require.NoError(t, err)
+				return
+			}
+			probeErr := new(ProbeError)
+			require.ErrorAs(t, err, &probeErr)
+			assert.Equal(t, tt.wantStep, probeErr.Step)
+			assert.Equal(t, cfg.SMTP.Host, probeErr.Host)
+		})
+	}
+}
+
+func TestConfig_Probe_unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	err = (&Config{SMTP: SMTP{Host: host}}).Probe(context.Background())
+	probeErr := new(ProbeError)
+	require.ErrorAs(t, err, &probeErr)
+	assert.Equal(t, ProbeStepConnect, probeErr.Step)
+	var opErr *net.OpError
+	assert.True(t, errors.As(err, &opErr), "probe error must wrap the dial error")
+}
@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/config/systemdefaults"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	prunedFailedEventsCounter = "pruned_failed_notification_events"
+
+	pruneFailedEventsStmt = "DELETE FROM " + projection.FailedEventsTable +
+		" WHERE projection_name = ANY($1) AND last_failed < $2"
+)
+
+// failedEventsCleaner removes failed events of the notification handlers
+// which are older than the configured retention.
+type failedEventsCleaner struct {
+	client      *database.DB
+	projections []string
+	retention   time.Duration
+	interval    time.Duration
+	now         func() time.Time
+}
+
+// StartFailedEventsCleaner periodically removes failed events of the registered notification handlers.
+// The cleaner is disabled if no retention is configured.
+func StartFailedEventsCleaner(ctx context.Context, client *database.DB, config systemdefaults.Notifications) {
+	if config.FailedEventsRetention <= 0 || config.FailedEventsCleanupInterval <= 0 {
+		return
+	}
+	registerCounter(prunedFailedEventsCounter, "Pruned failed notification events")
+	cleaner := &failedEventsCleaner{
+		client:      client,
+		projections: make([]string, len(projections)),
+		retention:   config.FailedEventsRetention,
+		interval:    config.FailedEventsCleanupInterval,
+		now:         time.Now,
+	}
+	for i, p := range projections {
+		cleaner.projections[i] = p.ProjectionName()
+	}
+	go cleaner.schedule(ctx)
+}
+
+func (c *failedEventsCleaner) schedule(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := c.prune(ctx)
+			logging.OnError(err).Warn("unable to prune failed notification events")
+			logging.WithFields("pruned", pruned).Debug("failed notification events pruned")
+		}
+	}
+}
+
+func (c *failedEventsCleaner) prune(ctx context.Context) (int64, error) {
+	result, err := c.client.ExecContext(ctx, pruneFailedEventsStmt, database.TextArray[string](c.projections), c.now().Add(-c.retention))
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "NOTIF-Wq3op", "Errors.Internal")
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "NOTIF-Wq3oq", "Errors.Internal")
+	}
+	err = metrics.AddCount(ctx, prunedFailedEventsCounter, pruned, nil)
+	logging.OnError(err).Warn("unable to add pruned failed events count")
+	return pruned, nil
+}
@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
+)
+
+func Test_failedEventsCleaner_prune(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		retention time.Duration
+		expect    func(sqlmock.Sqlmock)
+		want      int64
+		wantErr   bool
+	}{
+		{
+			name:      "old entries removed",
+			retention: 24 * time.Hour,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(pruneFailedEventsStmt)).
+					WithArgs(database.TextArray[string]{"projections.notifications", "projections.notifications_quota"}, now.Add(-24*time.Hour)).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			want: 3,
+		},
+		{
+			name:      "recent entries retained",
+			retention: 7 * 24 * time.Hour,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(pruneFailedEventsStmt)).
+					WithArgs(database.TextArray[string]{"projections.notifications", "projections.notifications_quota"}, now.Add(-7*24*time.Hour)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: 0,
+		},
+		{
+			name:      "error",
+			retention: 24 * time.Hour,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(pruneFailedEventsStmt)).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+			tt.expect(mock)
+
+			c := &failedEventsCleaner{
+				client:      &database.DB{DB: client},
+				projections: []string{"projections.notifications", "projections.notifications_quota"},
+				retention:   tt.retention,
+				now:         func() time.Time { return now },
+			}
+			got, err := c.prune(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/notification/channels/objectstore"
+)
+
+// GetObjectStoreProvider returns the object store config for archiving emails
+func (n *NotificationQueries) GetObjectStoreProvider(context.Context) (*objectstore.Config, error) {
+	config := n.objectStore
+	return &config, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/notification/channels/objectstore"
 	"github.com/zitadel/zitadel/internal/query"
 )
 
@@ -33,6 +34,7 @@ type NotificationQueries struct {
 	externalPort       uint16
 	externalSecure     bool
 	fileSystemPath     string
+	objectStore        objectstore.Config
 	UserDataCrypto     crypto.EncryptionAlgorithm
 	SMTPPasswordCrypto crypto.EncryptionAlgorithm
 	SMSTokenCrypto     crypto.EncryptionAlgorithm
@@ -45,6 +47,7 @@ func NewNotificationQueries(
 	externalPort uint16,
 	externalSecure bool,
 	fileSystemPath string,
+	objectStore objectstore.Config,
 	userDataCrypto crypto.EncryptionAlgorithm,
 	smtpPasswordCrypto crypto.EncryptionAlgorithm,
 	smsTokenCrypto crypto.EncryptionAlgorithm,
@@ -56,6 +59,7 @@ func NewNotificationQueries(
 		externalPort:       externalPort,
 		externalSecure:     externalSecure,
 		fileSystemPath:     fileSystemPath,
+		objectStore:        objectStore,
 		UserDataCrypto:     userDataCrypto,
 		SMTPPasswordCrypto: smtpPasswordCrypto,
 		SMSTokenCrypto:     smsTokenCrypto,
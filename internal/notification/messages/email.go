@@ -28,6 +28,9 @@ type Email struct {
 	Subject         string
 	Content         string
 	TriggeringEvent eventstore.Event
+	// IdempotencyKey identifies the email, emails with the same key are only sent once
+	// if empty, the key is derived from the triggering event
+	IdempotencyKey string
 }
 
 func (msg *Email) GetContent() (string, error) {
@@ -65,6 +68,22 @@ func (msg *Email) GetTriggeringEvent() eventstore.Event {
 	return msg.TriggeringEvent
 }
 
+// GetIdempotencyKey returns the [Email.IdempotencyKey] or the key of the triggering event
+func (msg *Email) GetIdempotencyKey() string {
+	if msg.IdempotencyKey != "" {
+		return msg.IdempotencyKey
+	}
+	return IdempotencyKeyFromEvent(msg.TriggeringEvent)
+}
+
+// IdempotencyKeyFromEvent returns a key which uniquely identifies the event
+func IdempotencyKeyFromEvent(event eventstore.Event) string {
+	if event == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%d", event.Aggregate().Type, event.Aggregate().ID, event.Sequence())
+}
+
 func isHTML(input string) bool {
 	return isHTMLRgx.MatchString(input)
 }
@@ -14,6 +14,8 @@ func ChainChannels(channel ...channels.NotificationChannel) *Chain {
 
 // HandleMessage returns a non nil error from a provider immediately if any occurs
 // messages are sent to channels in the same order they were provided to ChainChannels()
+// so the error of the first failing channel is returned and the following channels don't receive the message.
+// Use [FanOutChannels] with [FanOutAny] to send the message to all channels and inspect the joined errors of all failing channels.
 func (c *Chain) HandleMessage(message channels.Message) error {
 	for i := range c.channels {
 		if err := c.channels[i].HandleMessage(message); err != nil {
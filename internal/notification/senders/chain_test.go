@@ -0,0 +1,60 @@
+package senders
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/notification/channels"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+func TestChain_HandleMessage(t *testing.T) {
+	errSMTP := errors.New("smtp: connection refused")
+	errLog := errors.New("log: disabled")
+	tests := []struct {
+		name       string
+		channels   []error
+		wantErr    error
+		wantCalled int
+	}{
+		{
+			name:       "all succeed",
+			channels:   []error{nil, nil, nil},
+			wantCalled: 3,
+		},
+		{
+			name:       "first failing channel returned",
+			channels:   []error{errSMTP, errLog},
+			wantErr:    errSMTP,
+			wantCalled: 1,
+		},
+		{
+			name:       "following channels not called after failure",
+			channels:   []error{nil, errLog, nil},
+			wantErr:    errLog,
+			wantCalled: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called int
+			chainChannels := make([]channels.NotificationChannel, len(tt.channels))
+			for i, err := range tt.channels {
+				err := err
+				chainChannels[i] = channels.HandleMessageFunc(func(channels.Message) error {
+					called++
+					return err
+				})
+			}
+
+			err := ChainChannels(chainChannels...).HandleMessage(&messages.Email{})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalled, called)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package senders
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/notification/channels"
+)
+
+const sentEmailsTTL = 15 * time.Minute
+
+// sentEmails keeps the idempotency keys of the recently sent emails of all instances
+var sentEmails = NewSentMessages(sentEmailsTTL)
+
+var _ channels.NotificationChannel = (*Dedupe)(nil)
+
+// idempotentMessage is implemented by messages which must only be sent once
+type idempotentMessage interface {
+	GetIdempotencyKey() string
+}
+
+// SentMessages records the idempotency keys of sent messages until the ttl expired
+// the keys are held in memory of the process, it is safe for concurrent use
+type SentMessages struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	sent map[string]time.Time
+	// nextSweep is the time after which the expired keys are removed from sent
+	nextSweep time.Time
+}
+
+func NewSentMessages(ttl time.Duration) *SentMessages {
+	return &SentMessages{
+		ttl:       ttl,
+		sent:      make(map[string]time.Time),
+		nextSweep: time.Now().Add(ttl),
+	}
+}
+
+// reserve records the key and returns false if it was already recorded and is not expired
+// expired keys are removed at most once per ttl, so reserving a key doesn't scan all keys
+func (s *SentMessages) reserve(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		s.sweep(now)
+	}
+	if expiry, ok := s.sent[key]; ok && !now.After(expiry) {
+		return false
+	}
+	s.sent[key] = now.Add(s.ttl)
+	return true
+}
+
+// sweep removes the expired keys
+func (s *SentMessages) sweep(now time.Time) {
+	for sentKey, expiry := range s.sent {
+		if now.After(expiry) {
+			delete(s.sent, sentKey)
+		}
+	}
+	s.nextSweep = now.Add(s.ttl)
+}
+
+// release removes the key, so that the message can be sent again
+func (s *SentMessages) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sent, key)
+}
+
+// Dedupe drops messages of an instance whose idempotency key was already sent
+type Dedupe struct {
+	channel    channels.NotificationChannel
+	sent       *SentMessages
+	instanceID string
+	// channelKey distinguishes the channels of a [FanOut] sharing the same [SentMessages]
+	channelKey string
+}
+
+func DedupeChannel(ctx context.Context, channel channels.NotificationChannel, sent *SentMessages) *Dedupe {
+	return &Dedupe{
+		channel:    channel,
+		sent:       sent,
+		instanceID: authz.GetInstance(ctx).InstanceID(),
+	}
+}
+
+// HandleMessage passes the message to the channel if its idempotency key was not sent before
+// messages without idempotency key are always passed
+func (d *Dedupe) HandleMessage(message channels.Message) error {
+	idempotent, ok := message.(idempotentMessage)
+	if !ok || idempotent.GetIdempotencyKey() == "" {
+		return d.channel.HandleMessage(message)
+	}
+	key := d.instanceID + ":" + idempotent.GetIdempotencyKey()
+	if d.channelKey != "" {
+		key += ":" + d.channelKey
+	}
+	if !d.sent.reserve(key) {
+		logging.WithFields("instance", d.instanceID, "key", idempotent.GetIdempotencyKey()).Info("message already sent, duplicate is dropped")
+		return nil
+	}
+	if err := d.channel.HandleMessage(message); err != nil {
+		d.sent.release(key)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/notification/channels"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+func countingChannel(sent *atomic.Int32, err error) channels.NotificationChannel {
+	return channels.HandleMessageFunc(func(channels.Message) error {
+		if err != nil {
+			return err
+		}
+		sent.Add(1)
+		return nil
+	})
+}
+
+func TestDedupe_HandleMessage(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	var sent atomic.Int32
+	channel := DedupeChannel(ctx, countingChannel(&sent, nil), NewSentMessages(time.Minute))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, channel.HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), sent.Load())
+}
+
+func TestDedupe_HandleMessage_perInstance(t *testing.T) {
+	var sent atomic.Int32
+	store := NewSentMessages(time.Minute)
+
+	for _, instanceID := range []string{"instance1", "instance2"} {
+		channel := DedupeChannel(authz.WithInstanceID(context.Background(), instanceID), countingChannel(&sent, nil), store)
+		require.NoError(t, channel.HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+	}
+
+	assert.Equal(t, int32(2), sent.Load())
+}
+
+func TestDedupe_HandleMessage_withoutKey(t *testing.T) {
+	var sent atomic.Int32
+	channel := DedupeChannel(context.Background(), countingChannel(&sent, nil), NewSentMessages(time.Minute))
+
+	require.NoError(t, channel.HandleMessage(&messages.Email{}))
+	require.NoError(t, channel.HandleMessage(&messages.Email{}))
+
+	assert.Equal(t, int32(2), sent.Load())
+}
+
+func TestDedupe_HandleMessage_failedIsRetried(t *testing.T) {
+	var sent atomic.Int32
+	store := NewSentMessages(time.Minute)
+	errSend := errors.New("send failed")
+
+	err := DedupeChannel(context.Background(), countingChannel(&sent, errSend), store).HandleMessage(&messages.Email{IdempotencyKey: "key"})
+	require.ErrorIs(t, err, errSend)
+	require.NoError(t, DedupeChannel(context.Background(), countingChannel(&sent, nil), store).HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+
+	assert.Equal(t, int32(1), sent.Load())
+}
+
+func TestDedupe_HandleMessage_expired(t *testing.T) {
+	var sent atomic.Int32
+	channel := DedupeChannel(context.Background(), countingChannel(&sent, nil), NewSentMessages(0))
+
+	require.NoError(t, channel.HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, channel.HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+
+	assert.Equal(t, int32(2), sent.Load())
+}
+
+func TestSentMessages_reserve_sweep(t *testing.T) {
+	store := NewSentMessages(time.Minute)
+	store.sent["expired"] = time.Now().Add(-time.Second)
+
+	// the expired key is not swept before the next sweep is due
+	require.True(t, store.reserve("key"))
+	assert.Contains(t, store.sent, "expired")
+	// but it can be reserved again
+	require.True(t, store.reserve("expired"))
+
+	store.sent["expired"] = time.Now().Add(-time.Second)
+	store.nextSweep = time.Now().Add(-time.Second)
+	require.False(t, store.reserve("key"))
+	assert.NotContains(t, store.sent, "expired")
+	assert.Contains(t, store.sent, "key")
+}
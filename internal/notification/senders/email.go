@@ -9,27 +9,43 @@ import (
 	"github.com/zitadel/zitadel/internal/notification/channels/fs"
 	"github.com/zitadel/zitadel/internal/notification/channels/instrumenting"
 	"github.com/zitadel/zitadel/internal/notification/channels/log"
+	"github.com/zitadel/zitadel/internal/notification/channels/objectstore"
 	"github.com/zitadel/zitadel/internal/notification/channels/smtp"
 )
 
-const smtpSpanName = "smtp.NotificationChannel"
+const (
+	smtpSpanName        = "smtp.NotificationChannel"
+	objectStoreSpanName = "objectstore.NotificationChannel"
+)
 
+// EmailChannels returns the channels sending emails of the instance.
+// Suppressed recipients don't receive emails of the SMTP channel, suppression is disabled if suppressions is nil.
 func EmailChannels(
 	ctx context.Context,
 	emailConfig *smtp.Config,
+	dryRun bool,
+	suppressions SuppressionStore,
 	getFileSystemProvider func(ctx context.Context) (*fs.Config, error),
 	getLogProvider func(ctx context.Context) (*log.Config, error),
+	getObjectStoreProvider func(ctx context.Context) (*objectstore.Config, error),
 	successMetricName,
 	failureMetricName string,
 ) (chain *Chain, err error) {
-	channels := make([]channels.NotificationChannel, 0, 3)
+	if dryRun {
+		logging.WithFields(
+			"instance", authz.GetInstance(ctx).InstanceID(),
+		).Info("email dry-run is active, emails are only sent to the debug channels")
+		channels := objectStoreChannels(ctx, getObjectStoreProvider, successMetricName, failureMetricName)
+		return ChainChannels(append(channels, debugChannels(ctx, getFileSystemProvider, getLogProvider)...)...), nil
+	}
+	channels := make([]channels.NotificationChannel, 0, 4)
 	p, err := smtp.InitChannel(emailConfig)
 	logging.WithFields(
 		"instance", authz.GetInstance(ctx).InstanceID(),
 	).OnError(err).Debug("initializing SMTP channel failed")
 	if err == nil {
-		channels = append(
-			channels,
+		dedupe := DedupeChannel(
+			ctx,
 			instrumenting.Wrap(
 				ctx,
 				p,
@@ -37,8 +53,53 @@ func EmailChannels(
 				successMetricName,
 				failureMetricName,
 			),
+			sentEmails,
 		)
+		if suppressions != nil {
+			channels = append(channels, SuppressionChannel(ctx, dedupe, suppressions))
+		} else {
+			channels = append(channels, dedupe)
+		}
 	}
+	channels = append(channels, objectStoreChannels(ctx, getObjectStoreProvider, successMetricName, failureMetricName)...)
 	channels = append(channels, debugChannels(ctx, getFileSystemProvider, getLogProvider)...)
 	return ChainChannels(channels...), nil
 }
+
+// objectStoreChannels returns the channel archiving emails in the object store if it's enabled.
+// Archiving is best-effort: upload errors are logged and counted as failures but not returned,
+// so a failing object store doesn't trigger a retry which sends the email again.
+func objectStoreChannels(
+	ctx context.Context,
+	getObjectStoreProvider func(ctx context.Context) (*objectstore.Config, error),
+	successMetricName,
+	failureMetricName string,
+) []channels.NotificationChannel {
+	config, err := getObjectStoreProvider(ctx)
+	if err != nil || !config.Enabled {
+		return nil
+	}
+	p, err := objectstore.InitChannel(ctx, *config)
+	logging.WithFields(
+		"instance", authz.GetInstance(ctx).InstanceID(),
+	).OnError(err).Debug("initializing object store channel failed")
+	if err != nil {
+		return nil
+	}
+	archive := instrumenting.Wrap(
+		ctx,
+		p,
+		objectStoreSpanName,
+		successMetricName,
+		failureMetricName,
+	)
+	return []channels.NotificationChannel{
+		channels.HandleMessageFunc(func(message channels.Message) error {
+			err := archive.HandleMessage(message)
+			logging.WithFields(
+				"instance", authz.GetInstance(ctx).InstanceID(),
+			).OnError(err).Warn("archiving email in object store failed")
+			return nil
+		}),
+	}
+}
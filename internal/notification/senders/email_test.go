@@ -0,0 +1,88 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/notification/channels/fs"
+	"github.com/zitadel/zitadel/internal/notification/channels/log"
+	"github.com/zitadel/zitadel/internal/notification/channels/objectstore"
+	"github.com/zitadel/zitadel/internal/notification/channels/smtp"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+func TestEmailChannels_dryRun(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	chain, err := EmailChannels(
+		context.Background(),
+		&smtp.Config{
+			SMTP: smtp.SMTP{Host: listener.Addr().String()},
+			From: "zitadel@example.com",
+		},
+		true,
+		nil,
+		func(context.Context) (*fs.Config, error) { return nil, errors.New("not configured") },
+		func(context.Context) (*log.Config, error) { return &log.Config{Enabled: true}, nil },
+		func(context.Context) (*objectstore.Config, error) { return &objectstore.Config{}, nil },
+		"success",
+		"failure",
+	)
+	require.NoError(t, err)
+	// only the log channel is part of the chain
+	assert.Equal(t, 1, chain.Len())
+
+	// no connection to the smtp server must have been opened
+	require.NoError(t, listener.(*net.TCPListener).SetDeadline(time.Now().Add(100*time.Millisecond)))
+	conn, err := listener.Accept()
+	if err == nil {
+		conn.Close()
+		t.Fatal("smtp server was called during dry-run")
+	}
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestObjectStoreChannels_bestEffort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	archive := objectStoreChannels(
+		context.Background(),
+		func(context.Context) (*objectstore.Config, error) {
+			return &objectstore.Config{
+				Enabled:  true,
+				Endpoint: endpoint.Host,
+				Bucket:   "mails",
+				Location: "us-east-1",
+			}, nil
+		},
+		"success",
+		"failure",
+	)
+	require.Len(t, archive, 1)
+
+	// the failed upload must not fail the chain, otherwise the email would be sent again on retry
+	assert.NoError(t, archive[0].HandleMessage(&messages.Email{
+		Recipients:      []string{"user@example.com"},
+		Content:         "content",
+		TriggeringEvent: &repository.Event{Typ: "user.human.added"},
+	}))
+}
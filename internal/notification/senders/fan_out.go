@@ -0,0 +1,86 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/notification/channels"
+)
+
+var _ channels.NotificationChannel = (*FanOut)(nil)
+
+// FanOutMode defines when a message sent to multiple channels is considered as handled
+type FanOutMode int
+
+const (
+	// FanOutAny succeeds if at least one channel handled the message
+	FanOutAny FanOutMode = iota
+	// FanOutAll succeeds only if all channels handled the message
+	FanOutAll
+)
+
+type FanOut struct {
+	channels []channels.NotificationChannel
+	mode     FanOutMode
+}
+
+func FanOutChannels(mode FanOutMode, channel ...channels.NotificationChannel) *FanOut {
+	return &FanOut{channels: channel, mode: mode}
+}
+
+// DeliverOnce records per channel which idempotency keys were handled successfully.
+// If a message is retried after a partial failure, it is only sent to the channels which did not handle it yet.
+// Messages without idempotency key are always sent to all channels.
+//
+// The delivery state is only held in the memory of the process by sent:
+// it is lost on restart and not shared between processes, so a retry handled by another process
+// or after a restart is sent to all channels again.
+// The email and SMS senders chain their channels and don't use [FanOut].
+func (f *FanOut) DeliverOnce(ctx context.Context, sent *SentMessages) *FanOut {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	for i, channel := range f.channels {
+		f.channels[i] = &Dedupe{
+			channel:    channel,
+			sent:       sent,
+			instanceID: instanceID,
+			channelKey: strconv.Itoa(i),
+		}
+	}
+	return f
+}
+
+// HandleMessage sends the message to all channels concurrently
+// the errors of the channels are joined and returned depending on the [FanOutMode]
+func (f *FanOut) HandleMessage(message channels.Message) error {
+	errs := make([]error, len(f.channels))
+	var wg sync.WaitGroup
+	for i := range f.channels {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = f.channels[i].HandleMessage(message)
+		}(i)
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if err == nil || f.mode == FanOutAll {
+		return err
+	}
+	for _, channelErr := range errs {
+		if channelErr == nil {
+			logging.WithError(err).Warn("message not handled by all channels")
+			return nil
+		}
+	}
+	return err
+}
+
+func (f *FanOut) Len() int {
+	return len(f.channels)
+}
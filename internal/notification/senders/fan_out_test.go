@@ -0,0 +1,138 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/notification/channels"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+func TestFanOut_HandleMessage(t *testing.T) {
+	errChannel := errors.New("channel failed")
+	type fields struct {
+		mode     FanOutMode
+		channels []error
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr error
+	}{
+		{
+			name: "all succeed",
+			fields: fields{
+				mode:     FanOutAll,
+				channels: []error{nil, nil, nil},
+			},
+		},
+		{
+			name: "partial failure, any mode",
+			fields: fields{
+				mode:     FanOutAny,
+				channels: []error{nil, errChannel},
+			},
+		},
+		{
+			name: "partial failure, all mode",
+			fields: fields{
+				mode:     FanOutAll,
+				channels: []error{nil, errChannel},
+			},
+			wantErr: errChannel,
+		},
+		{
+			name: "all fail, any mode",
+			fields: fields{
+				mode:     FanOutAny,
+				channels: []error{errChannel, errChannel},
+			},
+			wantErr: errChannel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called atomic.Int32
+			fanOutChannels := make([]channels.NotificationChannel, len(tt.fields.channels))
+			for i, err := range tt.fields.channels {
+				err := err
+				fanOutChannels[i] = channels.HandleMessageFunc(func(channels.Message) error {
+					called.Add(1)
+					return err
+				})
+			}
+
+			err := FanOutChannels(tt.fields.mode, fanOutChannels...).HandleMessage(&messages.Email{})
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, int32(len(tt.fields.channels)), called.Load())
+		})
+	}
+}
+
+func TestFanOut_HandleMessage_joinedErrors(t *testing.T) {
+	errSMTP := errors.New("smtp: connection refused")
+	errObjectStore := errors.New("objectstore: bucket not found")
+	failing := func(err error) channels.NotificationChannel {
+		return channels.HandleMessageFunc(func(channels.Message) error {
+			return err
+		})
+	}
+
+	err := FanOutChannels(FanOutAny, failing(errSMTP), failing(errObjectStore)).HandleMessage(&messages.Email{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errSMTP)
+	assert.ErrorIs(t, err, errObjectStore)
+	assert.Contains(t, err.Error(), errSMTP.Error())
+	assert.Contains(t, err.Error(), errObjectStore.Error())
+}
+
+func TestFanOut_DeliverOnce(t *testing.T) {
+	errChannel := errors.New("channel failed")
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+
+	var succeeded, failed atomic.Int32
+	failing := true
+	fanOut := FanOutChannels(FanOutAll,
+		channels.HandleMessageFunc(func(channels.Message) error {
+			succeeded.Add(1)
+			return nil
+		}),
+		channels.HandleMessageFunc(func(channels.Message) error {
+			failed.Add(1)
+			if failing {
+				return errChannel
+			}
+			return nil
+		}),
+	).DeliverOnce(ctx, NewSentMessages(time.Minute))
+
+	require.ErrorIs(t, fanOut.HandleMessage(&messages.Email{IdempotencyKey: "key"}), errChannel)
+	assert.Equal(t, int32(1), succeeded.Load())
+	assert.Equal(t, int32(1), failed.Load())
+
+	// the retry is only sent to the failed channel
+	failing = false
+	require.NoError(t, fanOut.HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+	assert.Equal(t, int32(1), succeeded.Load())
+	assert.Equal(t, int32(2), failed.Load())
+
+	// all channels handled the message
+	require.NoError(t, fanOut.HandleMessage(&messages.Email{IdempotencyKey: "key"}))
+	assert.Equal(t, int32(1), succeeded.Load())
+	assert.Equal(t, int32(2), failed.Load())
+
+	// other messages are sent to all channels
+	require.NoError(t, fanOut.HandleMessage(&messages.Email{IdempotencyKey: "other"}))
+	assert.Equal(t, int32(2), succeeded.Load())
+	assert.Equal(t, int32(3), failed.Load())
+}
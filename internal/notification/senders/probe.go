@@ -0,0 +1,34 @@
+package senders
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zitadel/zitadel/internal/notification/channels/smtp"
+)
+
+// Prober is implemented by channels which can check if their provider is reachable
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// ProbeChannels probes all channels and returns the joined errors of the failed probes
+func ProbeChannels(ctx context.Context, probers ...Prober) error {
+	errs := make([]error, 0, len(probers))
+	for _, prober := range probers {
+		errs = append(errs, prober.Probe(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// ProbeEmailChannels probes the providers used by [EmailChannels].
+// The debug channels don't connect to a provider and are not probed.
+// It can be used as readiness check, nothing is probed if no smtp server is configured.
+func ProbeEmailChannels(emailConfig *smtp.Config) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if emailConfig == nil || emailConfig.SMTP.Host == "" {
+			return nil
+		}
+		return ProbeChannels(ctx, emailConfig)
+	}
+}
@@ -0,0 +1,73 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/notification/channels/smtp"
+)
+
+type proberFunc func(ctx context.Context) error
+
+func (f proberFunc) Probe(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestProbeChannels(t *testing.T) {
+	errProbe := errors.New("unreachable")
+	var probed int
+	probe := func(err error) Prober {
+		return proberFunc(func(context.Context) error {
+			probed++
+			return err
+		})
+	}
+
+	err := ProbeChannels(context.Background(), probe(nil), probe(errProbe), probe(nil))
+	assert.ErrorIs(t, err, errProbe)
+	assert.Equal(t, 3, probed, "all channels must be probed")
+
+	assert.NoError(t, ProbeChannels(context.Background(), probe(nil)))
+}
+
+func TestProbeEmailChannels(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	tests := []struct {
+		name    string
+		config  *smtp.Config
+		wantErr bool
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:   "no host",
+			config: &smtp.Config{From: "zitadel@example.com"},
+		},
+		{
+			name:    "unreachable",
+			config:  &smtp.Config{SMTP: smtp.SMTP{Host: host}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ProbeEmailChannels(tt.config)(context.Background())
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			probeErr := new(smtp.ProbeError)
+			assert.ErrorAs(t, err, &probeErr)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/notification/channels/fs"
+	"github.com/zitadel/zitadel/internal/notification/channels/log"
+	"github.com/zitadel/zitadel/internal/notification/channels/twilio"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+)
+
+func TestSMSChannels(t *testing.T) {
+	noFileSystem := func(context.Context) (*fs.Config, error) { return nil, errors.New("not configured") }
+	tests := []struct {
+		name             string
+		twilioConfig     *twilio.Config
+		getLogProvider   func(context.Context) (*log.Config, error)
+		wantLen          int
+		wantInstrumented bool
+	}{
+		{
+			name:             "twilio and log",
+			twilioConfig:     &twilio.Config{SID: "sid", Token: "token", SenderNumber: "+41000000000"},
+			getLogProvider:   func(context.Context) (*log.Config, error) { return &log.Config{Enabled: true}, nil },
+			wantLen:          2,
+			wantInstrumented: true,
+		},
+		{
+			name:             "twilio only",
+			twilioConfig:     &twilio.Config{SID: "sid", Token: "token", SenderNumber: "+41000000000"},
+			getLogProvider:   func(context.Context) (*log.Config, error) { return nil, errors.New("not configured") },
+			wantLen:          1,
+			wantInstrumented: true,
+		},
+		{
+			name:           "log only",
+			getLogProvider: func(context.Context) (*log.Config, error) { return &log.Config{Enabled: true}, nil },
+			wantLen:        1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := SMSChannels(
+				context.Background(),
+				tt.twilioConfig,
+				noFileSystem,
+				tt.getLogProvider,
+				"success",
+				"failure",
+			)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLen, chain.Len())
+			if !tt.wantInstrumented {
+				return
+			}
+			m := &countingMetrics{counts: make(map[string]int64)}
+			previous := metrics.M
+			metrics.M = m
+			defer func() { metrics.M = previous }()
+
+			// twilio rejects emails without calling its api, the instrumentation counts the failure
+			err = chain.channels[0].HandleMessage(&messages.Email{
+				Content:         "content",
+				TriggeringEvent: &repository.Event{Typ: "user.human.phone.code.added"},
+			})
+			assert.Error(t, err)
+			assert.Equal(t, map[string]int64{"failure": 1}, m.counts)
+		})
+	}
+}
+
+type countingMetrics struct {
+	metrics.Metrics
+	counts map[string]int64
+}
+
+func (m *countingMetrics) AddCount(_ context.Context, name string, value int64, _ map[string]attribute.Value) error {
+	m.counts[name] += value
+	return nil
+}
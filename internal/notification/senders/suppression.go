@@ -0,0 +1,114 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/notification/channels"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+// ErrAllRecipientsSuppressed is returned if an email was not sent because all its recipients are suppressed
+var ErrAllRecipientsSuppressed = errors.New("all recipients are suppressed")
+
+var _ channels.NotificationChannel = (*Suppression)(nil)
+
+// SuppressionStore knows the addresses which must not receive emails, e.g. because of hard bounces or unsubscribes
+type SuppressionStore interface {
+	// IsSuppressed returns true if the normalized address must not receive emails of the instance
+	IsSuppressed(ctx context.Context, instanceID, address string) (bool, error)
+}
+
+// StaticSuppressions suppresses the configured addresses for all instances
+type StaticSuppressions map[string]struct{}
+
+var _ SuppressionStore = (StaticSuppressions)(nil)
+
+// NewStaticSuppressions returns the store suppressing the normalized addresses
+func NewStaticSuppressions(addresses []string) StaticSuppressions {
+	suppressions := make(StaticSuppressions, len(addresses))
+	for _, address := range addresses {
+		suppressions[NormalizeEmailAddress(address)] = struct{}{}
+	}
+	return suppressions
+}
+
+// IsSuppressed returns true if the address is configured, regardless of the instance
+func (s StaticSuppressions) IsSuppressed(_ context.Context, _, address string) (bool, error) {
+	_, ok := s[address]
+	return ok, nil
+}
+
+// Suppression removes the suppressed recipients of emails before they are passed to the channel
+type Suppression struct {
+	ctx        context.Context
+	channel    channels.NotificationChannel
+	store      SuppressionStore
+	instanceID string
+}
+
+func SuppressionChannel(ctx context.Context, channel channels.NotificationChannel, store SuppressionStore) *Suppression {
+	return &Suppression{
+		ctx:        ctx,
+		channel:    channel,
+		store:      store,
+		instanceID: authz.GetInstance(ctx).InstanceID(),
+	}
+}
+
+// HandleMessage passes the email without the suppressed recipients to the channel.
+// [ErrAllRecipientsSuppressed] is returned if no recipient is left.
+// The email is not sent if the store fails, messages other than emails are always passed.
+func (s *Suppression) HandleMessage(message channels.Message) error {
+	email, ok := message.(*messages.Email)
+	if !ok {
+		return s.channel.HandleMessage(message)
+	}
+	filtered := *email
+	var err error
+	if filtered.Recipients, err = s.allowed(email.Recipients); err != nil {
+		return err
+	}
+	if filtered.CC, err = s.allowed(email.CC); err != nil {
+		return err
+	}
+	if filtered.BCC, err = s.allowed(email.BCC); err != nil {
+		return err
+	}
+	if len(filtered.Recipients)+len(filtered.CC)+len(filtered.BCC) == 0 {
+		logging.WithFields("instance", s.instanceID).Info("all recipients are suppressed, email is not sent")
+		return ErrAllRecipientsSuppressed
+	}
+	return s.channel.HandleMessage(&filtered)
+}
+
+// allowed returns the addresses which are not suppressed
+func (s *Suppression) allowed(addresses []string) ([]string, error) {
+	allowed := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		suppressed, err := s.store.IsSuppressed(s.ctx, s.instanceID, NormalizeEmailAddress(address))
+		if err != nil {
+			return nil, err
+		}
+		if suppressed {
+			logging.WithFields("instance", s.instanceID).Debug("suppressed recipient removed from email")
+			continue
+		}
+		allowed = append(allowed, address)
+	}
+	return allowed, nil
+}
+
+// NormalizeEmailAddress returns the lower cased address without display name,
+// addresses which can't be parsed are only trimmed and lower cased
+func NormalizeEmailAddress(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	return strings.ToLower(strings.TrimSpace(address))
+}
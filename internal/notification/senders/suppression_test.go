@@ -0,0 +1,169 @@
+package senders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/notification/channels"
+	"github.com/zitadel/zitadel/internal/notification/messages"
+)
+
+// suppressionStore suppresses the addresses per instance
+type suppressionStore struct {
+	suppressed map[string][]string
+	err        error
+}
+
+func (s *suppressionStore) IsSuppressed(_ context.Context, instanceID, address string) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	for _, suppressed := range s.suppressed[instanceID] {
+		if suppressed == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestSuppression_HandleMessage(t *testing.T) {
+	errStore := errors.New("store failed")
+	store := &suppressionStore{
+		suppressed: map[string][]string{
+			"instance": {"bounced@example.com", "unsubscribed@example.com"},
+			"other":    {"allowed@example.com"},
+		},
+	}
+	tests := []struct {
+		name    string
+		store   *suppressionStore
+		email   *messages.Email
+		want    *messages.Email
+		wantErr error
+	}{
+		{
+			name:  "suppressed recipients removed",
+			store: store,
+			email: &messages.Email{
+				Recipients: []string{"allowed@example.com", "Bounced@Example.com"},
+				CC:         []string{"Unsubscribed <unsubscribed@example.com>", "cc@example.com"},
+				BCC:        []string{" bounced@example.com "},
+				Subject:    "subject",
+			},
+			want: &messages.Email{
+				Recipients: []string{"allowed@example.com"},
+				CC:         []string{"cc@example.com"},
+				BCC:        []string{},
+				Subject:    "subject",
+			},
+		},
+		{
+			name:  "no recipient suppressed",
+			store: store,
+			email: &messages.Email{
+				Recipients: []string{"allowed@example.com"},
+			},
+			want: &messages.Email{
+				Recipients: []string{"allowed@example.com"},
+				CC:         []string{},
+				BCC:        []string{},
+			},
+		},
+		{
+			name:  "all recipients suppressed",
+			store: store,
+			email: &messages.Email{
+				Recipients: []string{"bounced@example.com"},
+				CC:         []string{"unsubscribed@example.com"},
+			},
+			wantErr: ErrAllRecipientsSuppressed,
+		},
+		{
+			name:  "store failed",
+			store: &suppressionStore{err: errStore},
+			email: &messages.Email{
+				Recipients: []string{"allowed@example.com"},
+			},
+			wantErr: errStore,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []*messages.Email
+			channel := SuppressionChannel(
+				authz.WithInstanceID(context.Background(), "instance"),
+				channels.HandleMessageFunc(func(message channels.Message) error {
+					sent = append(sent, message.(*messages.Email))
+					return nil
+				}),
+				tt.store,
+			)
+
+			err := channel.HandleMessage(tt.email)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, sent)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, sent, 1)
+			assert.Equal(t, tt.want, sent[0])
+		})
+	}
+}
+
+func TestSuppression_HandleMessage_otherMessages(t *testing.T) {
+	var sent int
+	channel := SuppressionChannel(
+		context.Background(),
+		channels.HandleMessageFunc(func(channels.Message) error {
+			sent++
+			return nil
+		}),
+		&suppressionStore{err: errors.New("must not be called")},
+	)
+
+	require.NoError(t, channel.HandleMessage(&messages.SMS{}))
+	assert.Equal(t, 1, sent)
+}
+
+func TestNormalizeEmailAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{address: "user@example.com", want: "user@example.com"},
+		{address: " User@Example.COM ", want: "user@example.com"},
+		{address: "User <User@Example.com>", want: "user@example.com"},
+		{address: "Not An Address", want: "not an address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeEmailAddress(tt.address))
+		})
+	}
+}
+
+func TestStaticSuppressions_IsSuppressed(t *testing.T) {
+	store := NewStaticSuppressions([]string{"Bounced <Bounced@Example.com>", " unsubscribed@example.com "})
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "bounced@example.com", want: true},
+		{address: "unsubscribed@example.com", want: true},
+		{address: "user@example.com", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			got, err := store.IsSuppressed(context.Background(), "instance", tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -42,7 +42,7 @@ user_grants as (
 -- filter all orgs we are interested in.
 orgs as (
 	select id, name, primary_domain
-	from projections.orgs1
+	from %s
 	where id in (
 		select resource_owner from user_grants
 		union
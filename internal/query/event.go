@@ -42,11 +42,7 @@ func (r *eventsReducer) Reduce() error { return nil }
 func (q *Queries) SearchEvents(ctx context.Context, query *eventstore.SearchQueryBuilder) (_ []*Event, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
-	auditLogRetention := q.defaultAuditLogRetention
-	if instanceAuditLogRetention := authz.GetInstance(ctx).AuditLogRetention(); instanceAuditLogRetention != nil {
-		auditLogRetention = *instanceAuditLogRetention
-	}
-	if auditLogRetention != 0 {
+	if auditLogRetention := q.auditLogRetention(ctx); auditLogRetention != 0 {
 		query = filterAuditLogRetention(ctx, auditLogRetention, query)
 	}
 	reducer := &eventsReducer{ctx: ctx, q: q}
@@ -56,6 +52,23 @@ func (q *Queries) SearchEvents(ctx context.Context, query *eventstore.SearchQuer
 	return reducer.events, nil
 }
 
+// EventExists returns if at least one event matches the query of the builder.
+// It can be used to check if one of the given event types exists, e.g. by [eventstore.SearchQueryBuilder.ForEventTypes].
+func (q *Queries) EventExists(ctx context.Context, query *eventstore.SearchQueryBuilder) (_ bool, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	return q.eventstore.Exists(ctx, query)
+}
+
+// auditLogRetention returns the audit log retention of the instance, the default if the instance doesn't define one
+func (q *Queries) auditLogRetention(ctx context.Context) time.Duration {
+	if instanceAuditLogRetention := authz.GetInstance(ctx).AuditLogRetention(); instanceAuditLogRetention != nil {
+		return *instanceAuditLogRetention
+	}
+	return q.defaultAuditLogRetention
+}
+
 func filterAuditLogRetention(ctx context.Context, auditLogRetention time.Duration, builder *eventstore.SearchQueryBuilder) *eventstore.SearchQueryBuilder {
 	callTime := call.FromContext(ctx)
 	if callTime.IsZero() {
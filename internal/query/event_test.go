@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestQueries_EventExists(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		want       bool
+		wantErr    error
+	}{
+		{
+			name:       "event exists",
+			eventstore: expectEventstore(expectExists(true)),
+			want:       true,
+		},
+		{
+			name:       "no event exists",
+			eventstore: expectEventstore(expectExists(false)),
+			want:       false,
+		},
+		{
+			name:       "error",
+			eventstore: expectEventstore(expectExistsError(zerrors.ThrowInternal(nil, "ID", "error"))),
+			wantErr:    zerrors.ThrowInternal(nil, "ID", "error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Queries{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := q.EventExists(context.Background(),
+				eventstore.NewSearchQueryBuilder(eventstore.ColumnsExists).
+					ForEventTypes("user", "user.added", "user.changed").
+					Builder(),
+			)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
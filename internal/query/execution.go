@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strconv"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -43,13 +44,34 @@ var (
 		name:  projection.ExecutionSequenceCol,
 		table: executionTable,
 	}
-	ExecutionColumnTargets = Column{
-		name:  projection.ExecutionTargetsCol,
+	ExecutionColumnEnabled = Column{
+		name:  projection.ExecutionEnabledCol,
 		table: executionTable,
 	}
-	ExecutionColumnIncludes = Column{
-		name:  projection.ExecutionIncludesCol,
-		table: executionTable,
+
+	executionTargetTable = table{
+		name:          projection.ExecutionTargetTable,
+		instanceIDCol: projection.ExecutionTargetInstanceIDCol,
+	}
+	ExecutionTargetColumnInstanceID = Column{
+		name:  projection.ExecutionTargetInstanceIDCol,
+		table: executionTargetTable,
+	}
+	ExecutionTargetColumnExecutionID = Column{
+		name:  projection.ExecutionTargetExecutionIDCol,
+		table: executionTargetTable,
+	}
+	ExecutionTargetColumnType = Column{
+		name:  projection.ExecutionTargetTypeCol,
+		table: executionTargetTable,
+	}
+	ExecutionTargetColumnTarget = Column{
+		name:  projection.ExecutionTargetTargetCol,
+		table: executionTargetTable,
+	}
+	ExecutionTargetColumnPosition = Column{
+		name:  projection.ExecutionTargetPositionCol,
+		table: executionTargetTable,
 	}
 )
 
@@ -68,6 +90,8 @@ type Execution struct {
 
 	Targets  database.TextArray[string]
 	Includes database.TextArray[string]
+	// Enabled is false if the execution is temporarily disabled
+	Enabled bool
 }
 
 type ExecutionSearchQueries struct {
@@ -100,6 +124,103 @@ func (q *Queries) GetExecutionByID(ctx context.Context, id string) (execution *E
 	return genericRowQuery[*Execution](ctx, q.client, query.Where(eq), scan)
 }
 
+// GetExecutionsByIDs returns the executions found for the ids,
+// a not found error is only returned if none of the executions exist
+func (q *Queries) GetExecutionsByIDs(ctx context.Context, ids []string) (_ []*Execution, err error) {
+	if len(ids) == 0 {
+		return nil, zerrors.ThrowNotFound(nil, "QUERY-Ex8nf", "Errors.Execution.NotFound")
+	}
+	eq := sq.Eq{
+		ExecutionColumnID.identifier():         ids,
+		ExecutionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareExecutionsQuery(ctx, q.client)
+	executions, err := genericRowsQuery[*Executions](ctx, q.client, query.Where(eq), scan)
+	if err != nil {
+		return nil, err
+	}
+	if len(executions.Executions) == 0 {
+		return nil, zerrors.ThrowNotFound(nil, "QUERY-Ex9nf", "Errors.Execution.NotFound")
+	}
+	return executions.Executions, nil
+}
+
+// maxExecutionIncludeDepth limits how deep includes of executions are resolved
+const maxExecutionIncludeDepth = 10
+
+// ResolveExecutionTargets returns the targets of the execution merged with the targets of all included executions.
+// The targets are ordered by their first occurrence, included executions are resolved after the targets of the including execution.
+func (q *Queries) ResolveExecutionTargets(ctx context.Context, id, resourceOwner string) ([]string, error) {
+	resolver := newExecutionResolver(func(ctx context.Context, id string) (*Execution, error) {
+		return q.getExecutionByIDAndResourceOwner(ctx, id, resourceOwner)
+	})
+	if err := resolver.resolve(ctx, id, 0); err != nil {
+		return nil, err
+	}
+	return resolver.targets, nil
+}
+
+func (q *Queries) getExecutionByIDAndResourceOwner(ctx context.Context, id, resourceOwner string) (*Execution, error) {
+	eq := sq.Eq{
+		ExecutionColumnID.identifier():            id,
+		ExecutionColumnResourceOwner.identifier(): resourceOwner,
+		ExecutionColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareExecutionQuery(ctx, q.client)
+	return genericRowQuery[*Execution](ctx, q.client, query.Where(eq), scan)
+}
+
+type executionResolver struct {
+	getExecution func(ctx context.Context, id string) (*Execution, error)
+	// resolving contains the executions of the current include path to detect cycles
+	resolving map[string]bool
+	// resolved contains the executions which are already merged, e.g. if included multiple times
+	resolved map[string]bool
+	added    map[string]bool
+	targets  []string
+}
+
+func newExecutionResolver(getExecution func(ctx context.Context, id string) (*Execution, error)) *executionResolver {
+	return &executionResolver{
+		getExecution: getExecution,
+		resolving:    make(map[string]bool),
+		resolved:     make(map[string]bool),
+		added:        make(map[string]bool),
+	}
+}
+
+func (r *executionResolver) resolve(ctx context.Context, id string, depth int) error {
+	if r.resolving[id] {
+		return zerrors.ThrowPreconditionFailed(nil, "QUERY-d1ynmdjdgc", "Errors.Execution.IncludeCycle")
+	}
+	if r.resolved[id] {
+		return nil
+	}
+	if depth > maxExecutionIncludeDepth {
+		return zerrors.ThrowPreconditionFailed(nil, "QUERY-s6b3ysl6tu", "Errors.Execution.IncludeMaxDepth")
+	}
+	execution, err := r.getExecution(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	r.resolving[id] = true
+	for _, target := range execution.Targets {
+		if !r.added[target] {
+			r.added[target] = true
+			r.targets = append(r.targets, target)
+		}
+	}
+	for _, include := range execution.Includes {
+		if err := r.resolve(ctx, include, depth+1); err != nil {
+			return err
+		}
+	}
+	delete(r.resolving, id)
+	r.resolved[id] = true
+	return nil
+}
+
 func NewExecutionInIDsSearchQuery(values []string) (SearchQuery, error) {
 	return NewInTextQuery(ExecutionColumnID, values)
 }
@@ -108,12 +229,90 @@ func NewExecutionTypeSearchQuery(t domain.ExecutionType) (SearchQuery, error) {
 	return NewTextQuery(ExecutionColumnID, t.String(), TextStartsWith)
 }
 
+func NewExecutionEnabledSearchQuery(enabled bool) (SearchQuery, error) {
+	return NewBoolQuery(ExecutionColumnEnabled, enabled)
+}
+
 func NewExecutionTargetSearchQuery(value string) (SearchQuery, error) {
-	return NewTextQuery(ExecutionColumnTargets, value, TextListContains)
+	return newExecutionTargetTypeSearchQuery(domain.ExecutionTargetTypeTarget, value)
 }
 
 func NewExecutionIncludeSearchQuery(value string) (SearchQuery, error) {
-	return NewTextQuery(ExecutionColumnIncludes, value, TextListContains)
+	return newExecutionTargetTypeSearchQuery(domain.ExecutionTargetTypeInclude, value)
+}
+
+func newExecutionTargetTypeSearchQuery(targetType domain.ExecutionTargetType, value string) (SearchQuery, error) {
+	//linking queries for the subselect
+	instanceQuery, err := NewColumnComparisonQuery(ExecutionTargetColumnInstanceID, ExecutionColumnInstanceID, ColumnEquals)
+	if err != nil {
+		return nil, err
+	}
+	executionIDQuery, err := NewColumnComparisonQuery(ExecutionTargetColumnExecutionID, ExecutionColumnID, ColumnEquals)
+	if err != nil {
+		return nil, err
+	}
+	typeQuery, err := NewNumberQuery(ExecutionTargetColumnType, targetType, NumberEquals)
+	if err != nil {
+		return nil, err
+	}
+	targetQuery, err := NewTextQuery(ExecutionTargetColumnTarget, value, TextEquals)
+	if err != nil {
+		return nil, err
+	}
+	subSelect, err := NewSubSelect(ExecutionTargetColumnExecutionID, []SearchQuery{instanceQuery, executionIDQuery, typeQuery, targetQuery})
+	if err != nil {
+		return nil, err
+	}
+	return NewListQuery(ExecutionColumnID, subSelect, ListIn)
+}
+
+// NewExecutionTargetTypeQuery matches the executions calling at least one target of the given type,
+// the type is looked up in the targets because it can change after the execution was set
+func NewExecutionTargetTypeQuery(targetType domain.TargetType) (SearchQuery, error) {
+	//linking queries for the subselects
+	targetInstanceQuery, err := NewColumnComparisonQuery(TargetColumnInstanceID, ExecutionTargetColumnInstanceID, ColumnEquals)
+	if err != nil {
+		return nil, err
+	}
+	targetTypeQuery, err := NewNumberQuery(TargetColumnTargetType, targetType, NumberEquals)
+	if err != nil {
+		return nil, err
+	}
+	targetSubSelect, err := NewSubSelect(TargetColumnID, []SearchQuery{targetInstanceQuery, targetTypeQuery})
+	if err != nil {
+		return nil, err
+	}
+	instanceQuery, err := NewColumnComparisonQuery(ExecutionTargetColumnInstanceID, ExecutionColumnInstanceID, ColumnEquals)
+	if err != nil {
+		return nil, err
+	}
+	executionIDQuery, err := NewColumnComparisonQuery(ExecutionTargetColumnExecutionID, ExecutionColumnID, ColumnEquals)
+	if err != nil {
+		return nil, err
+	}
+	typeQuery, err := NewNumberQuery(ExecutionTargetColumnType, domain.ExecutionTargetTypeTarget, NumberEquals)
+	if err != nil {
+		return nil, err
+	}
+	targetQuery, err := NewListQuery(ExecutionTargetColumnTarget, targetSubSelect, ListIn)
+	if err != nil {
+		return nil, err
+	}
+	subSelect, err := NewSubSelect(ExecutionTargetColumnExecutionID, []SearchQuery{instanceQuery, executionIDQuery, typeQuery, targetQuery})
+	if err != nil {
+		return nil, err
+	}
+	return NewListQuery(ExecutionColumnID, subSelect, ListIn)
+}
+
+// executionTargetsColumn reassembles the targets of the given type of an execution ordered by their position
+func executionTargetsColumn(targetType domain.ExecutionTargetType) string {
+	return "ARRAY(SELECT " + ExecutionTargetColumnTarget.identifier() +
+		" FROM " + executionTargetTable.identifier() +
+		" WHERE " + ExecutionTargetColumnInstanceID.identifier() + " = " + ExecutionColumnInstanceID.identifier() +
+		" AND " + ExecutionTargetColumnExecutionID.identifier() + " = " + ExecutionColumnID.identifier() +
+		" AND " + ExecutionTargetColumnType.identifier() + " = " + strconv.Itoa(int(targetType)) +
+		" ORDER BY " + ExecutionTargetColumnPosition.identifier() + ")"
 }
 
 func prepareExecutionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (*Executions, error)) {
@@ -122,8 +321,9 @@ func prepareExecutionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 			ExecutionColumnChangeDate.identifier(),
 			ExecutionColumnResourceOwner.identifier(),
 			ExecutionColumnSequence.identifier(),
-			ExecutionColumnTargets.identifier(),
-			ExecutionColumnIncludes.identifier(),
+			executionTargetsColumn(domain.ExecutionTargetTypeTarget),
+			executionTargetsColumn(domain.ExecutionTargetTypeInclude),
+			ExecutionColumnEnabled.identifier(),
 			countColumn.identifier(),
 		).From(executionTable.identifier()).
 			PlaceholderFormat(sq.Dollar),
@@ -139,6 +339,7 @@ func prepareExecutionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 					&execution.Sequence,
 					&execution.Targets,
 					&execution.Includes,
+					&execution.Enabled,
 					&count,
 				)
 				if err != nil {
@@ -166,8 +367,9 @@ func prepareExecutionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBu
 			ExecutionColumnChangeDate.identifier(),
 			ExecutionColumnResourceOwner.identifier(),
 			ExecutionColumnSequence.identifier(),
-			ExecutionColumnTargets.identifier(),
-			ExecutionColumnIncludes.identifier(),
+			executionTargetsColumn(domain.ExecutionTargetTypeTarget),
+			executionTargetsColumn(domain.ExecutionTargetTypeInclude),
+			ExecutionColumnEnabled.identifier(),
 		).From(executionTable.identifier()).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*Execution, error) {
@@ -179,6 +381,7 @@ func prepareExecutionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBu
 				&execution.Sequence,
 				&execution.Targets,
 				&execution.Includes,
+				&execution.Enabled,
 			)
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
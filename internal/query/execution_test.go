@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -8,20 +9,26 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
-	prepareExecutionsStmt = `SELECT projections.executions.id,` +
-		` projections.executions.change_date,` +
-		` projections.executions.resource_owner,` +
-		` projections.executions.sequence,` +
-		` projections.executions.targets,` +
-		` projections.executions.includes,` +
+	prepareExecutionsStmt = `SELECT projections.executions1.id,` +
+		` projections.executions1.change_date,` +
+		` projections.executions1.resource_owner,` +
+		` projections.executions1.sequence,` +
+		` ARRAY(SELECT projections.executions1_targets.target FROM projections.executions1_targets WHERE projections.executions1_targets.instance_id = projections.executions1.instance_id AND projections.executions1_targets.execution_id = projections.executions1.id AND projections.executions1_targets.type = 1 ORDER BY projections.executions1_targets.position),` +
+		` ARRAY(SELECT projections.executions1_targets.target FROM projections.executions1_targets WHERE projections.executions1_targets.instance_id = projections.executions1.instance_id AND projections.executions1_targets.execution_id = projections.executions1.id AND projections.executions1_targets.type = 2 ORDER BY projections.executions1_targets.position),` +
+		` projections.executions1.enabled,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.executions`
+		` FROM projections.executions1`
 	prepareExecutionsCols = []string{
 		"id",
 		"change_date",
@@ -29,16 +36,18 @@ var (
 		"sequence",
 		"targets",
 		"includes",
+		"enabled",
 		"count",
 	}
 
-	prepareExecutionStmt = `SELECT projections.executions.id,` +
-		` projections.executions.change_date,` +
-		` projections.executions.resource_owner,` +
-		` projections.executions.sequence,` +
-		` projections.executions.targets,` +
-		` projections.executions.includes` +
-		` FROM projections.executions`
+	prepareExecutionStmt = `SELECT projections.executions1.id,` +
+		` projections.executions1.change_date,` +
+		` projections.executions1.resource_owner,` +
+		` projections.executions1.sequence,` +
+		` ARRAY(SELECT projections.executions1_targets.target FROM projections.executions1_targets WHERE projections.executions1_targets.instance_id = projections.executions1.instance_id AND projections.executions1_targets.execution_id = projections.executions1.id AND projections.executions1_targets.type = 1 ORDER BY projections.executions1_targets.position),` +
+		` ARRAY(SELECT projections.executions1_targets.target FROM projections.executions1_targets WHERE projections.executions1_targets.instance_id = projections.executions1.instance_id AND projections.executions1_targets.execution_id = projections.executions1.id AND projections.executions1_targets.type = 2 ORDER BY projections.executions1_targets.position),` +
+		` projections.executions1.enabled` +
+		` FROM projections.executions1`
 	prepareExecutionCols = []string{
 		"id",
 		"change_date",
@@ -46,6 +55,7 @@ var (
 		"sequence",
 		"targets",
 		"includes",
+		"enabled",
 	}
 )
 
@@ -87,6 +97,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 							uint64(20211109),
 							database.TextArray[string]{"target"},
 							database.TextArray[string]{"include"},
+							true,
 						},
 					},
 				),
@@ -105,6 +116,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 						},
 						Targets:  database.TextArray[string]{"target"},
 						Includes: database.TextArray[string]{"include"},
+						Enabled:  true,
 					},
 				},
 			},
@@ -124,6 +136,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 							uint64(20211109),
 							database.TextArray[string]{"target1"},
 							database.TextArray[string]{"include1"},
+							true,
 						},
 						{
 							"id-2",
@@ -132,6 +145,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 							uint64(20211110),
 							database.TextArray[string]{"target2"},
 							database.TextArray[string]{"include2"},
+							false,
 						},
 					},
 				),
@@ -150,6 +164,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 						},
 						Targets:  database.TextArray[string]{"target1"},
 						Includes: database.TextArray[string]{"include1"},
+						Enabled:  true,
 					},
 					{
 						ID: "id-2",
@@ -160,6 +175,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 						},
 						Targets:  database.TextArray[string]{"target2"},
 						Includes: database.TextArray[string]{"include2"},
+						Enabled:  false,
 					},
 				},
 			},
@@ -213,6 +229,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 						uint64(20211109),
 						database.TextArray[string]{"target"},
 						database.TextArray[string]{"include"},
+						true,
 					},
 				),
 			},
@@ -225,6 +242,7 @@ func Test_ExecutionPrepares(t *testing.T) {
 				},
 				Targets:  database.TextArray[string]{"target"},
 				Includes: database.TextArray[string]{"include"},
+				Enabled:  true,
 			},
 		},
 		{
@@ -251,3 +269,208 @@ func Test_ExecutionPrepares(t *testing.T) {
 		})
 	}
 }
+
+func TestQueries_GetExecutionsByIDs(t *testing.T) {
+	getExecutionsByIDsStmt := prepareExecutionsStmt +
+		` WHERE projections.executions1.id IN ($1,$2,$3)` +
+		` AND projections.executions1.instance_id = $4`
+
+	type want struct {
+		sqlExpectations sqlExpectation
+		executions      []*Execution
+		err             func(error) bool
+	}
+	tests := []struct {
+		name string
+		ids  []string
+		want want
+	}{
+		{
+			name: "all found",
+			ids:  []string{"id-1", "id-2", "id-3"},
+			want: want{
+				sqlExpectations: mockQueries(getExecutionsByIDsStmt, prepareExecutionsCols,
+					[][]driver.Value{
+						{"id-1", testNow, "ro", uint64(20211109), database.TextArray[string]{"target1"}, database.TextArray[string]{}, true},
+						{"id-2", testNow, "ro", uint64(20211110), database.TextArray[string]{"target2"}, database.TextArray[string]{}, true},
+						{"id-3", testNow, "ro", uint64(20211111), database.TextArray[string]{"target3"}, database.TextArray[string]{}, false},
+					},
+					"id-1", "id-2", "id-3", "",
+				),
+				executions: []*Execution{
+					{ID: "id-1", ObjectDetails: domain.ObjectDetails{EventDate: testNow, ResourceOwner: "ro", Sequence: 20211109}, Targets: database.TextArray[string]{"target1"}, Includes: database.TextArray[string]{}, Enabled: true},
+					{ID: "id-2", ObjectDetails: domain.ObjectDetails{EventDate: testNow, ResourceOwner: "ro", Sequence: 20211110}, Targets: database.TextArray[string]{"target2"}, Includes: database.TextArray[string]{}, Enabled: true},
+					{ID: "id-3", ObjectDetails: domain.ObjectDetails{EventDate: testNow, ResourceOwner: "ro", Sequence: 20211111}, Targets: database.TextArray[string]{"target3"}, Includes: database.TextArray[string]{}, Enabled: false},
+				},
+			},
+		},
+		{
+			name: "partial match",
+			ids:  []string{"id-1", "id-2", "id-3"},
+			want: want{
+				sqlExpectations: mockQueries(getExecutionsByIDsStmt, prepareExecutionsCols,
+					[][]driver.Value{
+						{"id-2", testNow, "ro", uint64(20211110), database.TextArray[string]{"target2"}, database.TextArray[string]{"include"}, true},
+					},
+					"id-1", "id-2", "id-3", "",
+				),
+				executions: []*Execution{
+					{ID: "id-2", ObjectDetails: domain.ObjectDetails{EventDate: testNow, ResourceOwner: "ro", Sequence: 20211110}, Targets: database.TextArray[string]{"target2"}, Includes: database.TextArray[string]{"include"}, Enabled: true},
+				},
+			},
+		},
+		{
+			name: "none found",
+			ids:  []string{"id-1", "id-2", "id-3"},
+			want: want{
+				sqlExpectations: mockQueries(getExecutionsByIDsStmt, prepareExecutionsCols, nil, "id-1", "id-2", "id-3", ""),
+				err:             zerrors.IsNotFound,
+			},
+		},
+		{
+			name: "no ids",
+			want: want{
+				sqlExpectations: func(m sqlmock.Sqlmock) sqlmock.Sqlmock { return m },
+				err:             zerrors.IsNotFound,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(
+				sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+				sqlmock.ValueConverterOption(new(db_mock.TypeConverter)),
+			)
+			require.NoError(t, err)
+			tt.want.sqlExpectations(mock)
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+
+			executions, err := q.GetExecutionsByIDs(context.Background(), tt.ids)
+			if tt.want.err != nil {
+				assert.True(t, tt.want.err(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want.executions, executions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNewExecutionTargetSearchQuery(t *testing.T) {
+	query, err := NewExecutionTargetSearchQuery("target")
+	require.NoError(t, err)
+	stmt, args, err := query.comp().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `projections.executions1.id IN ( SELECT projections.executions1_targets.execution_id FROM projections.executions1_targets WHERE projections.executions1_targets.instance_id = projections.executions1.instance_id AND projections.executions1_targets.execution_id = projections.executions1.id AND projections.executions1_targets.type = ? AND projections.executions1_targets.target = ? )`, stmt)
+	assert.Equal(t, []interface{}{domain.ExecutionTargetTypeTarget, "target"}, args)
+}
+
+func TestNewExecutionTargetTypeQuery(t *testing.T) {
+	query, err := NewExecutionTargetTypeQuery(domain.TargetTypeWebhook)
+	require.NoError(t, err)
+	stmt, args, err := query.comp().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `projections.executions1.id IN ( SELECT projections.executions1_targets.execution_id FROM projections.executions1_targets WHERE projections.executions1_targets.instance_id = projections.executions1.instance_id AND projections.executions1_targets.execution_id = projections.executions1.id AND projections.executions1_targets.type = ? AND projections.executions1_targets.target IN ( SELECT projections.targets.id FROM projections.targets WHERE projections.targets.instance_id = projections.executions1_targets.instance_id AND projections.targets.target_type = ? ) )`, stmt)
+	assert.Equal(t, []interface{}{domain.ExecutionTargetTypeTarget, domain.TargetTypeWebhook}, args)
+}
+
+func TestNewExecutionEnabledSearchQuery(t *testing.T) {
+	query, err := NewExecutionEnabledSearchQuery(false)
+	require.NoError(t, err)
+	stmt, args, err := query.comp().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `projections.executions1.enabled = ?`, stmt)
+	assert.Equal(t, []interface{}{false}, args)
+}
+
+func Test_executionResolver_resolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		executions map[string]*Execution
+		want       []string
+		wantErr    func(error) bool
+	}{
+		{
+			name: "no includes",
+			executions: map[string]*Execution{
+				"a": {ID: "a", Targets: []string{"t1", "t2"}},
+			},
+			want: []string{"t1", "t2"},
+		},
+		{
+			name: "diamond",
+			executions: map[string]*Execution{
+				"a": {ID: "a", Targets: []string{"t1"}, Includes: []string{"b", "c"}},
+				"b": {ID: "b", Targets: []string{"t2"}, Includes: []string{"d"}},
+				"c": {ID: "c", Targets: []string{"t3", "t1"}, Includes: []string{"d"}},
+				"d": {ID: "d", Targets: []string{"t4"}},
+			},
+			want: []string{"t1", "t2", "t4", "t3"},
+		},
+		{
+			name: "cycle",
+			executions: map[string]*Execution{
+				"a": {ID: "a", Targets: []string{"t1"}, Includes: []string{"b"}},
+				"b": {ID: "b", Targets: []string{"t2"}, Includes: []string{"c"}},
+				"c": {ID: "c", Targets: []string{"t3"}, Includes: []string{"a"}},
+			},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+		{
+			name:       "max depth exceeded",
+			executions: executionChain(maxExecutionIncludeDepth + 2),
+			wantErr:    zerrors.IsPreconditionFailed,
+		},
+		{
+			name:       "max depth",
+			executions: executionChain(maxExecutionIncludeDepth + 1),
+			want:       []string{"t0", "t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8", "t9", "t10"},
+		},
+		{
+			name: "include not found",
+			executions: map[string]*Execution{
+				"a": {ID: "a", Targets: []string{"t1"}, Includes: []string{"b"}},
+			},
+			wantErr: zerrors.IsNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newExecutionResolver(func(_ context.Context, id string) (*Execution, error) {
+				execution, ok := tt.executions[id]
+				if !ok {
+					return nil, zerrors.ThrowNotFound(nil, "QUERY-qzn1xycesh", "Errors.Execution.NotFound")
+				}
+				return execution, nil
+			})
+			err := resolver.resolve(context.Background(), "a", 0)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resolver.targets)
+		})
+	}
+}
+
+// executionChain returns the given amount of executions, each including the next one
+func executionChain(length int) map[string]*Execution {
+	executions := make(map[string]*Execution, length)
+	id := "a"
+	for i := 0; i < length; i++ {
+		execution := &Execution{ID: id, Targets: []string{fmt.Sprintf("t%d", i)}}
+		if i < length-1 {
+			execution.Includes = []string{fmt.Sprintf("e%d", i+1)}
+		}
+		executions[id] = execution
+		id = fmt.Sprintf("e%d", i+1)
+	}
+	return executions
+}
@@ -3,7 +3,9 @@ package query
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"errors"
+	"io"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -11,10 +13,11 @@ import (
 	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
-	"github.com/zitadel/zitadel/internal/api/call"
 	domain_pkg "github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -22,6 +25,7 @@ import (
 var (
 	orgsTable = table{
 		name:          projection.OrgProjectionTable,
+		active:        projection.OrgsTable,
 		instanceIDCol: projection.OrgColumnInstanceID,
 	}
 	OrgColumnID = Column{
@@ -60,6 +64,22 @@ var (
 		name:  projection.OrgColumnDomain,
 		table: orgsTable,
 	}
+	OrgColumnParentID = Column{
+		name:  projection.OrgColumnParentID,
+		table: orgsTable,
+	}
+	OrgColumnNormalizedName = Column{
+		name:  projection.OrgColumnNormalizedName,
+		table: orgsTable,
+	}
+	OrgColumnPreviousName = Column{
+		name:  projection.OrgColumnPreviousName,
+		table: orgsTable,
+	}
+	OrgColumnPreviousNameChangeDate = Column{
+		name:  projection.OrgColumnPreviousNameChangeDate,
+		table: orgsTable,
+	}
 )
 
 type Orgs struct {
@@ -75,8 +95,25 @@ type Org struct {
 	State         domain_pkg.OrgState
 	Sequence      uint64
 
-	Name   string
+	Name string
+	// Domain is the primary domain of the organization
 	Domain string
+	// ParentID is the id of the parent organization, empty if the organization has no parent
+	ParentID string
+	// MatchedDomain is the verified domain which satisfied the lookup,
+	// only set by [Queries.OrgByVerifiedDomain]
+	MatchedDomain string
+}
+
+// OrgChange is an event of the history of an organization
+type OrgChange struct {
+	Sequence     uint64
+	CreationDate time.Time
+	EventType    eventstore.EventType
+	// Editor is the id of the user who caused the change
+	Editor string
+	// Name is the name of the organization after the change, only set if the change set the name
+	Name string
 }
 
 type OrgSearchQueries struct {
@@ -119,7 +156,40 @@ func (q *Queries) OrgByID(ctx context.Context, shouldTriggerBulk bool, id string
 	return org, err
 }
 
-func (q *Queries) OrgByPrimaryDomain(ctx context.Context, domain string) (org *Org, err error) {
+// OrgByIDIncludeRemoved returns the org like [Queries.OrgByID],
+// but a removed org is returned with state removed instead of a not found error.
+// Removed orgs are deleted from the projection, therefore they are reduced from their events.
+func (q *Queries) OrgByIDIncludeRemoved(ctx context.Context, shouldTriggerBulk bool, id string) (org *Org, err error) {
+	org, err = q.OrgByID(ctx, shouldTriggerBulk, id)
+	if !zerrors.IsNotFound(err) {
+		return org, err
+	}
+
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	model := NewOrgReadModel(id, authz.GetInstance(ctx).InstanceID())
+	if err := q.eventstore.FilterToQueryReducer(ctx, model); err != nil {
+		return nil, err
+	}
+	if model.State != domain_pkg.OrgStateRemoved {
+		return nil, zerrors.ThrowNotFound(nil, "QUERY-Or9mv", "Errors.Org.NotFound")
+	}
+	return model.org(), nil
+}
+
+// OrgByPrimaryDomain returns the active org of the instance with the given primary domain.
+// The result is cached if the cache is enabled using [WithOrgDomainCache].
+func (q *Queries) OrgByPrimaryDomain(ctx context.Context, domain string) (*Org, error) {
+	if q.orgDomainCache == nil {
+		return q.orgByPrimaryDomain(ctx, domain)
+	}
+	return q.orgDomainCache.load(ctx, authz.GetInstance(ctx).InstanceID(), orgDomainCacheKey{domain: domain}, func(ctx context.Context) (*Org, error) {
+		return q.orgByPrimaryDomain(ctx, domain)
+	})
+}
+
+func (q *Queries) orgByPrimaryDomain(ctx context.Context, domain string) (org *Org, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
 
@@ -140,7 +210,18 @@ func (q *Queries) OrgByPrimaryDomain(ctx context.Context, domain string) (org *O
 	return org, err
 }
 
-func (q *Queries) OrgByVerifiedDomain(ctx context.Context, domain string) (org *Org, err error) {
+// OrgByVerifiedDomain returns the org of the instance with the given verified domain.
+// The result is cached if the cache is enabled using [WithOrgDomainCache].
+func (q *Queries) OrgByVerifiedDomain(ctx context.Context, domain string) (*Org, error) {
+	if q.orgDomainCache == nil {
+		return q.orgByVerifiedDomain(ctx, domain)
+	}
+	return q.orgDomainCache.load(ctx, authz.GetInstance(ctx).InstanceID(), orgDomainCacheKey{domain: domain, verified: true}, func(ctx context.Context) (*Org, error) {
+		return q.orgByVerifiedDomain(ctx, domain)
+	})
+}
+
+func (q *Queries) orgByVerifiedDomain(ctx context.Context, domain string) (org *Org, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
 
@@ -161,6 +242,8 @@ func (q *Queries) OrgByVerifiedDomain(ctx context.Context, domain string) (org *
 	return org, err
 }
 
+// IsOrgUnique checks that no org uses the verified domain or the name.
+// Names are compared normalized (see [domain_pkg.NormalizeOrgName]) and domains case-insensitive.
 func (q *Queries) IsOrgUnique(ctx context.Context, name, domain string) (isUnique bool, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -179,8 +262,8 @@ func (q *Queries) IsOrgUnique(ctx context.Context, name, domain string) (isUniqu
 				sq.ILike{
 					OrgDomainDomainCol.identifier(): domain,
 				},
-				sq.ILike{
-					OrgColumnName.identifier(): name,
+				sq.Eq{
+					OrgColumnNormalizedName.identifier(): domain_pkg.NormalizeOrgName(name),
 				},
 			},
 			sq.NotEq{
@@ -198,6 +281,31 @@ func (q *Queries) IsOrgUnique(ctx context.Context, name, domain string) (isUniqu
 	return isUnique, err
 }
 
+// CountOrgsByState returns the amount of organizations of the instance per state.
+// States without organizations are returned with a count of 0.
+// Removed organizations are deleted from the projection and therefore not counted.
+func (q *Queries) CountOrgsByState(ctx context.Context) (counts map[domain_pkg.OrgState]uint64, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	query, scan := prepareCountOrgsByStateQuery(ctx, q.client)
+	stmt, args, err := query.Where(sq.Eq{
+		OrgColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+	}).ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Rk4cU", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		counts, err = scan(rows)
+		return err
+	}, stmt, args...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Rk4cV", "Errors.Internal")
+	}
+	return counts, nil
+}
+
 func (q *Queries) ExistsOrg(ctx context.Context, id, domain string) (verifiedID string, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -241,6 +349,85 @@ func (q *Queries) SearchOrgs(ctx context.Context, queries *OrgSearchQueries) (or
 	return orgs, err
 }
 
+// orgsCSVHeader is the header row written by [Queries.ExportOrgsCSV]
+var orgsCSVHeader = []string{"id", "name", "domain", "state", "creation_date"}
+
+// ExportOrgsCSV writes the organizations of the instance matching the queries as CSV to w.
+// The rows are written while they are read from the database,
+// so the organizations are never held in memory all at once.
+func (q *Queries) ExportOrgsCSV(ctx context.Context, queries *OrgSearchQueries, w io.Writer) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	query, scan := prepareOrgsCSVQuery(ctx, q.client)
+	stmt, args, err := queries.toQuery(query).
+		Where(sq.Eq{
+			OrgColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		}).ToSql()
+	if err != nil {
+		return zerrors.ThrowInvalidArgument(err, "QUERY-Cs4vO", "Errors.Query.InvalidRequest")
+	}
+
+	writer := csv.NewWriter(w)
+	if err = writer.Write(orgsCSVHeader); err != nil {
+		return zerrors.ThrowInternal(err, "QUERY-Cs4vW", "Errors.Internal")
+	}
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		return scan(rows, writer)
+	}, stmt, args...)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "QUERY-Cs4vQ", "Errors.Internal")
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return zerrors.ThrowInternal(err, "QUERY-Cs4vF", "Errors.Internal")
+	}
+	return nil
+}
+
+// OrgChanges returns the history of the organization, the latest change first.
+// At most limit changes are returned if limit is set.
+// Changes older than the audit log retention of the instance are not returned.
+func (q *Queries) OrgChanges(ctx context.Context, orgID string, limit uint64) (changes []*OrgChange, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if orgID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "QUERY-Oc4hs", "Errors.IDMissing")
+	}
+	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(authz.GetInstance(ctx).InstanceID()).
+		OrderDesc().
+		Limit(limit).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(orgID).
+		Builder()
+	if auditLogRetention := q.auditLogRetention(ctx); auditLogRetention != 0 {
+		query = filterAuditLogRetention(ctx, auditLogRetention, query)
+	}
+	events, err := q.eventstore.Filter(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	changes = make([]*OrgChange, len(events))
+	for i, event := range events {
+		changes[i] = &OrgChange{
+			Sequence:     event.Sequence(),
+			CreationDate: event.CreatedAt(),
+			EventType:    event.Type(),
+			Editor:       event.Creator(),
+		}
+		switch e := event.(type) {
+		case *org.OrgAddedEvent:
+			changes[i].Name = e.Name
+		case *org.OrgChangedEvent:
+			changes[i].Name = e.Name
+		}
+	}
+	return changes, nil
+}
+
 func NewOrgDomainSearchQuery(method TextComparison, value string) (SearchQuery, error) {
 	return NewTextQuery(OrgColumnDomain, value, method)
 }
@@ -249,10 +436,46 @@ func NewOrgNameSearchQuery(method TextComparison, value string) (SearchQuery, er
 	return NewTextQuery(OrgColumnName, value, method)
 }
 
+// NewOrgNameOrPreviousNameSearchQuery matches orgs by their current name
+// or by the name they had before their last rename, if the rename happened after renamedAfter.
+// Previous names of renames before renamedAfter are ignored, so that they are found by their old name only for a limited period.
+func NewOrgNameOrPreviousNameSearchQuery(method TextComparison, value string, renamedAfter time.Time) (SearchQuery, error) {
+	nameQuery, err := NewTextQuery(OrgColumnName, value, method)
+	if err != nil {
+		return nil, err
+	}
+	previousNameQuery, err := NewTextQuery(OrgColumnPreviousName, value, method)
+	if err != nil {
+		return nil, err
+	}
+	renamedAfterQuery, err := NewTimestampQuery(OrgColumnPreviousNameChangeDate, renamedAfter, TimestampGreater)
+	if err != nil {
+		return nil, err
+	}
+	recentlyRenamedQuery, err := NewAndQuery(previousNameQuery, renamedAfterQuery)
+	if err != nil {
+		return nil, err
+	}
+	return NewOrQuery(nameQuery, recentlyRenamedQuery)
+}
+
+func NewOrgCreationDateQuery(compare TimestampComparison, value time.Time) (SearchQuery, error) {
+	return NewTimestampQuery(OrgColumnCreationDate, value, compare)
+}
+
+func NewOrgChangeDateQuery(compare TimestampComparison, value time.Time) (SearchQuery, error) {
+	return NewTimestampQuery(OrgColumnChangeDate, value, compare)
+}
+
 func NewOrgStateSearchQuery(value domain_pkg.OrgState) (SearchQuery, error) {
 	return NewNumberQuery(OrgColumnState, value, NumberEquals)
 }
 
+// NewOrgParentSearchQuery returns the direct children of the organization with the given id
+func NewOrgParentSearchQuery(parentID string) (SearchQuery, error) {
+	return NewTextQuery(OrgColumnParentID, parentID, TextEquals)
+}
+
 func NewOrgIDsSearchQuery(ids ...string) (SearchQuery, error) {
 	list := make([]interface{}, len(ids))
 	for i, value := range ids {
@@ -271,8 +494,9 @@ func prepareOrgsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder
 			OrgColumnSequence.identifier(),
 			OrgColumnName.identifier(),
 			OrgColumnDomain.identifier(),
+			OrgColumnParentID.identifier(),
 			countColumn.identifier()).
-			From(orgsTable.identifier() + db.Timetravel(call.Took(ctx))).
+			From(orgsTable.identifier() + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(rows *sql.Rows) (*Orgs, error) {
 			orgs := make([]*Org, 0)
@@ -288,6 +512,7 @@ func prepareOrgsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder
 					&org.Sequence,
 					&org.Name,
 					&org.Domain,
+					&org.ParentID,
 					&count,
 				)
 				if err != nil {
@@ -319,8 +544,9 @@ func prepareOrgQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder,
 			OrgColumnSequence.identifier(),
 			OrgColumnName.identifier(),
 			OrgColumnDomain.identifier(),
+			OrgColumnParentID.identifier(),
 		).
-			From(orgsTable.identifier() + db.Timetravel(call.Took(ctx))).
+			From(orgsTable.identifier() + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*Org, error) {
 			o := new(Org)
@@ -333,6 +559,7 @@ func prepareOrgQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder,
 				&o.Sequence,
 				&o.Name,
 				&o.Domain,
+				&o.ParentID,
 			)
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
@@ -354,12 +581,15 @@ func prepareOrgWithDomainsQuery(ctx context.Context, db prepareDatabase) (sq.Sel
 			OrgColumnSequence.identifier(),
 			OrgColumnName.identifier(),
 			OrgColumnDomain.identifier(),
+			OrgColumnParentID.identifier(),
+			OrgDomainDomainCol.identifier(),
 		).
 			From(orgsTable.identifier()).
-			LeftJoin(join(OrgDomainOrgIDCol, OrgColumnID) + db.Timetravel(call.Took(ctx))).
+			LeftJoin(join(OrgDomainOrgIDCol, OrgColumnID) + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*Org, error) {
 			o := new(Org)
+			var matchedDomain sql.NullString
 			err := row.Scan(
 				&o.ID,
 				&o.CreationDate,
@@ -369,6 +599,8 @@ func prepareOrgWithDomainsQuery(ctx context.Context, db prepareDatabase) (sq.Sel
 				&o.Sequence,
 				&o.Name,
 				&o.Domain,
+				&o.ParentID,
+				&matchedDomain,
 			)
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
@@ -376,14 +608,46 @@ func prepareOrgWithDomainsQuery(ctx context.Context, db prepareDatabase) (sq.Sel
 				}
 				return nil, zerrors.ThrowInternal(err, "QUERY-pWS5H", "Errors.Internal")
 			}
+			o.MatchedDomain = matchedDomain.String
 			return o, nil
 		}
 }
 
+func prepareCountOrgsByStateQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (map[domain_pkg.OrgState]uint64, error)) {
+	return sq.Select(
+			OrgColumnState.identifier(),
+			"COUNT(*)",
+		).
+			From(orgsTable.identifier() + timetravel(ctx, db)).
+			GroupBy(OrgColumnState.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) (map[domain_pkg.OrgState]uint64, error) {
+			counts := map[domain_pkg.OrgState]uint64{
+				domain_pkg.OrgStateActive:   0,
+				domain_pkg.OrgStateInactive: 0,
+			}
+			for rows.Next() {
+				var (
+					state domain_pkg.OrgState
+					count uint64
+				)
+				if err := rows.Scan(&state, &count); err != nil {
+					return nil, err
+				}
+				counts[state] = count
+			}
+
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Rk4cW", "Errors.Query.CloseRows")
+			}
+			return counts, nil
+		}
+}
+
 func prepareOrgUniqueQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Row) (bool, error)) {
 	return sq.Select(uniqueColumn.identifier()).
 			From(orgsTable.identifier()).
-			LeftJoin(join(OrgDomainOrgIDCol, OrgColumnID) + db.Timetravel(call.Took(ctx))).
+			LeftJoin(join(OrgDomainOrgIDCol, OrgColumnID) + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (isUnique bool, err error) {
 			err = row.Scan(&isUnique)
@@ -393,3 +657,48 @@ func prepareOrgUniqueQuery(ctx context.Context, db prepareDatabase) (sq.SelectBu
 			return isUnique, err
 		}
 }
+
+func prepareOrgsCSVQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows, *csv.Writer) error) {
+	return sq.Select(
+			OrgColumnID.identifier(),
+			OrgColumnName.identifier(),
+			OrgColumnDomain.identifier(),
+			OrgColumnState.identifier(),
+			OrgColumnCreationDate.identifier(),
+		).
+			From(orgsTable.identifier() + timetravel(ctx, db)).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows, writer *csv.Writer) error {
+			for rows.Next() {
+				var (
+					id, name, domain string
+					state            domain_pkg.OrgState
+					creationDate     time.Time
+				)
+				if err := rows.Scan(&id, &name, &domain, &state, &creationDate); err != nil {
+					return err
+				}
+				if err := writer.Write([]string{id, name, domain, orgStateCSV(state), creationDate.UTC().Format(time.RFC3339)}); err != nil {
+					return err
+				}
+			}
+
+			if err := rows.Close(); err != nil {
+				return zerrors.ThrowInternal(err, "QUERY-Cs4vC", "Errors.Query.CloseRows")
+			}
+			return nil
+		}
+}
+
+func orgStateCSV(state domain_pkg.OrgState) string {
+	switch state {
+	case domain_pkg.OrgStateActive:
+		return "active"
+	case domain_pkg.OrgStateInactive:
+		return "inactive"
+	case domain_pkg.OrgStateRemoved:
+		return "removed"
+	default:
+		return "unspecified"
+	}
+}
@@ -0,0 +1,154 @@
+package query
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+type QueriesOption func(*Queries)
+
+// WithOrgDomainCache caches the results of [Queries.OrgByPrimaryDomain] and [Queries.OrgByVerifiedDomain] for the given ttl.
+// Not found results are cached for notFoundTTL, they are not cached if notFoundTTL is not positive.
+// The cache of an instance is invalidated after the orgs or org domains projection of this process committed events of the instance,
+// changes projected by other processes are visible after the ttl.
+// The cache is disabled if ttl is not positive.
+func WithOrgDomainCache(ttl, notFoundTTL time.Duration) QueriesOption {
+	return func(q *Queries) {
+		if ttl <= 0 {
+			return
+		}
+		q.orgDomainCache = newOrgDomainCache(ttl, notFoundTTL)
+	}
+}
+
+type orgDomainCacheKey struct {
+	domain   string
+	verified bool
+}
+
+type orgDomainCache struct {
+	ttl         time.Duration
+	notFoundTTL time.Duration
+	now         func() time.Time
+
+	mu sync.Mutex
+	// instances holds the cached orgs per instance id
+	instances map[string]*orgDomainInstanceCache
+}
+
+type orgDomainInstanceCache struct {
+	// generation is increased on each invalidation
+	// so that results queried before the invalidation are not stored
+	generation uint64
+	entries    map[orgDomainCacheKey]*orgDomainCacheEntry
+}
+
+type orgDomainCacheEntry struct {
+	org       *Org
+	err       error
+	expiresAt time.Time
+}
+
+func newOrgDomainCache(ttl, notFoundTTL time.Duration) *orgDomainCache {
+	return &orgDomainCache{
+		ttl:         ttl,
+		notFoundTTL: notFoundTTL,
+		now:         time.Now,
+		instances:   make(map[string]*orgDomainInstanceCache),
+	}
+}
+
+// load returns the cached result for the key or calls query and caches its result.
+func (c *orgDomainCache) load(ctx context.Context, instanceID string, key orgDomainCacheKey, query func(context.Context) (*Org, error)) (*Org, error) {
+	if org, err, ok := c.get(instanceID, key); ok {
+		return org, err
+	}
+	generation := c.generation(instanceID)
+	org, err := query(ctx)
+	c.set(instanceID, generation, key, org, err)
+	return org, err
+}
+
+func (c *orgDomainCache) get(instanceID string, key orgDomainCacheKey) (*Org, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	instance, ok := c.instances[instanceID]
+	if !ok {
+		return nil, nil, false
+	}
+	entry, ok := instance.entries[key]
+	if !ok || c.now().After(entry.expiresAt) {
+		return nil, nil, false
+	}
+	return copyOrg(entry.org), entry.err, true
+}
+
+func (c *orgDomainCache) generation(instanceID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if instance, ok := c.instances[instanceID]; ok {
+		return instance.generation
+	}
+	return 0
+}
+
+func (c *orgDomainCache) set(instanceID string, generation uint64, key orgDomainCacheKey, org *Org, err error) {
+	ttl := c.ttl
+	if err != nil {
+		if !zerrors.IsNotFound(err) || c.notFoundTTL <= 0 {
+			return
+		}
+		ttl = c.notFoundTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	instance, ok := c.instances[instanceID]
+	if !ok {
+		instance = &orgDomainInstanceCache{
+			entries: make(map[orgDomainCacheKey]*orgDomainCacheEntry),
+		}
+		c.instances[instanceID] = instance
+	}
+	if instance.generation != generation {
+		return
+	}
+	instance.entries[key] = &orgDomainCacheEntry{
+		org:       copyOrg(org),
+		err:       err,
+		expiresAt: c.now().Add(ttl),
+	}
+}
+
+// invalidate removes all cached orgs of the instance
+func (c *orgDomainCache) invalidate(_ context.Context, instanceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	instance, ok := c.instances[instanceID]
+	if !ok {
+		// queries started before the invalidation must not be cached
+		c.instances[instanceID] = &orgDomainInstanceCache{
+			generation: 1,
+			entries:    make(map[orgDomainCacheKey]*orgDomainCacheEntry),
+		}
+		return
+	}
+	instance.generation++
+	clear(instance.entries)
+}
+
+// copyOrg prevents callers from modifying cached orgs
+func copyOrg(org *Org) *Org {
+	if org == nil {
+		return nil
+	}
+	copied := *org
+	return &copied
+}
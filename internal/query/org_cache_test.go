@@ -0,0 +1,177 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+type orgDomainCacheQuery struct {
+	org   *Org
+	err   error
+	calls int
+}
+
+func (q *orgDomainCacheQuery) query(context.Context) (*Org, error) {
+	q.calls++
+	return q.org, q.err
+}
+
+func Test_orgDomainCache_load(t *testing.T) {
+	now := time.Now()
+	key := orgDomainCacheKey{domain: "zitadel.ch"}
+	type args struct {
+		query   *orgDomainCacheQuery
+		elapsed time.Duration
+	}
+	type want struct {
+		org   *Org
+		err   func(error) bool
+		calls int
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "hit",
+			args: args{
+				query:   &orgDomainCacheQuery{org: &Org{ID: "org-id"}},
+				elapsed: time.Minute - time.Second,
+			},
+			want: want{
+				org:   &Org{ID: "org-id"},
+				calls: 1,
+			},
+		},
+		{
+			name: "miss after ttl",
+			args: args{
+				query:   &orgDomainCacheQuery{org: &Org{ID: "org-id"}},
+				elapsed: time.Minute + time.Second,
+			},
+			want: want{
+				org:   &Org{ID: "org-id"},
+				calls: 2,
+			},
+		},
+		{
+			name: "not found cached",
+			args: args{
+				query:   &orgDomainCacheQuery{err: zerrors.ThrowNotFound(nil, "QUERY-iTTGJ", "Errors.Org.NotFound")},
+				elapsed: time.Second,
+			},
+			want: want{
+				err:   zerrors.IsNotFound,
+				calls: 1,
+			},
+		},
+		{
+			name: "not found expires before ttl",
+			args: args{
+				query:   &orgDomainCacheQuery{err: zerrors.ThrowNotFound(nil, "QUERY-iTTGJ", "Errors.Org.NotFound")},
+				elapsed: 10 * time.Second,
+			},
+			want: want{
+				err:   zerrors.IsNotFound,
+				calls: 2,
+			},
+		},
+		{
+			name: "internal error not cached",
+			args: args{
+				query:   &orgDomainCacheQuery{err: errors.New("internal")},
+				elapsed: time.Second,
+			},
+			want: want{
+				err: func(err error) bool {
+					return err != nil
+				},
+				calls: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newOrgDomainCache(time.Minute, 5*time.Second)
+			cache.now = func() time.Time { return now }
+
+			_, _ = cache.load(context.Background(), "instance", key, tt.args.query.query)
+			cache.now = func() time.Time { return now.Add(tt.args.elapsed) }
+			org, err := cache.load(context.Background(), "instance", key, tt.args.query.query)
+
+			if tt.want.err != nil {
+				assert.True(t, tt.want.err(err), "unexpected error %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want.org, org)
+			assert.Equal(t, tt.want.calls, tt.args.query.calls)
+		})
+	}
+}
+
+func Test_orgDomainCache_keys(t *testing.T) {
+	cache := newOrgDomainCache(time.Minute, 0)
+	query := &orgDomainCacheQuery{org: &Org{ID: "org-id"}}
+
+	_, _ = cache.load(context.Background(), "instance", orgDomainCacheKey{domain: "zitadel.ch"}, query.query)
+	_, _ = cache.load(context.Background(), "instance", orgDomainCacheKey{domain: "zitadel.ch", verified: true}, query.query)
+	_, _ = cache.load(context.Background(), "other", orgDomainCacheKey{domain: "zitadel.ch"}, query.query)
+	_, _ = cache.load(context.Background(), "instance", orgDomainCacheKey{domain: "zitadel.cloud"}, query.query)
+
+	assert.Equal(t, 4, query.calls)
+}
+
+func Test_orgDomainCache_copy(t *testing.T) {
+	cache := newOrgDomainCache(time.Minute, 0)
+	query := &orgDomainCacheQuery{org: &Org{ID: "org-id", Name: "org"}}
+	key := orgDomainCacheKey{domain: "zitadel.ch"}
+
+	org, _ := cache.load(context.Background(), "instance", key, query.query)
+	org.Name = "changed"
+	org, _ = cache.load(context.Background(), "instance", key, query.query)
+
+	assert.Equal(t, "org", org.Name)
+}
+
+func Test_orgDomainCache_invalidate(t *testing.T) {
+	key := orgDomainCacheKey{domain: "zitadel.ch"}
+
+	t.Run("instance", func(t *testing.T) {
+		cache := newOrgDomainCache(time.Minute, 0)
+		query := &orgDomainCacheQuery{org: &Org{ID: "org-id"}}
+		other := &orgDomainCacheQuery{org: &Org{ID: "other-org-id"}}
+
+		_, _ = cache.load(context.Background(), "instance", key, query.query)
+		_, _ = cache.load(context.Background(), "other", key, other.query)
+		cache.invalidate(context.Background(), "instance")
+		_, _ = cache.load(context.Background(), "instance", key, query.query)
+		_, _ = cache.load(context.Background(), "other", key, other.query)
+
+		assert.Equal(t, 2, query.calls)
+		assert.Equal(t, 1, other.calls)
+	})
+	t.Run("during query", func(t *testing.T) {
+		cache := newOrgDomainCache(time.Minute, 0)
+		calls := 0
+		query := func(context.Context) (*Org, error) {
+			calls++
+			if calls == 1 {
+				cache.invalidate(context.Background(), "instance")
+			}
+			return &Org{ID: "org-id"}, nil
+		}
+
+		_, _ = cache.load(context.Background(), "instance", key, query)
+		_, _ = cache.load(context.Background(), "instance", key, query)
+
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -8,7 +8,6 @@ import (
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
-	"github.com/zitadel/zitadel/internal/api/call"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
@@ -93,7 +92,7 @@ func prepareDomainsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 			OrgDomainIsPrimaryCol.identifier(),
 			OrgDomainValidationTypeCol.identifier(),
 			countColumn.identifier(),
-		).From(orgDomainsTable.identifier() + db.Timetravel(call.Took(ctx))).
+		).From(orgDomainsTable.identifier() + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(rows *sql.Rows) (*Domains, error) {
 			domains := make([]*Domain, 0)
@@ -11,7 +11,6 @@ import (
 	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
-	"github.com/zitadel/zitadel/internal/api/call"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
@@ -150,6 +149,31 @@ func (q *Queries) SearchOrgMetadata(ctx context.Context, shouldTriggerBulk bool,
 	return metadata, err
 }
 
+// GetOrgMetadata returns the metadata of the org as a map of key to value
+func (q *Queries) GetOrgMetadata(ctx context.Context, orgID string) (metadata map[string][]byte, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	query, scan := prepareOrgMetadataMapQuery(ctx, q.client)
+	stmt, args, err := query.Where(sq.Eq{
+		OrgMetadataOrgIDCol.identifier():        orgID,
+		OrgMetadataInstanceIDCol.identifier():   authz.GetInstance(ctx).InstanceID(),
+		OrgMetadataOwnerRemovedCol.identifier(): false,
+	}).ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Vfg2q", "Errors.Query.SQLStatment")
+	}
+
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		metadata, err = scan(rows)
+		return err
+	}, stmt, args...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Vfg2r", "Errors.Internal")
+	}
+	return metadata, nil
+}
+
 func (q *OrgMetadataSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
 	query = q.SearchRequest.toQuery(query)
 	for _, q := range q.Queries {
@@ -184,7 +208,7 @@ func prepareOrgMetadataQuery(ctx context.Context, db prepareDatabase) (sq.Select
 			OrgMetadataKeyCol.identifier(),
 			OrgMetadataValueCol.identifier(),
 		).
-			From(orgMetadataTable.identifier() + db.Timetravel(call.Took(ctx))).
+			From(orgMetadataTable.identifier() + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*OrgMetadata, error) {
 			m := new(OrgMetadata)
@@ -216,7 +240,7 @@ func prepareOrgMetadataListQuery(ctx context.Context, db prepareDatabase) (sq.Se
 			OrgMetadataKeyCol.identifier(),
 			OrgMetadataValueCol.identifier(),
 			countColumn.identifier()).
-			From(orgMetadataTable.identifier() + db.Timetravel(call.Took(ctx))).
+			From(orgMetadataTable.identifier() + timetravel(ctx, db)).
 			PlaceholderFormat(sq.Dollar),
 		func(rows *sql.Rows) (*OrgMetadataList, error) {
 			metadata := make([]*OrgMetadata, 0)
@@ -251,3 +275,30 @@ func prepareOrgMetadataListQuery(ctx context.Context, db prepareDatabase) (sq.Se
 			}, nil
 		}
 }
+
+func prepareOrgMetadataMapQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (map[string][]byte, error)) {
+	return sq.Select(
+			OrgMetadataKeyCol.identifier(),
+			OrgMetadataValueCol.identifier(),
+		).
+			From(orgMetadataTable.identifier() + timetravel(ctx, db)).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) (map[string][]byte, error) {
+			metadata := make(map[string][]byte)
+			for rows.Next() {
+				var (
+					key   string
+					value []byte
+				)
+				if err := rows.Scan(&key, &value); err != nil {
+					return nil, err
+				}
+				metadata[key] = value
+			}
+
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Vfg2s", "Errors.Query.CloseRows")
+			}
+			return metadata, nil
+		}
+}
@@ -46,6 +46,14 @@ var (
 		"value",
 		"count",
 	}
+	orgMetadataMapQuery = `SELECT projections.org_metadata2.key,` +
+		` projections.org_metadata2.value` +
+		` FROM projections.org_metadata2` +
+		` AS OF SYSTEM TIME '-1 ms'`
+	orgMetadataMapCols = []string{
+		"key",
+		"value",
+	}
 )
 
 func Test_OrgMetadataPrepares(t *testing.T) {
@@ -241,6 +249,59 @@ func Test_OrgMetadataPrepares(t *testing.T) {
 			},
 			object: (*OrgMetadataList)(nil),
 		},
+		{
+			name:    "prepareOrgMetadataMapQuery no result",
+			prepare: prepareOrgMetadataMapQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(orgMetadataMapQuery),
+					nil,
+					nil,
+				),
+			},
+			object: map[string][]byte{},
+		},
+		{
+			name:    "prepareOrgMetadataMapQuery multiple results",
+			prepare: prepareOrgMetadataMapQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(orgMetadataMapQuery),
+					orgMetadataMapCols,
+					[][]driver.Value{
+						{
+							"key",
+							[]byte("value"),
+						},
+						{
+							"key2",
+							[]byte("value2"),
+						},
+					},
+				),
+			},
+			object: map[string][]byte{
+				"key":  []byte("value"),
+				"key2": []byte("value2"),
+			},
+		},
+		{
+			name:    "prepareOrgMetadataMapQuery sql err",
+			prepare: prepareOrgMetadataMapQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(orgMetadataMapQuery),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (map[string][]byte)(nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
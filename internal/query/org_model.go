@@ -0,0 +1,83 @@
+package query
+
+import (
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+// OrgReadModel reduces an organization from its events,
+// which also covers removed organizations which are deleted from the projection
+type OrgReadModel struct {
+	eventstore.ReadModel
+
+	Name     string
+	Domain   string
+	ParentID string
+	State    domain.OrgState
+}
+
+func NewOrgReadModel(orgID, instanceID string) *OrgReadModel {
+	return &OrgReadModel{
+		ReadModel: eventstore.ReadModel{
+			AggregateID: orgID,
+			InstanceID:  instanceID,
+		},
+	}
+}
+
+func (m *OrgReadModel) Reduce() error {
+	for _, event := range m.Events {
+		switch e := event.(type) {
+		case *org.OrgAddedEvent:
+			m.Name = e.Name
+			m.State = domain.OrgStateActive
+		case *org.OrgChangedEvent:
+			m.Name = e.Name
+		case *org.OrgDeactivatedEvent:
+			m.State = domain.OrgStateInactive
+		case *org.OrgReactivatedEvent:
+			m.State = domain.OrgStateActive
+		case *org.DomainPrimarySetEvent:
+			m.Domain = e.Domain
+		case *org.OrgParentSetEvent:
+			m.ParentID = e.ParentID
+		case *org.OrgRemovedEvent:
+			m.State = domain.OrgStateRemoved
+		}
+	}
+
+	return m.ReadModel.Reduce()
+}
+
+func (m *OrgReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(m.InstanceID).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(m.AggregateID).
+		EventTypes(
+			org.OrgAddedEventType,
+			org.OrgChangedEventType,
+			org.OrgDeactivatedEventType,
+			org.OrgReactivatedEventType,
+			org.OrgDomainPrimarySetEventType,
+			org.OrgParentSetEventType,
+			org.OrgRemovedEventType,
+		).
+		Builder()
+}
+
+func (m *OrgReadModel) org() *Org {
+	return &Org{
+		ID:            m.AggregateID,
+		CreationDate:  m.CreationDate,
+		ChangeDate:    m.ChangeDate,
+		ResourceOwner: m.ResourceOwner,
+		State:         m.State,
+		Sequence:      m.ProcessedSequence,
+		Name:          m.Name,
+		Domain:        m.Domain,
+		ParentID:      m.ParentID,
+	}
+}
@@ -6,19 +6,31 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/database"
 	db_mock "github.com/zitadel/zitadel/internal/database/mock"
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/eventstore/repository/mock"
+	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
-	orgUniqueQuery = "SELECT COUNT(*) = 0 FROM projections.orgs1 LEFT JOIN projections.org_domains2 ON projections.orgs1.id = projections.org_domains2.org_id AND projections.orgs1.instance_id = projections.org_domains2.instance_id AS OF SYSTEM TIME '-1 ms' WHERE (projections.org_domains2.is_verified = $1 AND projections.orgs1.instance_id = $2 AND (projections.org_domains2.domain ILIKE $3 OR projections.orgs1.name ILIKE $4) AND projections.orgs1.org_state <> $5)"
+	orgUniqueQuery = "SELECT COUNT(*) = 0 FROM projections.orgs1 LEFT JOIN projections.org_domains2 ON projections.orgs1.id = projections.org_domains2.org_id AND projections.orgs1.instance_id = projections.org_domains2.instance_id AS OF SYSTEM TIME '-1 ms' WHERE (projections.org_domains2.is_verified = $1 AND projections.orgs1.instance_id = $2 AND (projections.org_domains2.domain ILIKE $3 OR projections.orgs1.normalized_name = $4) AND projections.orgs1.org_state <> $5)"
 	orgUniqueCols  = []string{"is_unique"}
 
 	prepareOrgsQueryStmt = `SELECT projections.orgs1.id,` +
@@ -29,6 +41,7 @@ var (
 		` projections.orgs1.sequence,` +
 		` projections.orgs1.name,` +
 		` projections.orgs1.primary_domain,` +
+		` projections.orgs1.parent_id,` +
 		` COUNT(*) OVER ()` +
 		` FROM projections.orgs1` +
 		` AS OF SYSTEM TIME '-1 ms' `
@@ -41,6 +54,7 @@ var (
 		"sequence",
 		"name",
 		"primary_domain",
+		"parent_id",
 		"count",
 	}
 
@@ -51,7 +65,8 @@ var (
 		` projections.orgs1.org_state,` +
 		` projections.orgs1.sequence,` +
 		` projections.orgs1.name,` +
-		` projections.orgs1.primary_domain` +
+		` projections.orgs1.primary_domain,` +
+		` projections.orgs1.parent_id` +
 		` FROM projections.orgs1` +
 		` AS OF SYSTEM TIME '-1 ms' `
 	prepareOrgQueryCols = []string{
@@ -63,6 +78,33 @@ var (
 		"sequence",
 		"name",
 		"primary_domain",
+		"parent_id",
+	}
+
+	prepareOrgWithDomainsQueryStmt = `SELECT projections.orgs1.id,` +
+		` projections.orgs1.creation_date,` +
+		` projections.orgs1.change_date,` +
+		` projections.orgs1.resource_owner,` +
+		` projections.orgs1.org_state,` +
+		` projections.orgs1.sequence,` +
+		` projections.orgs1.name,` +
+		` projections.orgs1.primary_domain,` +
+		` projections.orgs1.parent_id,` +
+		` projections.org_domains2.domain` +
+		` FROM projections.orgs1` +
+		` LEFT JOIN projections.org_domains2 ON projections.orgs1.id = projections.org_domains2.org_id AND projections.orgs1.instance_id = projections.org_domains2.instance_id` +
+		` AS OF SYSTEM TIME '-1 ms' `
+	prepareOrgWithDomainsQueryCols = []string{
+		"id",
+		"creation_date",
+		"change_date",
+		"resource_owner",
+		"org_state",
+		"sequence",
+		"name",
+		"primary_domain",
+		"parent_id",
+		"domain",
 	}
 
 	prepareOrgUniqueStmt = `SELECT COUNT(*) = 0` +
@@ -114,6 +156,7 @@ func Test_OrgPrepares(t *testing.T) {
 							uint64(20211109),
 							"org-name",
 							"zitadel.ch",
+							"",
 						},
 					},
 				),
@@ -153,6 +196,7 @@ func Test_OrgPrepares(t *testing.T) {
 							uint64(20211108),
 							"org-name-1",
 							"zitadel.ch",
+							"",
 						},
 						{
 							"id-2",
@@ -163,6 +207,7 @@ func Test_OrgPrepares(t *testing.T) {
 							uint64(20211108),
 							"org-name-2",
 							"caos.ch",
+							"id-1",
 						},
 					},
 				),
@@ -191,10 +236,157 @@ func Test_OrgPrepares(t *testing.T) {
 						Sequence:      20211108,
 						Name:          "org-name-2",
 						Domain:        "caos.ch",
+						ParentID:      "id-1",
+					},
+				},
+			},
+		},
+		{
+			name: "prepareOrgsQuery children of parent",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Orgs, error)) {
+				query, scan := prepareOrgsQuery(ctx, db)
+				parentQuery, err := NewOrgParentSearchQuery("id-1")
+				if err != nil {
+					panic(err)
+				}
+				return parentQuery.toQuery(query), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareOrgsQueryStmt+"WHERE projections.orgs1.parent_id = $1"),
+					prepareOrgsQueryCols,
+					[][]driver.Value{
+						{
+							"id-2",
+							testNow,
+							testNow,
+							"ro",
+							domain.OrgStateActive,
+							uint64(20211108),
+							"org-name-2",
+							"caos.ch",
+							"id-1",
+						},
+						{
+							"id-3",
+							testNow,
+							testNow,
+							"ro",
+							domain.OrgStateActive,
+							uint64(20211108),
+							"org-name-3",
+							"zitadel.com",
+							"id-1",
+						},
+					},
+					"id-1",
+				),
+			},
+			object: &Orgs{
+				SearchResponse: SearchResponse{
+					Count: 2,
+				},
+				Orgs: []*Org{
+					{
+						ID:            "id-2",
+						CreationDate:  testNow,
+						ChangeDate:    testNow,
+						ResourceOwner: "ro",
+						State:         domain.OrgStateActive,
+						Sequence:      20211108,
+						Name:          "org-name-2",
+						Domain:        "caos.ch",
+						ParentID:      "id-1",
+					},
+					{
+						ID:            "id-3",
+						CreationDate:  testNow,
+						ChangeDate:    testNow,
+						ResourceOwner: "ro",
+						State:         domain.OrgStateActive,
+						Sequence:      20211108,
+						Name:          "org-name-3",
+						Domain:        "zitadel.com",
+						ParentID:      "id-1",
+					},
+				},
+			},
+		},
+		{
+			name: "prepareOrgsQuery name and creation date lower bound",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Orgs, error)) {
+				query, scan := prepareOrgsQuery(ctx, db)
+				nameQuery, err := NewOrgNameSearchQuery(TextEquals, "org-name")
+				if err != nil {
+					panic(err)
+				}
+				creationDateQuery, err := NewOrgCreationDateQuery(TimestampGreaterOrEquals, testNow)
+				if err != nil {
+					panic(err)
+				}
+				queries := &OrgSearchQueries{Queries: []SearchQuery{nameQuery, creationDateQuery}}
+				return queries.toQuery(query), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareOrgsQueryStmt+"WHERE projections.orgs1.name = $1 AND projections.orgs1.creation_date >= $2"),
+					prepareOrgsQueryCols,
+					[][]driver.Value{
+						{
+							"id",
+							testNow,
+							testNow,
+							"ro",
+							domain.OrgStateActive,
+							uint64(20211109),
+							"org-name",
+							"zitadel.ch",
+							"",
+						},
+					},
+					"org-name",
+					testNow,
+				),
+			},
+			object: &Orgs{
+				SearchResponse: SearchResponse{
+					Count: 1,
+				},
+				Orgs: []*Org{
+					{
+						ID:            "id",
+						CreationDate:  testNow,
+						ChangeDate:    testNow,
+						ResourceOwner: "ro",
+						State:         domain.OrgStateActive,
+						Sequence:      20211109,
+						Name:          "org-name",
+						Domain:        "zitadel.ch",
 					},
 				},
 			},
 		},
+		{
+			name: "prepareOrgsQuery change date upper bound",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Orgs, error)) {
+				query, scan := prepareOrgsQuery(ctx, db)
+				changeDateQuery, err := NewOrgChangeDateQuery(TimestampLess, testNow)
+				if err != nil {
+					panic(err)
+				}
+				queries := &OrgSearchQueries{Queries: []SearchQuery{changeDateQuery}}
+				return queries.toQuery(query), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareOrgsQueryStmt+"WHERE projections.orgs1.change_date < $1"),
+					prepareOrgsQueryCols,
+					nil,
+					testNow,
+				),
+			},
+			object: &Orgs{Orgs: []*Org{}},
+		},
 		{
 			name:    "prepareOrgsQuery sql err",
 			prepare: prepareOrgsQuery,
@@ -246,6 +438,7 @@ func Test_OrgPrepares(t *testing.T) {
 						uint64(20211108),
 						"org-name",
 						"zitadel.ch",
+						"parent-id",
 					},
 				),
 			},
@@ -258,6 +451,7 @@ func Test_OrgPrepares(t *testing.T) {
 				Sequence:      20211108,
 				Name:          "org-name",
 				Domain:        "zitadel.ch",
+				ParentID:      "parent-id",
 			},
 		},
 		{
@@ -277,6 +471,75 @@ func Test_OrgPrepares(t *testing.T) {
 			},
 			object: (*Org)(nil),
 		},
+		{
+			name:    "prepareOrgWithDomainsQuery no result",
+			prepare: prepareOrgWithDomainsQuery,
+			want: want{
+				sqlExpectations: mockQueriesScanErr(
+					regexp.QuoteMeta(prepareOrgWithDomainsQueryStmt),
+					nil,
+					nil,
+				),
+				err: func(err error) (error, bool) {
+					if !zerrors.IsNotFound(err) {
+						return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*Org)(nil),
+		},
+		{
+			name:    "prepareOrgWithDomainsQuery found, matched domain differs from primary",
+			prepare: prepareOrgWithDomainsQuery,
+			want: want{
+				sqlExpectations: mockQuery(
+					regexp.QuoteMeta(prepareOrgWithDomainsQueryStmt),
+					prepareOrgWithDomainsQueryCols,
+					[]driver.Value{
+						"id",
+						testNow,
+						testNow,
+						"ro",
+						domain.OrgStateActive,
+						uint64(20211108),
+						"org-name",
+						"zitadel.ch",
+						"parent-id",
+						"zitadel.cloud",
+					},
+				),
+			},
+			object: &Org{
+				ID:            "id",
+				CreationDate:  testNow,
+				ChangeDate:    testNow,
+				ResourceOwner: "ro",
+				State:         domain.OrgStateActive,
+				Sequence:      20211108,
+				Name:          "org-name",
+				Domain:        "zitadel.ch",
+				ParentID:      "parent-id",
+				MatchedDomain: "zitadel.cloud",
+			},
+		},
+		{
+			name:    "prepareOrgWithDomainsQuery sql err",
+			prepare: prepareOrgWithDomainsQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(prepareOrgWithDomainsQueryStmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*Org)(nil),
+		},
 		{
 			name:    "prepareOrgUniqueQuery no result",
 			prepare: prepareOrgUniqueQuery,
@@ -334,6 +597,56 @@ func Test_OrgPrepares(t *testing.T) {
 	}
 }
 
+func Test_OrgPrepares_timetravel(t *testing.T) {
+	prepares := map[string]func(context.Context, prepareDatabase) sq.SelectBuilder{
+		"prepareOrgsQuery": func(ctx context.Context, db prepareDatabase) sq.SelectBuilder {
+			query, _ := prepareOrgsQuery(ctx, db)
+			return query
+		},
+		"prepareOrgQuery": func(ctx context.Context, db prepareDatabase) sq.SelectBuilder {
+			query, _ := prepareOrgQuery(ctx, db)
+			return query
+		},
+		"prepareOrgWithDomainsQuery": func(ctx context.Context, db prepareDatabase) sq.SelectBuilder {
+			query, _ := prepareOrgWithDomainsQuery(ctx, db)
+			return query
+		},
+		"prepareCountOrgsByStateQuery": func(ctx context.Context, db prepareDatabase) sq.SelectBuilder {
+			query, _ := prepareCountOrgsByStateQuery(ctx, db)
+			return query
+		},
+		"prepareOrgUniqueQuery": func(ctx context.Context, db prepareDatabase) sq.SelectBuilder {
+			query, _ := prepareOrgUniqueQuery(ctx, db)
+			return query
+		},
+	}
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		wantTimetravel bool
+	}{
+		{
+			name:           "default",
+			ctx:            context.Background(),
+			wantTimetravel: true,
+		},
+		{
+			name:           "strong read",
+			ctx:            WithStrongRead(context.Background()),
+			wantTimetravel: false,
+		},
+	}
+	for _, tt := range tests {
+		for name, prepare := range prepares {
+			t.Run(tt.name+" "+name, func(t *testing.T) {
+				stmt, _, err := prepare(tt.ctx, new(prepareDB)).ToSql()
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantTimetravel, strings.Contains(stmt, asOfSystemTime))
+			})
+		}
+	}
+}
+
 func TestQueries_IsOrgUnique(t *testing.T) {
 	type args struct {
 		name   string
@@ -382,6 +695,28 @@ func TestQueries_IsOrgUnique(t *testing.T) {
 				sqlExpectations: mockQueries(orgUniqueQuery, orgUniqueCols, [][]driver.Value{{false}}, true, "", "exists", "exists", domain.OrgStateRemoved),
 			},
 		},
+		{
+			name: "existing name with other whitespace",
+			args: args{
+				domain: "",
+				name:   " Acme   Inc ",
+			},
+			want: want{
+				isUnique:        false,
+				sqlExpectations: mockQueries(orgUniqueQuery, orgUniqueCols, [][]driver.Value{{false}}, true, "", "", "acme inc", domain.OrgStateRemoved),
+			},
+		},
+		{
+			name: "existing name with accents",
+			args: args{
+				domain: "",
+				name:   "Zürich Äpfel",
+			},
+			want: want{
+				isUnique:        false,
+				sqlExpectations: mockQueries(orgUniqueQuery, orgUniqueCols, [][]driver.Value{{false}}, true, "", "", "zurich apfel", domain.OrgStateRemoved),
+			},
+		},
 		{
 			name: "not existing",
 			args: args{
@@ -441,3 +776,490 @@ func TestQueries_IsOrgUnique(t *testing.T) {
 
 	}
 }
+
+func TestQueries_CountOrgsByState(t *testing.T) {
+	countOrgsByStateQuery := `SELECT projections.orgs1.org_state, COUNT(*)` +
+		` FROM projections.orgs1 AS OF SYSTEM TIME '-1 ms'` +
+		` WHERE projections.orgs1.instance_id = $1` +
+		` GROUP BY projections.orgs1.org_state`
+	countOrgsByStateCols := []string{"org_state", "orgs"}
+
+	type want struct {
+		err             func(error) bool
+		sqlExpectations sqlExpectation
+		counts          map[domain.OrgState]uint64
+	}
+	tests := []struct {
+		name string
+		want want
+	}{
+		{
+			name: "mixed states",
+			want: want{
+				sqlExpectations: mockQueries(countOrgsByStateQuery, countOrgsByStateCols,
+					[][]driver.Value{
+						{domain.OrgStateActive, uint64(3)},
+						{domain.OrgStateInactive, uint64(2)},
+					},
+					"",
+				),
+				counts: map[domain.OrgState]uint64{
+					domain.OrgStateActive:   3,
+					domain.OrgStateInactive: 2,
+				},
+			},
+		},
+		{
+			name: "missing states are zero",
+			want: want{
+				sqlExpectations: mockQueries(countOrgsByStateQuery, countOrgsByStateCols,
+					[][]driver.Value{
+						{domain.OrgStateActive, uint64(1)},
+					},
+					"",
+				),
+				counts: map[domain.OrgState]uint64{
+					domain.OrgStateActive:   1,
+					domain.OrgStateInactive: 0,
+				},
+			},
+		},
+		{
+			name: "no orgs",
+			want: want{
+				sqlExpectations: mockQueries(countOrgsByStateQuery, countOrgsByStateCols, nil, ""),
+				counts: map[domain.OrgState]uint64{
+					domain.OrgStateActive:   0,
+					domain.OrgStateInactive: 0,
+				},
+			},
+		},
+		{
+			name: "sql err",
+			want: want{
+				sqlExpectations: mockQueryErr(countOrgsByStateQuery, sql.ErrConnDone, ""),
+				err:             zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		client, mock, err := sqlmock.New(
+			sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+			sqlmock.ValueConverterOption(new(db_mock.TypeConverter)),
+		)
+		if err != nil {
+			t.Fatalf("unable to mock db: %v", err)
+		}
+		tt.want.sqlExpectations(mock)
+
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+
+			counts, err := q.CountOrgsByState(context.Background())
+			if (tt.want.err == nil && err != nil) || (err != nil && tt.want.err != nil && !tt.want.err(err)) {
+				t.Errorf("Queries.CountOrgsByState() unexpected error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(counts, tt.want.counts) {
+				t.Errorf("Queries.CountOrgsByState() = %v, want %v", counts, tt.want.counts)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("expectation was met: %v", err)
+			}
+		})
+	}
+}
+
+func TestQueries_ExportOrgsCSV(t *testing.T) {
+	exportOrgsCSVStmt := `SELECT projections.orgs1.id,` +
+		` projections.orgs1.name,` +
+		` projections.orgs1.primary_domain,` +
+		` projections.orgs1.org_state,` +
+		` projections.orgs1.creation_date` +
+		` FROM projections.orgs1 AS OF SYSTEM TIME '-1 ms'`
+	exportOrgsCSVCols := []string{"id", "name", "primary_domain", "org_state", "creation_date"}
+	creationDate := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	nameQuery, err := NewOrgNameSearchQuery(TextEquals, "org-1")
+	require.NoError(t, err)
+
+	type want struct {
+		err             func(error) bool
+		sqlExpectations sqlExpectation
+		csv             string
+	}
+	tests := []struct {
+		name    string
+		queries *OrgSearchQueries
+		want    want
+	}{
+		{
+			name:    "no orgs",
+			queries: &OrgSearchQueries{},
+			want: want{
+				sqlExpectations: mockQueries(exportOrgsCSVStmt+` WHERE projections.orgs1.instance_id = $1`, exportOrgsCSVCols, nil, ""),
+				csv:             "id,name,domain,state,creation_date\n",
+			},
+		},
+		{
+			name:    "multiple orgs",
+			queries: &OrgSearchQueries{},
+			want: want{
+				sqlExpectations: mockQueries(exportOrgsCSVStmt+` WHERE projections.orgs1.instance_id = $1`, exportOrgsCSVCols,
+					[][]driver.Value{
+						{"id-1", "org-1", "org-1.zitadel.ch", domain.OrgStateActive, creationDate},
+						{"id-2", "org, 2", "org-2.zitadel.ch", domain.OrgStateInactive, creationDate},
+						{"id-3", "org-3", "org-3.zitadel.ch", domain.OrgStateActive, creationDate},
+					},
+					"",
+				),
+				csv: "id,name,domain,state,creation_date\n" +
+					"id-1,org-1,org-1.zitadel.ch,active,2024-01-02T03:04:05Z\n" +
+					"id-2,\"org, 2\",org-2.zitadel.ch,inactive,2024-01-02T03:04:05Z\n" +
+					"id-3,org-3,org-3.zitadel.ch,active,2024-01-02T03:04:05Z\n",
+			},
+		},
+		{
+			name: "filtered",
+			queries: &OrgSearchQueries{
+				Queries: []SearchQuery{nameQuery},
+			},
+			want: want{
+				sqlExpectations: mockQueries(exportOrgsCSVStmt+` WHERE projections.orgs1.name = $1 AND projections.orgs1.instance_id = $2`, exportOrgsCSVCols,
+					[][]driver.Value{
+						{"id-1", "org-1", "org-1.zitadel.ch", domain.OrgStateActive, creationDate},
+					},
+					"org-1", "",
+				),
+				csv: "id,name,domain,state,creation_date\n" +
+					"id-1,org-1,org-1.zitadel.ch,active,2024-01-02T03:04:05Z\n",
+			},
+		},
+		{
+			name:    "sql err",
+			queries: &OrgSearchQueries{},
+			want: want{
+				sqlExpectations: mockQueryErr(exportOrgsCSVStmt+` WHERE projections.orgs1.instance_id = $1`, sql.ErrConnDone, ""),
+				err:             zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(
+				sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+				sqlmock.ValueConverterOption(new(db_mock.TypeConverter)),
+			)
+			require.NoError(t, err)
+			tt.want.sqlExpectations(mock)
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+
+			var buf strings.Builder
+			err = q.ExportOrgsCSV(context.Background(), tt.queries, &buf)
+			if tt.want.err != nil {
+				assert.True(t, tt.want.err(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.csv, buf.String())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueries_OrgByIDIncludeRemoved(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	orgByIDStmt := regexp.QuoteMeta(prepareOrgQueryStmt +
+		`WHERE projections.orgs1.id = $1 AND projections.orgs1.instance_id = $2`)
+	agg := org.NewAggregate("org-id")
+	agg.InstanceID = "instance"
+	agg.ResourceOwner = "org-id"
+	history := func(commands ...eventstore.Command) []eventstore.Event {
+		events := make([]eventstore.Event, len(commands))
+		for i, command := range commands {
+			event := eventFromEventPusher(command)
+			event.Seq = uint64(i + 1)
+			event.CreationDate = testNow.Add(time.Duration(i) * time.Minute)
+			events[i] = event
+		}
+		return events
+	}
+	removed := history(
+		org.NewOrgAddedEvent(ctx, &agg.Aggregate, "org"),
+		org.NewDomainPrimarySetEvent(ctx, &agg.Aggregate, "org.zitadel.ch"),
+		org.NewOrgParentSetEvent(ctx, &agg.Aggregate, "parent-id"),
+		org.NewOrgDeactivatedEvent(ctx, &agg.Aggregate),
+		org.NewOrgRemovedEvent(ctx, &agg.Aggregate, "org", []string{}, false, []string{}, []*domain.UserIDPLink{}, []string{}),
+	)
+
+	type want struct {
+		org *Org
+		err func(error) bool
+	}
+	tests := []struct {
+		name            string
+		includeRemoved  bool
+		sqlExpectations sqlExpectation
+		eventstore      func(*testing.T) *eventstore.Eventstore
+		want            want
+	}{
+		{
+			name:            "removed org not found by default",
+			sqlExpectations: mockQueryScanErr(orgByIDStmt, prepareOrgQueryCols, nil, "org-id", "instance"),
+			eventstore:      expectEventstore(),
+			want: want{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			name:            "removed org included",
+			includeRemoved:  true,
+			sqlExpectations: mockQueryScanErr(orgByIDStmt, prepareOrgQueryCols, nil, "org-id", "instance"),
+			eventstore:      expectEventstore(expectFilter(removed...)),
+			want: want{
+				org: &Org{
+					ID:            "org-id",
+					CreationDate:  testNow,
+					ChangeDate:    testNow.Add(4 * time.Minute),
+					ResourceOwner: "org-id",
+					State:         domain.OrgStateRemoved,
+					Sequence:      5,
+					Name:          "org",
+					Domain:        "org.zitadel.ch",
+					ParentID:      "parent-id",
+				},
+			},
+		},
+		{
+			name:            "existing org from projection",
+			includeRemoved:  true,
+			sqlExpectations: mockQuery(orgByIDStmt, prepareOrgQueryCols, []driver.Value{"org-id", testNow, testNow, "org-id", domain.OrgStateActive, uint64(1), "org", "org.zitadel.ch", ""}, "org-id", "instance"),
+			eventstore:      expectEventstore(),
+			want: want{
+				org: &Org{
+					ID:            "org-id",
+					CreationDate:  testNow,
+					ChangeDate:    testNow,
+					ResourceOwner: "org-id",
+					State:         domain.OrgStateActive,
+					Sequence:      1,
+					Name:          "org",
+					Domain:        "org.zitadel.ch",
+				},
+			},
+		},
+		{
+			name:            "not existing org included",
+			includeRemoved:  true,
+			sqlExpectations: mockQueryScanErr(orgByIDStmt, prepareOrgQueryCols, nil, "org-id", "instance"),
+			eventstore:      expectEventstore(expectFilter()),
+			want: want{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			name:            "org not yet projected included",
+			includeRemoved:  true,
+			sqlExpectations: mockQueryScanErr(orgByIDStmt, prepareOrgQueryCols, nil, "org-id", "instance"),
+			eventstore:      expectEventstore(expectFilter(removed[:2]...)),
+			want: want{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			name:            "filter error",
+			includeRemoved:  true,
+			sqlExpectations: mockQueryScanErr(orgByIDStmt, prepareOrgQueryCols, nil, "org-id", "instance"),
+			eventstore:      expectEventstore(expectFilterError(zerrors.ThrowInternal(nil, "ID", "error"))),
+			want: want{
+				err: zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			tt.sqlExpectations(mock)
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+				eventstore: tt.eventstore(t),
+			}
+
+			var got *Org
+			if tt.includeRemoved {
+				got, err = q.OrgByIDIncludeRemoved(ctx, false, "org-id")
+			} else {
+				got, err = q.OrgByID(ctx, false, "org-id")
+			}
+			if tt.want.err != nil {
+				assert.True(t, tt.want.err(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want.org, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueries_OrgChanges(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	agg := org.NewAggregate("org-id")
+	change := func(command eventstore.Command, sequence uint64, editor string) *repository.Event {
+		event := eventFromEventPusher(command)
+		event.Seq = sequence
+		event.CreationDate = testNow.Add(time.Duration(sequence) * time.Minute)
+		event.EditorUser = editor
+		return event
+	}
+	// the querier returns the events in descending order
+	history := []eventstore.Event{
+		change(org.NewOrgDeactivatedEvent(ctx, &agg.Aggregate), 3, "admin"),
+		change(org.NewOrgChangedEvent(ctx, &agg.Aggregate, "org", "renamed org"), 2, "owner"),
+		change(org.NewOrgAddedEvent(ctx, &agg.Aggregate, "org"), 1, "creator"),
+	}
+
+	var gotQuery *eventstore.SearchQueryBuilder
+	expectHistory := func(events ...eventstore.Event) expect {
+		return func(m *mock.MockRepository) {
+			m.MockQuerier.EXPECT().FilterToReducer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, query *eventstore.SearchQueryBuilder, reduce eventstore.Reducer) error {
+					gotQuery = query
+					for _, event := range events {
+						if err := reduce(event); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		orgID      string
+		limit      uint64
+		eventstore func(*testing.T) *eventstore.Eventstore
+		want       []*OrgChange
+		wantErr    func(error) bool
+	}{
+		{
+			name:       "missing id",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:       "all changes",
+			orgID:      "org-id",
+			eventstore: expectEventstore(expectHistory(history...)),
+			want: []*OrgChange{
+				{Sequence: 3, CreationDate: testNow.Add(3 * time.Minute), EventType: org.OrgDeactivatedEventType, Editor: "admin"},
+				{Sequence: 2, CreationDate: testNow.Add(2 * time.Minute), EventType: org.OrgChangedEventType, Editor: "owner", Name: "renamed org"},
+				{Sequence: 1, CreationDate: testNow.Add(1 * time.Minute), EventType: org.OrgAddedEventType, Editor: "creator", Name: "org"},
+			},
+		},
+		{
+			name:       "limited",
+			orgID:      "org-id",
+			limit:      2,
+			eventstore: expectEventstore(expectHistory(history[:2]...)),
+			want: []*OrgChange{
+				{Sequence: 3, CreationDate: testNow.Add(3 * time.Minute), EventType: org.OrgDeactivatedEventType, Editor: "admin"},
+				{Sequence: 2, CreationDate: testNow.Add(2 * time.Minute), EventType: org.OrgChangedEventType, Editor: "owner", Name: "renamed org"},
+			},
+		},
+		{
+			name:       "no changes",
+			orgID:      "org-id",
+			eventstore: expectEventstore(expectHistory()),
+			want:       []*OrgChange{},
+		},
+		{
+			name:       "filter error",
+			orgID:      "org-id",
+			eventstore: expectEventstore(expectFilterError(zerrors.ThrowInternal(nil, "ID", "error"))),
+			wantErr:    zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery = nil
+			q := &Queries{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := q.OrgChanges(ctx, tt.orgID, tt.limit)
+			if tt.wantErr != nil {
+				require.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			require.NotNil(t, gotQuery)
+			assert.True(t, gotQuery.GetDesc(), "changes must be ordered descending")
+			assert.Equal(t, tt.limit, gotQuery.GetLimit())
+		})
+	}
+}
+
+func TestNewOrgNameOrPreviousNameSearchQuery(t *testing.T) {
+	renamedAfter := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		method   TextComparison
+		value    string
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "equals",
+			method:   TextEquals,
+			value:    "old name",
+			wantSQL:  "SELECT x FROM t WHERE (projections.orgs1.name = ? OR (projections.orgs1.previous_name = ? AND projections.orgs1.previous_name_change_date > ?))",
+			wantArgs: []interface{}{"old name", "old name", renamedAfter},
+		},
+		{
+			name:     "starts with ignore case",
+			method:   TextStartsWithIgnoreCase,
+			value:    "old",
+			wantSQL:  "SELECT x FROM t WHERE (projections.orgs1.name ILIKE ? OR (projections.orgs1.previous_name ILIKE ? AND projections.orgs1.previous_name_change_date > ?))",
+			wantArgs: []interface{}{"old%", "old%", renamedAfter},
+		},
+		{
+			name:    "invalid method",
+			method:  -1,
+			value:   "old name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewOrgNameOrPreviousNameSearchQuery(tt.method, tt.value, renamedAfter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			stmt, args, err := query.toQuery(sq.Select("x").From("t")).ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, stmt)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
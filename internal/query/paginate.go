@@ -0,0 +1,61 @@
+package query
+
+import (
+	"context"
+)
+
+// PageFetcher returns the items of the page starting at offset
+// and the total count of items matching the search.
+type PageFetcher[T any] func(offset, limit uint64) (items []T, count uint64, err error)
+
+// Paginate walks a search result page by page using the offset and limit of [SearchRequest].
+// The returned function has the signature of iter.Seq2[T, error].
+// Iteration stops after the first error or as soon as all items are yielded.
+//
+// Pages are only consistent if the search is sorted by a unique column.
+func Paginate[T any](ctx context.Context, pageSize uint64, fetch PageFetcher[T]) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for offset := uint64(0); ; {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, count, err := fetch(offset, pageSize)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			offset += uint64(len(items))
+			if len(items) == 0 || offset >= count {
+				return
+			}
+		}
+	}
+}
+
+// PaginateOrgs yields all organizations matching the queries
+// by calling [Queries.SearchOrgs] with pages of pageSize.
+// The offset and limit of queries are ignored.
+// Orgs are sorted by id if no sorting column is set.
+func (q *Queries) PaginateOrgs(ctx context.Context, queries *OrgSearchQueries, pageSize uint64) func(yield func(*Org, error) bool) {
+	return Paginate(ctx, pageSize, func(offset, limit uint64) ([]*Org, uint64, error) {
+		page := *queries
+		page.Offset = offset
+		page.Limit = limit
+		if page.SortingColumn.isZero() {
+			page.SortingColumn = OrgColumnID
+			page.Asc = true
+		}
+		orgs, err := q.SearchOrgs(ctx, &page)
+		if err != nil {
+			return nil, 0, err
+		}
+		return orgs.Orgs, orgs.Count, nil
+	})
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func collect[T any](seq func(yield func(T, error) bool)) (items []T, err error) {
+	seq(func(item T, itemErr error) bool {
+		if itemErr != nil {
+			err = itemErr
+			return false
+		}
+		items = append(items, item)
+		return true
+	})
+	return items, err
+}
+
+func TestPaginate(t *testing.T) {
+	dataset := []int{1, 2, 3, 4, 5, 6, 7}
+	errFetch := errors.New("fetch failed")
+
+	type fetched struct {
+		offset, limit uint64
+	}
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		failAfter   int
+		stopAfter   int
+		wantItems   []int
+		wantFetched []fetched
+		wantErr     error
+	}{
+		{
+			name:        "all pages",
+			ctx:         context.Background(),
+			wantItems:   dataset,
+			wantFetched: []fetched{{0, 3}, {3, 3}, {6, 3}},
+		},
+		{
+			name:        "stop early",
+			ctx:         context.Background(),
+			stopAfter:   4,
+			wantItems:   []int{1, 2, 3, 4},
+			wantFetched: []fetched{{0, 3}, {3, 3}},
+		},
+		{
+			name:        "error on second page",
+			ctx:         context.Background(),
+			failAfter:   1,
+			wantItems:   []int{1, 2, 3},
+			wantFetched: []fetched{{0, 3}, {3, 3}},
+			wantErr:     errFetch,
+		},
+		{
+			name: "context cancelled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			}(),
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFetched []fetched
+			seq := Paginate(tt.ctx, 3, func(offset, limit uint64) ([]int, uint64, error) {
+				gotFetched = append(gotFetched, fetched{offset, limit})
+				if tt.failAfter > 0 && len(gotFetched) > tt.failAfter {
+					return nil, 0, errFetch
+				}
+				end := min(offset+limit, uint64(len(dataset)))
+				return dataset[offset:end], uint64(len(dataset)), nil
+			})
+
+			var (
+				gotItems []int
+				gotErr   error
+			)
+			seq(func(item int, err error) bool {
+				if err != nil {
+					gotErr = err
+					return false
+				}
+				gotItems = append(gotItems, item)
+				return tt.stopAfter == 0 || len(gotItems) < tt.stopAfter
+			})
+			assert.ErrorIs(t, gotErr, tt.wantErr)
+			assert.Equal(t, tt.wantItems, gotItems)
+			assert.Equal(t, tt.wantFetched, gotFetched)
+		})
+	}
+}
+
+func TestQueries_PaginateOrgs(t *testing.T) {
+	orgRow := func(id string, count uint64) []driver.Value {
+		return []driver.Value{id, testNow, testNow, "ro", domain.OrgStateActive, uint64(20211108), "org-name", "zitadel.ch", "", count}
+	}
+	expectPage := func(m sqlmock.Sqlmock, pagination string, rows ...[]driver.Value) {
+		m.ExpectBegin()
+		result := m.NewRows(prepareOrgsQueryCols)
+		for _, row := range rows {
+			result.AddRow(row...)
+		}
+		m.ExpectQuery(regexp.QuoteMeta(prepareOrgsQueryStmt) + `.*` + regexp.QuoteMeta(" ORDER BY projections.orgs1.id"+pagination)).
+			WillReturnRows(result)
+		m.ExpectCommit()
+		m.ExpectBegin()
+		m.ExpectQuery(regexp.QuoteMeta("FROM projections.current_states")).
+			WillReturnRows(m.NewRows([]string{"event_date", "position", "last_updated"}))
+		m.ExpectCommit()
+	}
+
+	client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+	require.NoError(t, err)
+	defer client.Close()
+	expectPage(mock, " LIMIT 2", orgRow("org-1", 5), orgRow("org-2", 5))
+	expectPage(mock, " LIMIT 2 OFFSET 2", orgRow("org-3", 5), orgRow("org-4", 5))
+	expectPage(mock, " LIMIT 2 OFFSET 4", orgRow("org-5", 5))
+
+	q := &Queries{
+		client: &database.DB{
+			DB:       client,
+			Database: new(prepareDB),
+		},
+	}
+	orgs, err := collect(q.PaginateOrgs(context.Background(), new(OrgSearchQueries), 2))
+	require.NoError(t, err)
+	ids := make([]string, len(orgs))
+	for i, org := range orgs {
+		ids[i] = org.ID
+	}
+	assert.Equal(t, []string{"org-1", "org-2", "org-3", "org-4", "org-5"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package projection
 
 import (
+	"context"
 	"time"
 )
 
@@ -13,6 +14,9 @@ type Config struct {
 	Customizations        map[string]CustomConfig
 	HandleActiveInstances time.Duration
 	TransactionDuration   time.Duration
+	// OrgsChanged is called with the instance id after the orgs or org domains projection
+	// committed events of the instance, it's not part of the configuration file.
+	OrgsChanged func(ctx context.Context, instanceID string) `mapstructure:"-"`
 }
 
 type CustomConfig struct {
@@ -23,4 +27,7 @@ type CustomConfig struct {
 	BulkLimit             *uint16
 	HandleActiveInstances *time.Duration
 	TransactionDuration   *time.Duration
+	// ReportProgress logs the progress of catch ups, e.g. during rebuilds.
+	// It's only supported by the sessions and executions projections.
+	ReportProgress *bool
 }
@@ -3,23 +3,32 @@ package projection
 import (
 	"context"
 
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	old_handler "github.com/zitadel/zitadel/internal/eventstore/handler"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	exec "github.com/zitadel/zitadel/internal/repository/execution"
 	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/target"
 )
 
 const (
-	ExecutionTable            = "projections.executions"
+	ExecutionTable            = "projections.executions1"
 	ExecutionIDCol            = "id"
 	ExecutionCreationDateCol  = "creation_date"
 	ExecutionChangeDateCol    = "change_date"
 	ExecutionResourceOwnerCol = "resource_owner"
 	ExecutionInstanceIDCol    = "instance_id"
 	ExecutionSequenceCol      = "sequence"
-	ExecutionTargetsCol       = "targets"
-	ExecutionIncludesCol      = "includes"
+	ExecutionEnabledCol       = "enabled"
+
+	executionTargetSuffix         = "targets"
+	ExecutionTargetTable          = ExecutionTable + "_" + executionTargetSuffix
+	ExecutionTargetInstanceIDCol  = "instance_id"
+	ExecutionTargetExecutionIDCol = "execution_id"
+	ExecutionTargetTypeCol        = "type"
+	ExecutionTargetTargetCol      = "target"
+	ExecutionTargetPositionCol    = "position"
 )
 
 type executionProjection struct{}
@@ -33,7 +42,7 @@ func (*executionProjection) Name() string {
 }
 
 func (*executionProjection) Init() *old_handler.Check {
-	return handler.NewTableCheck(
+	return handler.NewMultiTableCheck(
 		handler.NewTable([]*handler.InitColumn{
 			handler.NewColumn(ExecutionIDCol, handler.ColumnTypeText),
 			handler.NewColumn(ExecutionCreationDateCol, handler.ColumnTypeTimestamp),
@@ -41,11 +50,25 @@ func (*executionProjection) Init() *old_handler.Check {
 			handler.NewColumn(ExecutionResourceOwnerCol, handler.ColumnTypeText),
 			handler.NewColumn(ExecutionInstanceIDCol, handler.ColumnTypeText),
 			handler.NewColumn(ExecutionSequenceCol, handler.ColumnTypeInt64),
-			handler.NewColumn(ExecutionTargetsCol, handler.ColumnTypeTextArray, handler.Nullable()),
-			handler.NewColumn(ExecutionIncludesCol, handler.ColumnTypeTextArray, handler.Nullable()),
+			handler.NewColumn(ExecutionEnabledCol, handler.ColumnTypeBool, handler.Default(true)),
 		},
 			handler.NewPrimaryKey(ExecutionInstanceIDCol, ExecutionIDCol),
 		),
+		handler.NewSuffixedTable([]*handler.InitColumn{
+			handler.NewColumn(ExecutionTargetInstanceIDCol, handler.ColumnTypeText),
+			handler.NewColumn(ExecutionTargetExecutionIDCol, handler.ColumnTypeText),
+			handler.NewColumn(ExecutionTargetTypeCol, handler.ColumnTypeEnum),
+			handler.NewColumn(ExecutionTargetTargetCol, handler.ColumnTypeText),
+			handler.NewColumn(ExecutionTargetPositionCol, handler.ColumnTypeInt64),
+		},
+			handler.NewPrimaryKey(ExecutionTargetInstanceIDCol, ExecutionTargetExecutionIDCol, ExecutionTargetTypeCol, ExecutionTargetTargetCol),
+			executionTargetSuffix,
+			handler.WithForeignKey(handler.NewForeignKey(
+				"execution",
+				[]string{ExecutionTargetInstanceIDCol, ExecutionTargetExecutionIDCol},
+				[]string{ExecutionInstanceIDCol, ExecutionIDCol},
+			)),
+		),
 	)
 }
 
@@ -62,6 +85,23 @@ func (p *executionProjection) Reducers() []handler.AggregateReducer {
 					Event:  exec.RemovedEventType,
 					Reduce: p.reduceExecutionRemoved,
 				},
+				{
+					Event:  exec.EnabledEventType,
+					Reduce: p.reduceExecutionEnabled,
+				},
+				{
+					Event:  exec.DisabledEventType,
+					Reduce: p.reduceExecutionDisabled,
+				},
+			},
+		},
+		{
+			Aggregate: target.AggregateType,
+			EventReducers: []handler.EventReducer{
+				{
+					Event:  target.RemovedEventType,
+					Reduce: p.reduceTargetRemoved,
+				},
 			},
 		},
 		{
@@ -81,6 +121,12 @@ func (p *executionProjection) reduceExecutionSet(event eventstore.Event) (*handl
 	if err != nil {
 		return nil, err
 	}
+	// the conflict target must match the primary key of the table,
+	// the resource owner of an execution is always its instance
+	conflictCols := []handler.Column{
+		handler.NewCol(ExecutionInstanceIDCol, e.Aggregate().InstanceID),
+		handler.NewCol(ExecutionIDCol, e.Aggregate().ID),
+	}
 	columns := []handler.Column{
 		handler.NewCol(ExecutionInstanceIDCol, e.Aggregate().InstanceID),
 		handler.NewCol(ExecutionIDCol, e.Aggregate().ID),
@@ -88,10 +134,43 @@ func (p *executionProjection) reduceExecutionSet(event eventstore.Event) (*handl
 		handler.NewCol(ExecutionCreationDateCol, handler.OnlySetValueOnInsert(ExecutionTable, e.CreationDate())),
 		handler.NewCol(ExecutionChangeDateCol, e.CreationDate()),
 		handler.NewCol(ExecutionSequenceCol, e.Sequence()),
-		handler.NewCol(ExecutionTargetsCol, e.Targets),
-		handler.NewCol(ExecutionIncludesCol, e.Includes),
+		// setting an execution doesn't change its state, only new executions are enabled
+		handler.NewCol(ExecutionEnabledCol, handler.OnlySetValueOnInsert(ExecutionTable, true)),
+	}
+	// the statements are not coalesced with other set events during catch up,
+	// because the target statements depend on the upsert and on each other
+	stmts := []func(eventstore.Event) handler.Exec{
+		handler.AddUpsertStatement(conflictCols, columns),
 	}
-	return handler.NewUpsertStatement(e, columns[0:2], columns), nil
+	stmts = append(stmts, executionTargetStatements(e, domain.ExecutionTargetTypeTarget, e.Targets)...)
+	stmts = append(stmts, executionTargetStatements(e, domain.ExecutionTargetTypeInclude, e.Includes)...)
+	return handler.NewMultiStatement(e, stmts...), nil
+}
+
+// executionTargetStatements only removes the targets which are not set anymore
+// and upserts the remaining ones to keep their position up to date.
+func executionTargetStatements(e eventstore.Event, targetType domain.ExecutionTargetType, targets []string) []func(eventstore.Event) handler.Exec {
+	conditions := []handler.Condition{
+		handler.NewCond(ExecutionTargetInstanceIDCol, e.Aggregate().InstanceID),
+		handler.NewCond(ExecutionTargetExecutionIDCol, e.Aggregate().ID),
+		handler.NewCond(ExecutionTargetTypeCol, targetType),
+	}
+	if len(targets) > 0 {
+		conditions = append(conditions, handler.Not(handler.NewOneOfTextCond(ExecutionTargetTargetCol, targets)))
+	}
+	stmts := make([]func(eventstore.Event) handler.Exec, 0, len(targets)+1)
+	stmts = append(stmts, handler.AddDeleteStatement(conditions, handler.WithTableSuffix(executionTargetSuffix)))
+	for i, target := range targets {
+		columns := []handler.Column{
+			handler.NewCol(ExecutionTargetInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCol(ExecutionTargetExecutionIDCol, e.Aggregate().ID),
+			handler.NewCol(ExecutionTargetTypeCol, targetType),
+			handler.NewCol(ExecutionTargetTargetCol, target),
+			handler.NewCol(ExecutionTargetPositionCol, i),
+		}
+		stmts = append(stmts, handler.AddUpsertStatement(columns[0:4], columns, handler.WithTableSuffix(executionTargetSuffix)))
+	}
+	return stmts
 }
 
 func (p *executionProjection) reduceExecutionRemoved(event eventstore.Event) (*handler.Statement, error) {
@@ -99,11 +178,59 @@ func (p *executionProjection) reduceExecutionRemoved(event eventstore.Event) (*h
 	if err != nil {
 		return nil, err
 	}
-	return handler.NewDeleteStatement(
+	// the targets are removed by the foreign key,
+	// consecutive removals are coalesced during catch up
+	return handler.NewBatchableDeleteStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(ExecutionInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCol(ExecutionIDCol, e.Aggregate().ID),
+		},
+	), nil
+}
+
+func (p *executionProjection) reduceExecutionEnabled(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*exec.EnabledEvent](event)
+	if err != nil {
+		return nil, err
+	}
+	return executionEnabledStatement(e, true), nil
+}
+
+func (p *executionProjection) reduceExecutionDisabled(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*exec.DisabledEvent](event)
+	if err != nil {
+		return nil, err
+	}
+	return executionEnabledStatement(e, false), nil
+}
+
+func executionEnabledStatement(e eventstore.Event, enabled bool) *handler.Statement {
+	return handler.NewUpdateStatement(
 		e,
+		[]handler.Column{
+			handler.NewCol(ExecutionChangeDateCol, e.CreatedAt()),
+			handler.NewCol(ExecutionSequenceCol, e.Sequence()),
+			handler.NewCol(ExecutionEnabledCol, enabled),
+		},
 		[]handler.Condition{
 			handler.NewCond(ExecutionInstanceIDCol, e.Aggregate().InstanceID),
 			handler.NewCond(ExecutionIDCol, e.Aggregate().ID),
 		},
+	)
+}
+
+func (p *executionProjection) reduceTargetRemoved(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*target.RemovedEvent](event)
+	if err != nil {
+		return nil, err
+	}
+	return handler.NewBatchableDeleteStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(ExecutionTargetInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCol(ExecutionTargetTargetCol, e.Aggregate().ID),
+		},
+		handler.WithTableSuffix(executionTargetSuffix),
 	), nil
 }
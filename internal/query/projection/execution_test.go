@@ -1,12 +1,21 @@
 package projection
 
 import (
+	"database/sql"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	exec "github.com/zitadel/zitadel/internal/repository/execution"
 	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/target"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
@@ -39,7 +48,7 @@ func TestExecutionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.executions (instance_id, id, resource_owner, creation_date, change_date, sequence, targets, includes) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (instance_id, id) DO UPDATE SET (resource_owner, creation_date, change_date, sequence, targets, includes) = (EXCLUDED.resource_owner, projections.executions.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, EXCLUDED.targets, EXCLUDED.includes)",
+							expectedStmt: "INSERT INTO projections.executions1 (instance_id, id, resource_owner, creation_date, change_date, sequence, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (instance_id, id) DO UPDATE SET (resource_owner, creation_date, change_date, sequence, enabled) = (EXCLUDED.resource_owner, projections.executions1.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, projections.executions1.enabled)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"agg-id",
@@ -47,8 +56,334 @@ func TestExecutionProjection_reduces(t *testing.T) {
 								anyArg{},
 								anyArg{},
 								uint64(15),
-								[]string{"target"},
-								[]string{"include"},
+								true,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								database.TextArray[string]{"target"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								"target",
+								0,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+								database.TextArray[string]{"include"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+								"include",
+								0,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceExecutionSet add target",
+			args: args{
+				event: getEvent(
+					testEvent(
+						exec.SetEventType,
+						exec.AggregateType,
+						[]byte(`{"targets": ["target1", "target2"]}`),
+					),
+					eventstore.GenericEventMapper[exec.SetEvent],
+				),
+			},
+			reduce: (&executionProjection{}).reduceExecutionSet,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("execution"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.executions1 (instance_id, id, resource_owner, creation_date, change_date, sequence, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (instance_id, id) DO UPDATE SET (resource_owner, creation_date, change_date, sequence, enabled) = (EXCLUDED.resource_owner, projections.executions1.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, projections.executions1.enabled)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								"ro-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								true,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								database.TextArray[string]{"target1", "target2"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								"target1",
+								0,
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								"target2",
+								1,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceExecutionSet remove target",
+			args: args{
+				event: getEvent(
+					testEvent(
+						exec.SetEventType,
+						exec.AggregateType,
+						[]byte(`{"targets": ["target2"], "includes": ["include"]}`),
+					),
+					eventstore.GenericEventMapper[exec.SetEvent],
+				),
+			},
+			reduce: (&executionProjection{}).reduceExecutionSet,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("execution"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.executions1 (instance_id, id, resource_owner, creation_date, change_date, sequence, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (instance_id, id) DO UPDATE SET (resource_owner, creation_date, change_date, sequence, enabled) = (EXCLUDED.resource_owner, projections.executions1.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, projections.executions1.enabled)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								"ro-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								true,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								database.TextArray[string]{"target2"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								"target2",
+								0,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+								database.TextArray[string]{"include"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+								"include",
+								0,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceExecutionSet reorder targets",
+			args: args{
+				event: getEvent(
+					testEvent(
+						exec.SetEventType,
+						exec.AggregateType,
+						[]byte(`{"targets": ["target2", "target1"], "includes": ["include"]}`),
+					),
+					eventstore.GenericEventMapper[exec.SetEvent],
+				),
+			},
+			reduce: (&executionProjection{}).reduceExecutionSet,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("execution"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.executions1 (instance_id, id, resource_owner, creation_date, change_date, sequence, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (instance_id, id) DO UPDATE SET (resource_owner, creation_date, change_date, sequence, enabled) = (EXCLUDED.resource_owner, projections.executions1.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, projections.executions1.enabled)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								"ro-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								true,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								database.TextArray[string]{"target2", "target1"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								"target2",
+								0,
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeTarget,
+								"target1",
+								1,
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (execution_id = $2) AND (type = $3) AND (NOT (target = ANY($4)))",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+								database.TextArray[string]{"include"},
+							},
+						},
+						{
+							expectedStmt: "INSERT INTO projections.executions1_targets (instance_id, execution_id, type, target, position) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (instance_id, execution_id, type, target) DO UPDATE SET position = EXCLUDED.position",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								domain.ExecutionTargetTypeInclude,
+								"include",
+								0,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceExecutionDisabled",
+			args: args{
+				event: getEvent(
+					testEvent(
+						exec.DisabledEventType,
+						exec.AggregateType,
+						[]byte(`{}`),
+					),
+					eventstore.GenericEventMapper[exec.DisabledEvent],
+				),
+			},
+			reduce: (&executionProjection{}).reduceExecutionDisabled,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("execution"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.executions1 SET (change_date, sequence, enabled) = ($1, $2, $3) WHERE (instance_id = $4) AND (id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								false,
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceExecutionEnabled",
+			args: args{
+				event: getEvent(
+					testEvent(
+						exec.EnabledEventType,
+						exec.AggregateType,
+						[]byte(`{}`),
+					),
+					eventstore.GenericEventMapper[exec.EnabledEvent],
+				),
+			},
+			reduce: (&executionProjection{}).reduceExecutionEnabled,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("execution"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.executions1 SET (change_date, sequence, enabled) = ($1, $2, $3) WHERE (instance_id = $4) AND (id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								true,
+								"instance-id",
+								"agg-id",
 							},
 						},
 					},
@@ -74,7 +409,36 @@ func TestExecutionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.executions WHERE (instance_id = $1) AND (id = $2)",
+							expectedStmt: "DELETE FROM projections.executions1 WHERE (instance_id = $1) AND (id = $2)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceTargetRemoved",
+			args: args{
+				event: getEvent(
+					testEvent(
+						target.RemovedEventType,
+						target.AggregateType,
+						[]byte(`{"name": "target"}`),
+					),
+					eventstore.GenericEventMapper[target.RemovedEvent],
+				),
+			},
+			reduce: (&executionProjection{}).reduceTargetRemoved,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("target"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.executions1_targets WHERE (instance_id = $1) AND (target = $2)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"agg-id",
@@ -103,7 +467,7 @@ func TestExecutionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.executions WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.executions1 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -127,3 +491,59 @@ func TestExecutionProjection_reduces(t *testing.T) {
 		})
 	}
 }
+
+// upsertTable simulates the rows of a table keyed by the conflict target of upsert statements
+type upsertTable struct {
+	table string
+	rows  map[string]map[string]interface{}
+}
+
+func (e *upsertTable) Exec(stmt string, args ...interface{}) (sql.Result, error) {
+	prefix := "INSERT INTO " + e.table + " ("
+	if !strings.HasPrefix(stmt, prefix) {
+		return nil, nil
+	}
+	cols := strings.Split(stmt[len(prefix):strings.Index(stmt, ")")], ", ")
+	conflict := stmt[strings.Index(stmt, "ON CONFLICT (")+len("ON CONFLICT ("):]
+	conflictCols := strings.Split(conflict[:strings.Index(conflict, ")")], ", ")
+
+	row := make(map[string]interface{}, len(cols))
+	for i, col := range cols {
+		row[col] = args[i]
+	}
+	key := make([]string, len(conflictCols))
+	for i, col := range conflictCols {
+		key[i] = fmt.Sprint(row[col])
+	}
+	e.rows[strings.Join(key, "/")] = row
+	return nil, nil
+}
+
+func TestExecutionProjection_reduceExecutionSet_key(t *testing.T) {
+	init := new(recordingExecuter)
+	for _, execute := range new(executionProjection).Init().Executes {
+		_, err := execute(init, ExecutionTable)
+		require.NoError(t, err)
+	}
+	require.Contains(t, strings.Join(init.stmts, "\n"), "PRIMARY KEY (instance_id, id)")
+
+	table := &upsertTable{table: ExecutionTable, rows: make(map[string]map[string]interface{})}
+	set := func(instanceID, resourceOwner string, targets ...string) {
+		t.Helper()
+		event := testEvent(exec.SetEventType, exec.AggregateType, []byte(`{"targets": ["`+strings.Join(targets, `", "`)+`"]}`))
+		event.InstanceID = instanceID
+		event.ResourceOwner = sql.NullString{String: resourceOwner, Valid: true}
+		stmt, err := new(executionProjection).reduceExecutionSet(getEvent(event, eventstore.GenericEventMapper[exec.SetEvent])(t))
+		require.NoError(t, err)
+		require.NoError(t, stmt.Execute(table, ExecutionTable))
+	}
+
+	set("instance-1", "instance-1", "target")
+	set("instance-2", "instance-2", "target")
+	require.Len(t, table.rows, 2, "same id in different instances must be distinct rows")
+
+	set("instance-1", "instance-1", "other")
+	assert.Len(t, table.rows, 2, "setting an existing execution must not add a row")
+	assert.Equal(t, "instance-2", table.rows["instance-2/agg-id"]["resource_owner"])
+	assert.Equal(t, "instance-1", table.rows["instance-1/agg-id"]["resource_owner"])
+}
@@ -24,12 +24,21 @@ const (
 	OrgColumnSequence      = "sequence"
 	OrgColumnName          = "name"
 	OrgColumnDomain        = "primary_domain"
+	OrgColumnParentID      = "parent_id"
+	// OrgColumnNormalizedName holds the name normalized by [domain.NormalizeOrgName]
+	OrgColumnNormalizedName = "normalized_name"
+	// OrgColumnPreviousName holds the name before the last rename, it is null if the org was never renamed
+	OrgColumnPreviousName           = "previous_name"
+	OrgColumnPreviousNameChangeDate = "previous_name_change_date"
 )
 
+// OrgsTable is the table of the orgs read by the queries, it can be replaced by a shadow table, see [handler.Handler.Swap]
+var OrgsTable = handler.NewActiveTable(OrgProjectionTable)
+
 type orgProjection struct{}
 
 func (*orgProjection) Name() string {
-	return OrgProjectionTable
+	return OrgsTable.Name()
 }
 
 func newOrgProjection(ctx context.Context, config handler.Config) *handler.Handler {
@@ -49,10 +58,17 @@ func (p *orgProjection) Init() *old_handler.Check {
 			handler.NewColumn(OrgColumnSequence, handler.ColumnTypeInt64),
 			handler.NewColumn(OrgColumnName, handler.ColumnTypeText),
 			handler.NewColumn(OrgColumnDomain, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(OrgColumnParentID, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(OrgColumnNormalizedName, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(OrgColumnPreviousName, handler.ColumnTypeText, handler.Nullable()),
+			handler.NewColumn(OrgColumnPreviousNameChangeDate, handler.ColumnTypeTimestamp, handler.Nullable()),
 		},
 			handler.NewPrimaryKey(OrgColumnInstanceID, OrgColumnID),
 			handler.WithIndex(handler.NewIndex("domain", []string{OrgColumnDomain})),
 			handler.WithIndex(handler.NewIndex("name", []string{OrgColumnName})),
+			handler.WithIndex(handler.NewIndex("parent", []string{OrgColumnParentID})),
+			handler.WithIndex(handler.NewIndex("previous_name", []string{OrgColumnPreviousName})),
+			handler.WithIndex(handler.NewIndex("normalized_name", []string{OrgColumnInstanceID, OrgColumnNormalizedName})),
 		),
 	)
 }
@@ -82,6 +98,10 @@ func (p *orgProjection) Reducers() []handler.AggregateReducer {
 					Event:  org.OrgRemovedEventType,
 					Reduce: p.reduceOrgRemoved,
 				},
+				{
+					Event:  org.OrgParentSetEventType,
+					Reduce: p.reduceOrgParentSet,
+				},
 				{
 					Event:  org.OrgDomainPrimarySetEventType,
 					Reduce: p.reducePrimaryDomainSet,
@@ -115,6 +135,7 @@ func (p *orgProjection) reduceOrgAdded(event eventstore.Event) (*handler.Stateme
 			handler.NewCol(OrgColumnInstanceID, e.Aggregate().InstanceID),
 			handler.NewCol(OrgColumnSequence, e.Sequence()),
 			handler.NewCol(OrgColumnName, e.Name),
+			handler.NewCol(OrgColumnNormalizedName, domain.NormalizeOrgName(e.Name)),
 			handler.NewCol(OrgColumnState, domain.OrgStateActive),
 		},
 	), nil
@@ -133,7 +154,11 @@ func (p *orgProjection) reduceOrgChanged(event eventstore.Event) (*handler.State
 		[]handler.Column{
 			handler.NewCol(OrgColumnChangeDate, e.CreationDate()),
 			handler.NewCol(OrgColumnSequence, e.Sequence()),
+			// the previous name is copied from the row before the update
+			handler.NewCopyCol(OrgColumnPreviousName, OrgColumnName),
+			handler.NewCol(OrgColumnPreviousNameChangeDate, e.CreationDate()),
 			handler.NewCol(OrgColumnName, e.Name),
+			handler.NewCol(OrgColumnNormalizedName, domain.NormalizeOrgName(e.Name)),
 		},
 		[]handler.Condition{
 			handler.NewCond(OrgColumnID, e.Aggregate().ID),
@@ -180,6 +205,25 @@ func (p *orgProjection) reduceOrgReactivated(event eventstore.Event) (*handler.S
 	), nil
 }
 
+func (p *orgProjection) reduceOrgParentSet(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*org.OrgParentSetEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Pa4nt", "reduce.wrong.event.type %s", org.OrgParentSetEventType)
+	}
+	return handler.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(OrgColumnChangeDate, e.CreationDate()),
+			handler.NewCol(OrgColumnSequence, e.Sequence()),
+			handler.NewCol(OrgColumnParentID, e.ParentID),
+		},
+		[]handler.Condition{
+			handler.NewCond(OrgColumnID, e.Aggregate().ID),
+			handler.NewCond(OrgColumnInstanceID, e.Aggregate().InstanceID),
+		},
+	), nil
+}
+
 func (p *orgProjection) reducePrimaryDomainSet(event eventstore.Event) (*handler.Statement, error) {
 	e, ok := event.(*org.DomainPrimarySetEvent)
 	if !ok {
@@ -51,6 +51,66 @@ func TestOrgProjection_reduces(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "reduceOrgParentSet",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.OrgParentSetEventType,
+						org.AggregateType,
+						[]byte(`{"parentId": "parent-id"}`),
+					), org.OrgParentSetEventMapper),
+			},
+			reduce: (&orgProjection{}).reduceOrgParentSet,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("org"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.orgs1 SET (change_date, sequence, parent_id) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								"parent-id",
+								"agg-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceOrgParentSet removed parent",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.OrgParentSetEventType,
+						org.AggregateType,
+						[]byte(`{}`),
+					), org.OrgParentSetEventMapper),
+			},
+			reduce: (&orgProjection{}).reduceOrgParentSet,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("org"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.orgs1 SET (change_date, sequence, parent_id) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								"",
+								"agg-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "reduceOrgReactivated",
 			args: args{
@@ -118,7 +178,7 @@ func TestOrgProjection_reduces(t *testing.T) {
 					testEvent(
 						org.OrgChangedEventType,
 						org.AggregateType,
-						[]byte(`{"name": "new name"}`),
+						[]byte(`{"name": "Néw  Name"}`),
 					), org.OrgChangedEventMapper),
 			},
 			reduce: (&orgProjection{}).reduceOrgChanged,
@@ -128,10 +188,12 @@ func TestOrgProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.orgs1 SET (change_date, sequence, name) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.orgs1 SET (change_date, sequence, previous_name, previous_name_change_date, name, normalized_name) = ($1, $2, name, $3, $4, $5) WHERE (id = $6) AND (instance_id = $7)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
+								anyArg{},
+								"Néw  Name",
 								"new name",
 								"agg-id",
 								"instance-id",
@@ -175,7 +237,7 @@ func TestOrgProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.orgs1 (id, creation_date, change_date, resource_owner, instance_id, sequence, name, org_state) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+							expectedStmt: "INSERT INTO projections.orgs1 (id, creation_date, change_date, resource_owner, instance_id, sequence, name, normalized_name, org_state) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
 							expectedArgs: []interface{}{
 								"agg-id",
 								anyArg{},
@@ -184,6 +246,7 @@ func TestOrgProjection_reduces(t *testing.T) {
 								"instance-id",
 								uint64(15),
 								"name",
+								"name",
 								domain.OrgStateActive,
 							},
 						},
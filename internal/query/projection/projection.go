@@ -3,6 +3,8 @@ package projection
 import (
 	"context"
 
+	"github.com/zitadel/logging"
+
 	internal_authz "github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/database"
@@ -102,7 +104,7 @@ func Create(ctx context.Context, sqlClient *database.DB, es handler.EventStore,
 		TransactionDuration:   config.TransactionDuration,
 	}
 
-	OrgProjection = newOrgProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["orgs"]))
+	OrgProjection = newOrgProjection(ctx, withChangeCallback(applyCustomConfig(projectionConfig, config.Customizations["orgs"]), config.OrgsChanged))
 	OrgMetadataProjection = newOrgMetadataProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["org_metadata"]))
 	ActionProjection = newActionProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["actions"]))
 	FlowProjection = newFlowProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["flows"]))
@@ -115,7 +117,7 @@ func Create(ctx context.Context, sqlClient *database.DB, es handler.EventStore,
 	LabelPolicyProjection = newLabelPolicyProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["label_policy"]))
 	ProjectGrantProjection = newProjectGrantProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["project_grants"]))
 	ProjectRoleProjection = newProjectRoleProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["project_roles"]))
-	OrgDomainProjection = newOrgDomainProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["org_domains"]))
+	OrgDomainProjection = newOrgDomainProjection(ctx, withChangeCallback(applyCustomConfig(projectionConfig, config.Customizations["org_domains"]), config.OrgsChanged))
 	LoginPolicyProjection = newLoginPolicyProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["login_policies"]))
 	IDPProjection = newIDPProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["idps"]))
 	AppProjection = newAppProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["apps"]))
@@ -147,7 +149,7 @@ func Create(ctx context.Context, sqlClient *database.DB, es handler.EventStore,
 	SecurityPolicyProjection = newSecurityPolicyProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["security_policies"]))
 	NotificationPolicyProjection = newNotificationPolicyProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["notification_policies"]))
 	DeviceAuthProjection = newDeviceAuthProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["device_auth"]))
-	SessionProjection = newSessionProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["sessions"]))
+	SessionProjection = newSessionProjection(ctx, withProgressLog(applyCustomConfig(projectionConfig, config.Customizations["sessions"]), config.Customizations["sessions"]))
 	AuthRequestProjection = newAuthRequestProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["auth_requests"]))
 	MilestoneProjection = newMilestoneProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["milestones"]), systemUsers)
 	QuotaProjection = newQuotaProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["quotas"]))
@@ -156,12 +158,22 @@ func Create(ctx context.Context, sqlClient *database.DB, es handler.EventStore,
 	SystemFeatureProjection = newSystemFeatureProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["system_features"]))
 	InstanceFeatureProjection = newInstanceFeatureProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["instance_features"]))
 	TargetProjection = newTargetProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["targets"]))
-	ExecutionProjection = newExecutionProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["executions"]))
+	ExecutionProjection = newExecutionProjection(ctx, withProgressLog(applyCustomConfig(projectionConfig, config.Customizations["executions"]), config.Customizations["executions"]))
 	UserSchemaProjection = newUserSchemaProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["user_schemas"]))
 	newProjectionsList()
 	return nil
 }
 
+// LoadActiveTables activates the tables of the projections which were swapped, see [handler.Handler.Swap]
+func LoadActiveTables(ctx context.Context, client *database.DB) error {
+	for _, table := range []*handler.ActiveTable{OrgsTable, SessionsTable} {
+		if err := table.Load(ctx, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func Projections() []projection {
 	return projections
 }
@@ -208,6 +220,42 @@ func applyCustomConfig(config handler.Config, customConfig CustomConfig) handler
 	return config
 }
 
+// withProgressLog logs the progress of catch ups if enabled by the custom config.
+// It's only supported by projections with many events, whose rebuilds take long.
+func withProgressLog(config handler.Config, customConfig CustomConfig) handler.Config {
+	if customConfig.ReportProgress != nil && *customConfig.ReportProgress {
+		config.ReportProgress = logProgress
+	}
+	return config
+}
+
+// withChangeCallback calls changed with the instance id after each iteration which processed events was committed.
+// A progress callback which is already set is still called.
+func withChangeCallback(config handler.Config, changed func(ctx context.Context, instanceID string)) handler.Config {
+	if changed == nil {
+		return config
+	}
+	report := config.ReportProgress
+	config.ReportProgress = func(ctx context.Context, progress handler.Progress) {
+		if report != nil {
+			report(ctx, progress)
+		}
+		changed(ctx, progress.InstanceID)
+	}
+	return config
+}
+
+func logProgress(_ context.Context, progress handler.Progress) {
+	logging.WithFields(
+		"projection", progress.Projection,
+		"instance", progress.InstanceID,
+		"processed", progress.ProcessedEvents,
+		"position", progress.Position,
+		"sequence", progress.Sequence,
+		"remaining", progress.EstimatedRemaining,
+	).Info("projection catch up progress")
+}
+
 // we know this is ugly, but we need to have a singleton slice of all projections
 // and are only able to initialize it after all projections are created
 // as setup and start currently create them individually, we make sure we get the right one
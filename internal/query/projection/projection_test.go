@@ -0,0 +1,41 @@
+package projection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
+)
+
+func Test_withChangeCallback(t *testing.T) {
+	t.Run("no callback", func(t *testing.T) {
+		config := withChangeCallback(handler.Config{}, nil)
+		assert.Nil(t, config.ReportProgress)
+	})
+	t.Run("callback", func(t *testing.T) {
+		var changed []string
+		config := withChangeCallback(handler.Config{}, func(_ context.Context, instanceID string) {
+			changed = append(changed, instanceID)
+		})
+		config.ReportProgress(context.Background(), handler.Progress{InstanceID: "instance"})
+		assert.Equal(t, []string{"instance"}, changed)
+	})
+	t.Run("progress still reported", func(t *testing.T) {
+		var reported, changed []string
+		config := withChangeCallback(
+			handler.Config{
+				ReportProgress: func(_ context.Context, progress handler.Progress) {
+					reported = append(reported, progress.InstanceID)
+				},
+			},
+			func(_ context.Context, instanceID string) {
+				changed = append(changed, instanceID)
+			},
+		)
+		config.ReportProgress(context.Background(), handler.Progress{InstanceID: "instance"})
+		assert.Equal(t, []string{"instance"}, reported)
+		assert.Equal(t, []string{"instance"}, changed)
+	})
+}
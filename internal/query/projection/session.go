@@ -8,13 +8,14 @@ import (
 	old_handler "github.com/zitadel/zitadel/internal/eventstore/handler"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/repository/session"
 	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 const (
-	SessionsProjectionTable = "projections.sessions8"
+	SessionsProjectionTable = "projections.sessions9"
 
 	SessionColumnID                     = "id"
 	SessionColumnCreationDate           = "creation_date"
@@ -32,6 +33,7 @@ const (
 	SessionColumnWebAuthNCheckedAt      = "webauthn_checked_at"
 	SessionColumnWebAuthNUserVerified   = "webauthn_user_verified"
 	SessionColumnTOTPCheckedAt          = "totp_checked_at"
+	SessionColumnTOTPUserVerified       = "totp_user_verified"
 	SessionColumnOTPSMSCheckedAt        = "otp_sms_checked_at"
 	SessionColumnOTPEmailCheckedAt      = "otp_email_checked_at"
 	SessionColumnMetadata               = "metadata"
@@ -41,8 +43,13 @@ const (
 	SessionColumnUserAgentDescription   = "user_agent_description"
 	SessionColumnUserAgentHeader        = "user_agent_header"
 	SessionColumnExpiration             = "expiration"
+	SessionColumnTerminatedAt           = "terminated_at"
+	SessionColumnTerminationReason      = "termination_reason"
 )
 
+// SessionsTable is the table of the sessions read by the queries, it can be replaced by a shadow table, see [handler.Handler.Swap]
+var SessionsTable = handler.NewActiveTable(SessionsProjectionTable)
+
 type sessionProjection struct{}
 
 func newSessionProjection(ctx context.Context, config handler.Config) *handler.Handler {
@@ -50,7 +57,7 @@ func newSessionProjection(ctx context.Context, config handler.Config) *handler.H
 }
 
 func (*sessionProjection) Name() string {
-	return SessionsProjectionTable
+	return SessionsTable.Name()
 }
 
 func (*sessionProjection) Init() *old_handler.Check {
@@ -72,6 +79,7 @@ func (*sessionProjection) Init() *old_handler.Check {
 			handler.NewColumn(SessionColumnWebAuthNCheckedAt, handler.ColumnTypeTimestamp, handler.Nullable()),
 			handler.NewColumn(SessionColumnWebAuthNUserVerified, handler.ColumnTypeBool, handler.Nullable()),
 			handler.NewColumn(SessionColumnTOTPCheckedAt, handler.ColumnTypeTimestamp, handler.Nullable()),
+			handler.NewColumn(SessionColumnTOTPUserVerified, handler.ColumnTypeBool, handler.Nullable()),
 			handler.NewColumn(SessionColumnOTPSMSCheckedAt, handler.ColumnTypeTimestamp, handler.Nullable()),
 			handler.NewColumn(SessionColumnOTPEmailCheckedAt, handler.ColumnTypeTimestamp, handler.Nullable()),
 			handler.NewColumn(SessionColumnMetadata, handler.ColumnTypeJSONB, handler.Nullable()),
@@ -81,12 +89,17 @@ func (*sessionProjection) Init() *old_handler.Check {
 			handler.NewColumn(SessionColumnUserAgentDescription, handler.ColumnTypeText, handler.Nullable()),
 			handler.NewColumn(SessionColumnUserAgentHeader, handler.ColumnTypeJSONB, handler.Nullable()),
 			handler.NewColumn(SessionColumnExpiration, handler.ColumnTypeTimestamp, handler.Nullable()),
+			handler.NewColumn(SessionColumnTerminatedAt, handler.ColumnTypeTimestamp, handler.Nullable()),
+			handler.NewColumn(SessionColumnTerminationReason, handler.ColumnTypeText, handler.Nullable()),
 		},
 			handler.NewPrimaryKey(SessionColumnInstanceID, SessionColumnID),
 			handler.WithIndex(handler.NewIndex(
 				SessionColumnUserAgentFingerprintID+"_idx",
 				[]string{SessionColumnUserAgentFingerprintID},
 			)),
+			handler.WithIndex(handler.NewIndex("user_id", []string{SessionColumnInstanceID, SessionColumnUserID})),
+			handler.WithIndex(handler.NewIndex("creator", []string{SessionColumnInstanceID, SessionColumnCreator})),
+			handler.WithIndex(handler.NewIndex("token_id", []string{SessionColumnInstanceID, SessionColumnTokenID})),
 		),
 	)
 }
@@ -155,6 +168,15 @@ func (p *sessionProjection) Reducers() []handler.AggregateReducer {
 				},
 			},
 		},
+		{
+			Aggregate: org.AggregateType,
+			EventReducers: []handler.EventReducer{
+				{
+					Event:  org.OrgRemovedEventType,
+					Reduce: p.reduceOrgRemoved,
+				},
+			},
+		},
 		{
 			Aggregate: user.AggregateType,
 			EventReducers: []handler.EventReducer{
@@ -297,6 +319,7 @@ func (p *sessionProjection) reduceTOTPChecked(event eventstore.Event) (*handler.
 			handler.NewCol(SessionColumnChangeDate, e.CreationDate()),
 			handler.NewCol(SessionColumnSequence, e.Sequence()),
 			handler.NewCol(SessionColumnTOTPCheckedAt, e.CheckedAt),
+			handler.NewCol(SessionColumnTOTPUserVerified, e.UserVerified),
 		},
 		[]handler.Condition{
 			handler.NewCond(SessionColumnID, e.Aggregate().ID),
@@ -411,8 +434,16 @@ func (p *sessionProjection) reduceSessionTerminated(event eventstore.Event) (*ha
 		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-SAftn", "reduce.wrong.event.type %s", session.TerminateType)
 	}
 
-	return handler.NewDeleteStatement(
+	// the row is kept as tombstone so that terminated sessions can be audited
+	return handler.NewUpdateStatement(
 		e,
+		[]handler.Column{
+			handler.NewCol(SessionColumnChangeDate, e.CreationDate()),
+			handler.NewCol(SessionColumnSequence, e.Sequence()),
+			handler.NewCol(SessionColumnState, domain.SessionStateTerminated),
+			handler.NewCol(SessionColumnTerminatedAt, e.CreationDate()),
+			handler.NewCol(SessionColumnTerminationReason, e.Reason),
+		},
 		[]handler.Condition{
 			handler.NewCond(SessionColumnID, e.Aggregate().ID),
 			handler.NewCond(SessionColumnInstanceID, e.Aggregate().InstanceID),
@@ -438,3 +469,20 @@ func (p *sessionProjection) reducePasswordChanged(event eventstore.Event) (*hand
 		},
 	), nil
 }
+
+// reduceOrgRemoved deletes the sessions of the users of the org,
+// the sessions themselves are owned by the instance
+func (p *sessionProjection) reduceOrgRemoved(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*org.OrgRemovedEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Rm4qe", "reduce.wrong.event.type %s", org.OrgRemovedEventType)
+	}
+
+	return handler.NewDeleteStatement(
+		e,
+		[]handler.Condition{
+			handler.NewCond(SessionColumnInstanceID, e.Aggregate().InstanceID),
+			handler.NewCond(SessionColumnUserResourceOwner, e.Aggregate().ID),
+		},
+	), nil
+}
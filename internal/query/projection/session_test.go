@@ -1,15 +1,20 @@
 package projection
 
 import (
+	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/muhlemmer/gu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/repository/session"
 	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -51,7 +56,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.sessions8 (id, instance_id, creation_date, change_date, resource_owner, state, sequence, creator, user_agent_fingerprint_id, user_agent_description, user_agent_ip, user_agent_header) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+							expectedStmt: "INSERT INTO projections.sessions9 (id, instance_id, creation_date, change_date, resource_owner, state, sequence, creator, user_agent_fingerprint_id, user_agent_description, user_agent_ip, user_agent_header) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
 							expectedArgs: []interface{}{
 								"agg-id",
 								"instance-id",
@@ -71,6 +76,76 @@ func TestSessionProjection_reduces(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "instance reduceSessionAdded without user agent",
+			args: args{
+				event: getEvent(testEvent(
+					session.AddedType,
+					session.AggregateType,
+					[]byte(`{}`),
+				), session.AddedEventMapper),
+			},
+			reduce: (&sessionProjection{}).reduceSessionAdded,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("session"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.sessions9 (id, instance_id, creation_date, change_date, resource_owner, state, sequence, creator) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+							expectedArgs: []interface{}{
+								"agg-id",
+								"instance-id",
+								anyArg{},
+								anyArg{},
+								"ro-id",
+								domain.SessionStateActive,
+								uint64(15),
+								"editor-user",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "instance reduceSessionAdded with fingerprint only",
+			args: args{
+				event: getEvent(testEvent(
+					session.AddedType,
+					session.AggregateType,
+					[]byte(`{
+						"user_agent": {
+							"fingerprint_id": "fp1"
+						}
+					}`),
+				), session.AddedEventMapper),
+			},
+			reduce: (&sessionProjection{}).reduceSessionAdded,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("session"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.sessions9 (id, instance_id, creation_date, change_date, resource_owner, state, sequence, creator, user_agent_fingerprint_id, user_agent_description) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+							expectedArgs: []interface{}{
+								"agg-id",
+								"instance-id",
+								anyArg{},
+								anyArg{},
+								"ro-id",
+								domain.SessionStateActive,
+								uint64(15),
+								"editor-user",
+								gu.Ptr("fp1"),
+								(*string)(nil),
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "instance reduceUserChecked",
 			args: args{
@@ -91,7 +166,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, user_id, user_resource_owner, user_checked_at) = ($1, $2, $3, $4, $5) WHERE (id = $6) AND (instance_id = $7)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, user_id, user_resource_owner, user_checked_at) = ($1, $2, $3, $4, $5) WHERE (id = $6) AND (instance_id = $7)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -124,7 +199,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, password_checked_at) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, password_checked_at) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -156,7 +231,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, webauthn_checked_at, webauthn_user_verified) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, webauthn_checked_at, webauthn_user_verified) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -188,7 +263,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, intent_checked_at) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, intent_checked_at) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -203,6 +278,39 @@ func TestSessionProjection_reduces(t *testing.T) {
 		},
 		{
 			name: "instance reduceOTPChecked",
+			args: args{
+				event: getEvent(testEvent(
+					session.AddedType,
+					session.AggregateType,
+					[]byte(`{
+						"checkedAt": "2023-05-04T00:00:00Z",
+						"userVerified": true
+					}`),
+				), eventstore.GenericEventMapper[session.TOTPCheckedEvent]),
+			},
+			reduce: (&sessionProjection{}).reduceTOTPChecked,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("session"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, totp_checked_at, totp_user_verified) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								anyArg{},
+								time.Date(2023, time.May, 4, 0, 0, 0, 0, time.UTC),
+								true,
+								"agg-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "instance reduceOTPChecked without user verified",
 			args: args{
 				event: getEvent(testEvent(
 					session.AddedType,
@@ -219,11 +327,12 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, totp_checked_at) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, totp_checked_at, totp_user_verified) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
 								time.Date(2023, time.May, 4, 0, 0, 0, 0, time.UTC),
+								false,
 								"agg-id",
 								"instance-id",
 							},
@@ -250,7 +359,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, token_id) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, token_id) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -283,7 +392,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, metadata) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, metadata) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -316,7 +425,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET (change_date, sequence, expiration) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, expiration) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -331,6 +440,37 @@ func TestSessionProjection_reduces(t *testing.T) {
 		},
 		{
 			name: "instance reduceSessionTerminated",
+			args: args{
+				event: getEvent(testEvent(
+					session.TerminateType,
+					session.AggregateType,
+					[]byte(`{"reason": "logout"}`),
+				), session.TerminateEventMapper),
+			},
+			reduce: (&sessionProjection{}).reduceSessionTerminated,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("session"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, state, terminated_at, termination_reason) = ($1, $2, $3, $4, $5) WHERE (id = $6) AND (instance_id = $7)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								domain.SessionStateTerminated,
+								anyArg{},
+								domain.SessionTerminationReasonLogout,
+								"agg-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "instance reduceSessionTerminated without reason",
 			args: args{
 				event: getEvent(testEvent(
 					session.TerminateType,
@@ -345,8 +485,13 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.sessions8 WHERE (id = $1) AND (instance_id = $2)",
+							expectedStmt: "UPDATE projections.sessions9 SET (change_date, sequence, state, terminated_at, termination_reason) = ($1, $2, $3, $4, $5) WHERE (id = $6) AND (instance_id = $7)",
 							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								domain.SessionStateTerminated,
+								anyArg{},
+								domain.SessionTerminationReasonUnspecified,
 								"agg-id",
 								"instance-id",
 							},
@@ -372,7 +517,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.sessions8 WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.sessions9 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -381,6 +526,33 @@ func TestSessionProjection_reduces(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "org reduceOrgRemoved",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.OrgRemovedEventType,
+						org.AggregateType,
+						nil,
+					), org.OrgRemovedEventMapper),
+			},
+			reduce: (&sessionProjection{}).reduceOrgRemoved,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("org"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.sessions9 WHERE (instance_id = $1) AND (user_resource_owner = $2)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "reducePasswordChanged",
 			args: args{
@@ -402,7 +574,7 @@ func TestSessionProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.sessions8 SET password_checked_at = $1 WHERE (user_id = $2) AND (instance_id = $3) AND (password_checked_at < $4)",
+							expectedStmt: "UPDATE projections.sessions9 SET password_checked_at = $1 WHERE (user_id = $2) AND (instance_id = $3) AND (password_checked_at < $4)",
 							expectedArgs: []interface{}{
 								nil,
 								"agg-id",
@@ -429,3 +601,54 @@ func TestSessionProjection_reduces(t *testing.T) {
 		})
 	}
 }
+
+type recordingExecuter struct {
+	stmts []string
+	args  [][]interface{}
+}
+
+func (e *recordingExecuter) Exec(stmt string, args ...interface{}) (sql.Result, error) {
+	e.stmts = append(e.stmts, stmt)
+	e.args = append(e.args, args)
+	return nil, nil
+}
+
+func TestSessionProjection_reduceOrgRemoved(t *testing.T) {
+	var reduce handler.Reduce
+	for _, aggregate := range new(sessionProjection).Reducers() {
+		if aggregate.Aggregate != org.AggregateType {
+			continue
+		}
+		for _, reducer := range aggregate.EventReducers {
+			if reducer.Event == org.OrgRemovedEventType {
+				reduce = reducer.Reduce
+			}
+		}
+	}
+	require.NotNil(t, reduce, "org removed is not reduced")
+
+	// the sessions are owned by the instance, the org of the user is stored as user resource owner
+	event := getEvent(testEvent(org.OrgRemovedEventType, org.AggregateType, nil), org.OrgRemovedEventMapper)(t)
+	stmt, err := reduce(event)
+	require.NoError(t, err)
+	assert.Equal(t, eventstore.AggregateType(org.AggregateType), stmt.AggregateType)
+
+	executer := new(recordingExecuter)
+	require.NoError(t, stmt.Execute(executer, SessionsProjectionTable))
+	// the statement is executed in a savepoint
+	require.Len(t, executer.stmts, 3)
+	assert.Equal(t, "DELETE FROM projections.sessions9 WHERE (instance_id = $1) AND (user_resource_owner = $2)", executer.stmts[1])
+	assert.Equal(t, []interface{}{"instance-id", "agg-id"}, executer.args[1])
+}
+
+func TestSessionProjection_Init(t *testing.T) {
+	executer := new(recordingExecuter)
+	for _, execute := range new(sessionProjection).Init().Executes {
+		_, err := execute(executer, SessionsProjectionTable)
+		require.NoError(t, err)
+	}
+	stmts := strings.Join(executer.stmts, "\n")
+	assert.Contains(t, stmts, "CREATE INDEX IF NOT EXISTS sessions9_user_id_idx ON projections.sessions9 (instance_id,user_id);")
+	assert.Contains(t, stmts, "CREATE INDEX IF NOT EXISTS sessions9_creator_idx ON projections.sessions9 (instance_id,creator);")
+	assert.Contains(t, stmts, "CREATE INDEX IF NOT EXISTS sessions9_token_id_idx ON projections.sessions9 (instance_id,token_id);")
+}
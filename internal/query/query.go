@@ -11,6 +11,7 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/call"
 	sd "github.com/zitadel/zitadel/internal/config/systemdefaults"
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/database"
@@ -38,6 +39,8 @@ type Queries struct {
 	zitadelRoles                        []authz.RoleMapping
 	multifactors                        domain.MultifactorConfigs
 	defaultAuditLogRetention            time.Duration
+	// orgDomainCache caches the orgs by domain, disabled if nil
+	orgDomainCache *orgDomainCache
 }
 
 func StartQueries(
@@ -53,6 +56,7 @@ func StartQueries(
 	defaultAuditLogRetention time.Duration,
 	systemAPIUsers map[string]*authz.SystemAPIUser,
 	startProjections bool,
+	opts ...QueriesOption,
 ) (repo *Queries, err error) {
 	repo = &Queries{
 		eventstore:                          es,
@@ -73,12 +77,22 @@ func StartQueries(
 		defaultAuditLogRetention: defaultAuditLogRetention,
 	}
 
+	for _, opt := range opts {
+		opt(repo)
+	}
+	if repo.orgDomainCache != nil {
+		projections.OrgsChanged = repo.orgDomainCache.invalidate
+	}
+
 	repo.checkPermission = permissionCheck(repo)
 
 	err = projection.Create(ctx, projectionSqlClient, es, projections, keyEncryptionAlgorithm, certEncryptionAlgorithm, systemAPIUsers)
 	if err != nil {
 		return nil, err
 	}
+	if err = projection.LoadActiveTables(ctx, projectionSqlClient); err != nil {
+		return nil, err
+	}
 	if startProjections {
 		projection.Start(ctx)
 	}
@@ -94,6 +108,28 @@ type prepareDatabase interface {
 	Timetravel(d time.Duration) string
 }
 
+type strongReadKey struct{}
+
+// WithStrongRead forces the queries executed with the returned context
+// to read the latest committed state instead of a possibly stale follower read.
+func WithStrongRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strongReadKey{}, true)
+}
+
+func isStrongRead(ctx context.Context) bool {
+	strong, _ := ctx.Value(strongReadKey{}).(bool)
+	return strong
+}
+
+// timetravel returns the time travel clause of the database for the call
+// or an empty string if a strong read was requested using [WithStrongRead].
+func timetravel(ctx context.Context, db prepareDatabase) string {
+	if isStrongRead(ctx) {
+		return ""
+	}
+	return db.Timetravel(call.Took(ctx))
+}
+
 // cleanStaticQueries removes whitespaces,
 // such as ` `, \t, \n, from queries to improve
 // readability in logs and errors.
@@ -41,6 +41,18 @@ func expectFilterError(err error) expect {
 	}
 }
 
+func expectExists(exists bool) expect {
+	return func(m *mock.MockRepository) {
+		m.ExpectExists(exists)
+	}
+}
+
+func expectExistsError(err error) expect {
+	return func(m *mock.MockRepository) {
+		m.ExpectExistsError(err)
+	}
+}
+
 func eventFromEventPusher(event eventstore.Command) *repository.Event {
 	data, _ := eventstore.EventData(event)
 	return &repository.Event{
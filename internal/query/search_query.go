@@ -10,6 +10,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 )
 
 type SearchResponse struct {
@@ -651,6 +652,9 @@ type table struct {
 	name          string
 	alias         string
 	instanceIDCol string
+	// active is set if the table of the projection can be swapped,
+	// the name of the active table is used instead of name
+	active *handler.ActiveTable
 }
 
 func (t table) setAlias(a string) table {
@@ -658,22 +662,30 @@ func (t table) setAlias(a string) table {
 	return t
 }
 
+// tableName returns the name of the currently active table
+func (t table) tableName() string {
+	if t.active != nil {
+		return t.active.Name()
+	}
+	return t.name
+}
+
 func (t table) identifier() string {
 	if t.alias == "" {
-		return t.name
+		return t.tableName()
 	}
-	return t.name + " AS " + t.alias
+	return t.tableName() + " AS " + t.alias
 }
 
 func (t table) isZero() bool {
-	return t.name == ""
+	return t.tableName() == ""
 }
 
 func (t table) InstanceIDIdentifier() string {
 	if t.alias != "" {
 		return t.alias + "." + t.instanceIDCol
 	}
-	return t.name + "." + t.instanceIDCol
+	return t.tableName() + "." + t.instanceIDCol
 }
 
 type Column struct {
@@ -686,8 +698,8 @@ func (c Column) identifier() string {
 	if c.table.alias != "" {
 		return c.table.alias + "." + c.name
 	}
-	if c.table.name != "" {
-		return c.table.name + "." + c.name
+	if name := c.table.tableName(); name != "" {
+		return name + "." + c.name
 	}
 	return c.name
 }
@@ -8,6 +8,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 )
 
 var (
@@ -47,6 +48,51 @@ var (
 	}
 )
 
+func TestTable_activeTable(t *testing.T) {
+	activeTable := table{
+		name:          "test_table",
+		instanceIDCol: "instance_id",
+		active:        handler.NewActiveTable("test_table_shadow"),
+	}
+	col := Column{
+		name:  "test_col",
+		table: activeTable,
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "table",
+			got:  activeTable.identifier(),
+			want: "test_table_shadow",
+		},
+		{
+			name: "table with alias",
+			got:  activeTable.setAlias("t").identifier(),
+			want: "test_table_shadow AS t",
+		},
+		{
+			name: "instance id",
+			got:  activeTable.InstanceIDIdentifier(),
+			want: "test_table_shadow.instance_id",
+		},
+		{
+			name: "column",
+			got:  col.identifier(),
+			want: "test_table_shadow.test_col",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("want: %s, got: %s", tt.want, tt.got)
+			}
+		})
+	}
+}
+
 func TestSearchRequest_ToQuery(t *testing.T) {
 	type fields struct {
 		Offset        uint64
@@ -45,6 +45,10 @@ type Session struct {
 	Metadata       map[string][]byte
 	UserAgent      domain.UserAgent
 	Expiration     time.Time
+	// TerminatedAt and TerminationReason are only set on terminated sessions,
+	// which are only returned by [Queries.SearchSessions] if [SessionsSearchQueries.IncludeTerminated] is set
+	TerminatedAt      time.Time
+	TerminationReason domain.SessionTerminationReason
 }
 
 type SessionUserFactor struct {
@@ -70,6 +74,7 @@ type SessionWebAuthNFactor struct {
 
 type SessionTOTPFactor struct {
 	TOTPCheckedAt time.Time
+	UserVerified  bool
 }
 
 type SessionOTPFactor struct {
@@ -79,6 +84,8 @@ type SessionOTPFactor struct {
 type SessionsSearchQueries struct {
 	SearchRequest
 	Queries []SearchQuery
+	// IncludeTerminated also returns the terminated sessions, e.g. to audit why and when sessions ended
+	IncludeTerminated bool
 }
 
 func (q *SessionsSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
@@ -86,12 +93,18 @@ func (q *SessionsSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder
 	for _, q := range q.Queries {
 		query = q.toQuery(query)
 	}
+	if !q.IncludeTerminated {
+		query = query.Where(sq.NotEq{
+			SessionColumnState.identifier(): domain.SessionStateTerminated,
+		})
+	}
 	return query
 }
 
 var (
 	sessionsTable = table{
 		name:          projection.SessionsProjectionTable,
+		active:        projection.SessionsTable,
 		instanceIDCol: projection.SessionColumnInstanceID,
 	}
 	SessionColumnID = Column{
@@ -158,6 +171,10 @@ var (
 		name:  projection.SessionColumnTOTPCheckedAt,
 		table: sessionsTable,
 	}
+	SessionColumnTOTPUserVerified = Column{
+		name:  projection.SessionColumnTOTPUserVerified,
+		table: sessionsTable,
+	}
 	SessionColumnOTPSMSCheckedAt = Column{
 		name:  projection.SessionColumnOTPSMSCheckedAt,
 		table: sessionsTable,
@@ -194,6 +211,14 @@ var (
 		name:  projection.SessionColumnExpiration,
 		table: sessionsTable,
 	}
+	SessionColumnTerminatedAt = Column{
+		name:  projection.SessionColumnTerminatedAt,
+		table: sessionsTable,
+	}
+	SessionColumnTerminationReason = Column{
+		name:  projection.SessionColumnTerminationReason,
+		table: sessionsTable,
+	}
 )
 
 func (q *Queries) SessionByID(ctx context.Context, shouldTriggerBulk bool, id, sessionToken string) (session *Session, err error) {
@@ -213,6 +238,10 @@ func (q *Queries) SessionByID(ctx context.Context, shouldTriggerBulk bool, id, s
 			SessionColumnID.identifier():         id,
 			SessionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
 		},
+	).Where(
+		sq.NotEq{
+			SessionColumnState.identifier(): domain.SessionStateTerminated,
+		},
 	).ToSql()
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "QUERY-dn9JW", "Errors.Query.SQLStatement")
@@ -235,6 +264,41 @@ func (q *Queries) SessionByID(ctx context.Context, shouldTriggerBulk bool, id, s
 	return session, nil
 }
 
+// SessionByTokenID returns the session of the current instance the token was issued for.
+// Tokens replaced by a newer token of the session are not found.
+func (q *Queries) SessionByTokenID(ctx context.Context, tokenID string) (session *Session, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if tokenID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "QUERY-Tk3sd", "Errors.Query.InvalidRequest")
+	}
+
+	query, scan := prepareSessionQuery(ctx, q.client)
+	stmt, args, err := query.Where(
+		sq.Eq{
+			SessionColumnToken.identifier():      tokenID,
+			SessionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		},
+	).Where(
+		sq.NotEq{
+			SessionColumnState.identifier(): domain.SessionStateTerminated,
+		},
+	).ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Tk3se", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryRowContext(ctx, func(row *sql.Row) error {
+		session, _, err = scan(row)
+		return err
+	}, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
 func (q *Queries) SearchSessions(ctx context.Context, queries *SessionsSearchQueries) (sessions *Sessions, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -261,6 +325,98 @@ func (q *Queries) SearchSessions(ctx context.Context, queries *SessionsSearchQue
 	return sessions, err
 }
 
+// SessionsByCreator returns the sessions of the current instance created by the given creator.
+// Terminated sessions are not returned, the newest sessions are returned first.
+func (q *Queries) SessionsByCreator(ctx context.Context, creator string, limit int) (sessions []*Session, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if creator == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "QUERY-Ca3ps", "Errors.Query.InvalidRequest")
+	}
+
+	query, scan := prepareSessionsQuery(ctx, q.client)
+	query = query.
+		Where(sq.Eq{
+			SessionColumnCreator.identifier():    creator,
+			SessionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		}).
+		Where(sq.NotEq{
+			SessionColumnState.identifier(): domain.SessionStateTerminated,
+		}).
+		OrderBy(SessionColumnCreationDate.identifier() + " DESC")
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+	stmt, args, err := query.ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Ca3pt", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		result, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		sessions = result.Sessions
+		return nil
+	}, stmt, args...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Ca3pu", "Errors.Internal")
+	}
+	return sessions, nil
+}
+
+// CountSessionsByUser returns the amount of active sessions of the current instance per user.
+// Users without active sessions are returned with a count of 0.
+func (q *Queries) CountSessionsByUser(ctx context.Context, userIDs []string) (counts map[string]uint64, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	counts = make(map[string]uint64, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+	for _, userID := range userIDs {
+		counts[userID] = 0
+	}
+
+	stmt, args, err := sq.Select(
+		SessionColumnUserID.identifier(),
+		"COUNT(*)",
+	).
+		From(sessionsTable.identifier()).
+		Where(sq.Eq{
+			SessionColumnUserID.identifier():     userIDs,
+			SessionColumnState.identifier():      domain.SessionStateActive,
+			SessionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		}).
+		GroupBy(SessionColumnUserID.identifier()).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Cs8nu", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			var (
+				userID string
+				count  uint64
+			)
+			if err := rows.Scan(&userID, &count); err != nil {
+				return err
+			}
+			counts[userID] = count
+		}
+		return rows.Close()
+	}, stmt, args...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Cs8nv", "Errors.Internal")
+	}
+	return counts, nil
+}
+
 func NewSessionIDsSearchQuery(ids []string) (SearchQuery, error) {
 	list := make([]interface{}, len(ids))
 	for i, value := range ids {
@@ -281,6 +437,103 @@ func NewCreationDateQuery(datetime time.Time, compare TimestampComparison) (Sear
 	return NewTimestampQuery(SessionColumnCreationDate, datetime, compare)
 }
 
+type SessionAuthFactor int
+
+const (
+	SessionAuthFactorUser SessionAuthFactor = iota
+	SessionAuthFactorPassword
+	SessionAuthFactorIntent
+	SessionAuthFactorWebAuthN
+	SessionAuthFactorTOTP
+	SessionAuthFactorOTPSMS
+	SessionAuthFactorOTPEmail
+)
+
+func (f SessionAuthFactor) checkedAtColumn() (Column, bool) {
+	switch f {
+	case SessionAuthFactorUser:
+		return SessionColumnUserCheckedAt, true
+	case SessionAuthFactorPassword:
+		return SessionColumnPasswordCheckedAt, true
+	case SessionAuthFactorIntent:
+		return SessionColumnIntentCheckedAt, true
+	case SessionAuthFactorWebAuthN:
+		return SessionColumnWebAuthNCheckedAt, true
+	case SessionAuthFactorTOTP:
+		return SessionColumnTOTPCheckedAt, true
+	case SessionAuthFactorOTPSMS:
+		return SessionColumnOTPSMSCheckedAt, true
+	case SessionAuthFactorOTPEmail:
+		return SessionColumnOTPEmailCheckedAt, true
+	}
+	return Column{}, false
+}
+
+// SessionAuthFactorCheck requires the factor to be checked,
+// after CheckedAfter if it's set.
+type SessionAuthFactorCheck struct {
+	Factor       SessionAuthFactor
+	CheckedAfter time.Time
+}
+
+type SessionAuthFactorsMatch int
+
+const (
+	// SessionAuthFactorsAllOf requires all checks to be satisfied
+	SessionAuthFactorsAllOf SessionAuthFactorsMatch = iota
+	// SessionAuthFactorsAnyOf requires at least one check to be satisfied
+	SessionAuthFactorsAnyOf
+)
+
+// AuthFactorsQuery filters sessions by their checked factors,
+// e.g. sessions where the password and a passkey were checked after a point in time.
+type AuthFactorsQuery struct {
+	match  SessionAuthFactorsMatch
+	checks []SessionAuthFactorCheck
+}
+
+func NewAuthFactorsQuery(match SessionAuthFactorsMatch, checks ...SessionAuthFactorCheck) (*AuthFactorsQuery, error) {
+	if len(checks) == 0 {
+		return nil, ErrEmptyValues
+	}
+	if match != SessionAuthFactorsAllOf && match != SessionAuthFactorsAnyOf {
+		return nil, ErrInvalidCompare
+	}
+	for _, check := range checks {
+		if _, ok := check.Factor.checkedAtColumn(); !ok {
+			return nil, ErrMissingColumn
+		}
+	}
+	return &AuthFactorsQuery{
+		match:  match,
+		checks: checks,
+	}, nil
+}
+
+func (q *AuthFactorsQuery) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(q.comp())
+}
+
+func (q *AuthFactorsQuery) comp() sq.Sqlizer {
+	conditions := make([]sq.Sqlizer, len(q.checks))
+	for i, check := range q.checks {
+		col, _ := check.Factor.checkedAtColumn()
+		if check.CheckedAfter.IsZero() {
+			conditions[i] = sq.NotEq{col.identifier(): nil}
+			continue
+		}
+		conditions[i] = sq.Gt{col.identifier(): check.CheckedAfter}
+	}
+	if q.match == SessionAuthFactorsAnyOf {
+		return sq.Or(conditions)
+	}
+	return sq.And(conditions)
+}
+
+func (q *AuthFactorsQuery) Col() Column {
+	return Column{}
+}
+
 func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Row) (*Session, string, error)) {
 	return sq.Select(
 			SessionColumnID.identifier(),
@@ -300,6 +553,7 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 			SessionColumnWebAuthNCheckedAt.identifier(),
 			SessionColumnWebAuthNUserVerified.identifier(),
 			SessionColumnTOTPCheckedAt.identifier(),
+			SessionColumnTOTPUserVerified.identifier(),
 			SessionColumnOTPSMSCheckedAt.identifier(),
 			SessionColumnOTPEmailCheckedAt.identifier(),
 			SessionColumnMetadata.identifier(),
@@ -327,6 +581,7 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 				webAuthNCheckedAt   sql.NullTime
 				webAuthNUserPresent sql.NullBool
 				totpCheckedAt       sql.NullTime
+				totpUserVerified    sql.NullBool
 				otpSMSCheckedAt     sql.NullTime
 				otpEmailCheckedAt   sql.NullTime
 				metadata            database.Map[[]byte]
@@ -354,6 +609,7 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 				&webAuthNCheckedAt,
 				&webAuthNUserPresent,
 				&totpCheckedAt,
+				&totpUserVerified,
 				&otpSMSCheckedAt,
 				&otpEmailCheckedAt,
 				&metadata,
@@ -382,6 +638,7 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 			session.WebAuthNFactor.WebAuthNCheckedAt = webAuthNCheckedAt.Time
 			session.WebAuthNFactor.UserVerified = webAuthNUserPresent.Bool
 			session.TOTPFactor.TOTPCheckedAt = totpCheckedAt.Time
+			session.TOTPFactor.UserVerified = totpUserVerified.Bool
 			session.OTPSMSFactor.OTPCheckedAt = otpSMSCheckedAt.Time
 			session.OTPEmailFactor.OTPCheckedAt = otpEmailCheckedAt.Time
 			session.Metadata = metadata
@@ -413,10 +670,13 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 			SessionColumnWebAuthNCheckedAt.identifier(),
 			SessionColumnWebAuthNUserVerified.identifier(),
 			SessionColumnTOTPCheckedAt.identifier(),
+			SessionColumnTOTPUserVerified.identifier(),
 			SessionColumnOTPSMSCheckedAt.identifier(),
 			SessionColumnOTPEmailCheckedAt.identifier(),
 			SessionColumnMetadata.identifier(),
 			SessionColumnExpiration.identifier(),
+			SessionColumnTerminatedAt.identifier(),
+			SessionColumnTerminationReason.identifier(),
 			countColumn.identifier(),
 		).From(sessionsTable.identifier()).
 			LeftJoin(join(LoginNameUserIDCol, SessionColumnUserID)).
@@ -439,10 +699,13 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 					webAuthNCheckedAt   sql.NullTime
 					webAuthNUserPresent sql.NullBool
 					totpCheckedAt       sql.NullTime
+					totpUserVerified    sql.NullBool
 					otpSMSCheckedAt     sql.NullTime
 					otpEmailCheckedAt   sql.NullTime
 					metadata            database.Map[[]byte]
 					expiration          sql.NullTime
+					terminatedAt        sql.NullTime
+					terminationReason   sql.NullString
 				)
 
 				err := rows.Scan(
@@ -463,10 +726,13 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 					&webAuthNCheckedAt,
 					&webAuthNUserPresent,
 					&totpCheckedAt,
+					&totpUserVerified,
 					&otpSMSCheckedAt,
 					&otpEmailCheckedAt,
 					&metadata,
 					&expiration,
+					&terminatedAt,
+					&terminationReason,
 					&sessions.Count,
 				)
 
@@ -483,10 +749,13 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 				session.WebAuthNFactor.WebAuthNCheckedAt = webAuthNCheckedAt.Time
 				session.WebAuthNFactor.UserVerified = webAuthNUserPresent.Bool
 				session.TOTPFactor.TOTPCheckedAt = totpCheckedAt.Time
+				session.TOTPFactor.UserVerified = totpUserVerified.Bool
 				session.OTPSMSFactor.OTPCheckedAt = otpSMSCheckedAt.Time
 				session.OTPEmailFactor.OTPCheckedAt = otpEmailCheckedAt.Time
 				session.Metadata = metadata
 				session.Expiration = expiration.Time
+				session.TerminatedAt = terminatedAt.Time
+				session.TerminationReason = domain.SessionTerminationReason(terminationReason.String)
 
 				sessions.Sessions = append(sessions.Sessions, session)
 			}
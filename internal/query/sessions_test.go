@@ -10,73 +10,82 @@ import (
 	"net/http"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/muhlemmer/gu"
 	"github.com/stretchr/testify/require"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
-	expectedSessionQuery = regexp.QuoteMeta(`SELECT projections.sessions8.id,` +
-		` projections.sessions8.creation_date,` +
-		` projections.sessions8.change_date,` +
-		` projections.sessions8.sequence,` +
-		` projections.sessions8.state,` +
-		` projections.sessions8.resource_owner,` +
-		` projections.sessions8.creator,` +
-		` projections.sessions8.user_id,` +
-		` projections.sessions8.user_resource_owner,` +
-		` projections.sessions8.user_checked_at,` +
+	expectedSessionQuery = regexp.QuoteMeta(`SELECT projections.sessions9.id,` +
+		` projections.sessions9.creation_date,` +
+		` projections.sessions9.change_date,` +
+		` projections.sessions9.sequence,` +
+		` projections.sessions9.state,` +
+		` projections.sessions9.resource_owner,` +
+		` projections.sessions9.creator,` +
+		` projections.sessions9.user_id,` +
+		` projections.sessions9.user_resource_owner,` +
+		` projections.sessions9.user_checked_at,` +
 		` projections.login_names3.login_name,` +
 		` projections.users11_humans.display_name,` +
-		` projections.sessions8.password_checked_at,` +
-		` projections.sessions8.intent_checked_at,` +
-		` projections.sessions8.webauthn_checked_at,` +
-		` projections.sessions8.webauthn_user_verified,` +
-		` projections.sessions8.totp_checked_at,` +
-		` projections.sessions8.otp_sms_checked_at,` +
-		` projections.sessions8.otp_email_checked_at,` +
-		` projections.sessions8.metadata,` +
-		` projections.sessions8.token_id,` +
-		` projections.sessions8.user_agent_fingerprint_id,` +
-		` projections.sessions8.user_agent_ip,` +
-		` projections.sessions8.user_agent_description,` +
-		` projections.sessions8.user_agent_header,` +
-		` projections.sessions8.expiration` +
-		` FROM projections.sessions8` +
-		` LEFT JOIN projections.login_names3 ON projections.sessions8.user_id = projections.login_names3.user_id AND projections.sessions8.instance_id = projections.login_names3.instance_id` +
-		` LEFT JOIN projections.users11_humans ON projections.sessions8.user_id = projections.users11_humans.user_id AND projections.sessions8.instance_id = projections.users11_humans.instance_id` +
-		` LEFT JOIN projections.users11 ON projections.sessions8.user_id = projections.users11.id AND projections.sessions8.instance_id = projections.users11.instance_id` +
+		` projections.sessions9.password_checked_at,` +
+		` projections.sessions9.intent_checked_at,` +
+		` projections.sessions9.webauthn_checked_at,` +
+		` projections.sessions9.webauthn_user_verified,` +
+		` projections.sessions9.totp_checked_at,` +
+		` projections.sessions9.totp_user_verified,` +
+		` projections.sessions9.otp_sms_checked_at,` +
+		` projections.sessions9.otp_email_checked_at,` +
+		` projections.sessions9.metadata,` +
+		` projections.sessions9.token_id,` +
+		` projections.sessions9.user_agent_fingerprint_id,` +
+		` projections.sessions9.user_agent_ip,` +
+		` projections.sessions9.user_agent_description,` +
+		` projections.sessions9.user_agent_header,` +
+		` projections.sessions9.expiration` +
+		` FROM projections.sessions9` +
+		` LEFT JOIN projections.login_names3 ON projections.sessions9.user_id = projections.login_names3.user_id AND projections.sessions9.instance_id = projections.login_names3.instance_id` +
+		` LEFT JOIN projections.users11_humans ON projections.sessions9.user_id = projections.users11_humans.user_id AND projections.sessions9.instance_id = projections.users11_humans.instance_id` +
+		` LEFT JOIN projections.users11 ON projections.sessions9.user_id = projections.users11.id AND projections.sessions9.instance_id = projections.users11.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms'`)
-	expectedSessionsQuery = regexp.QuoteMeta(`SELECT projections.sessions8.id,` +
-		` projections.sessions8.creation_date,` +
-		` projections.sessions8.change_date,` +
-		` projections.sessions8.sequence,` +
-		` projections.sessions8.state,` +
-		` projections.sessions8.resource_owner,` +
-		` projections.sessions8.creator,` +
-		` projections.sessions8.user_id,` +
-		` projections.sessions8.user_resource_owner,` +
-		` projections.sessions8.user_checked_at,` +
+	expectedSessionsQuery = regexp.QuoteMeta(`SELECT projections.sessions9.id,` +
+		` projections.sessions9.creation_date,` +
+		` projections.sessions9.change_date,` +
+		` projections.sessions9.sequence,` +
+		` projections.sessions9.state,` +
+		` projections.sessions9.resource_owner,` +
+		` projections.sessions9.creator,` +
+		` projections.sessions9.user_id,` +
+		` projections.sessions9.user_resource_owner,` +
+		` projections.sessions9.user_checked_at,` +
 		` projections.login_names3.login_name,` +
 		` projections.users11_humans.display_name,` +
-		` projections.sessions8.password_checked_at,` +
-		` projections.sessions8.intent_checked_at,` +
-		` projections.sessions8.webauthn_checked_at,` +
-		` projections.sessions8.webauthn_user_verified,` +
-		` projections.sessions8.totp_checked_at,` +
-		` projections.sessions8.otp_sms_checked_at,` +
-		` projections.sessions8.otp_email_checked_at,` +
-		` projections.sessions8.metadata,` +
-		` projections.sessions8.expiration,` +
+		` projections.sessions9.password_checked_at,` +
+		` projections.sessions9.intent_checked_at,` +
+		` projections.sessions9.webauthn_checked_at,` +
+		` projections.sessions9.webauthn_user_verified,` +
+		` projections.sessions9.totp_checked_at,` +
+		` projections.sessions9.totp_user_verified,` +
+		` projections.sessions9.otp_sms_checked_at,` +
+		` projections.sessions9.otp_email_checked_at,` +
+		` projections.sessions9.metadata,` +
+		` projections.sessions9.expiration,` +
+		` projections.sessions9.terminated_at,` +
+		` projections.sessions9.termination_reason,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.sessions8` +
-		` LEFT JOIN projections.login_names3 ON projections.sessions8.user_id = projections.login_names3.user_id AND projections.sessions8.instance_id = projections.login_names3.instance_id` +
-		` LEFT JOIN projections.users11_humans ON projections.sessions8.user_id = projections.users11_humans.user_id AND projections.sessions8.instance_id = projections.users11_humans.instance_id` +
-		` LEFT JOIN projections.users11 ON projections.sessions8.user_id = projections.users11.id AND projections.sessions8.instance_id = projections.users11.instance_id` +
+		` FROM projections.sessions9` +
+		` LEFT JOIN projections.login_names3 ON projections.sessions9.user_id = projections.login_names3.user_id AND projections.sessions9.instance_id = projections.login_names3.instance_id` +
+		` LEFT JOIN projections.users11_humans ON projections.sessions9.user_id = projections.users11_humans.user_id AND projections.sessions9.instance_id = projections.users11_humans.instance_id` +
+		` LEFT JOIN projections.users11 ON projections.sessions9.user_id = projections.users11.id AND projections.sessions9.instance_id = projections.users11.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms'`)
 
 	sessionCols = []string{
@@ -97,6 +106,7 @@ var (
 		"webauthn_checked_at",
 		"webauthn_user_verified",
 		"totp_checked_at",
+		"totp_user_verified",
 		"otp_sms_checked_at",
 		"otp_email_checked_at",
 		"metadata",
@@ -126,10 +136,13 @@ var (
 		"webauthn_checked_at",
 		"webauthn_user_verified",
 		"totp_checked_at",
+		"totp_user_verified",
 		"otp_sms_checked_at",
 		"otp_email_checked_at",
 		"metadata",
 		"expiration",
+		"terminated_at",
+		"termination_reason",
 		"count",
 	}
 )
@@ -157,6 +170,69 @@ func Test_SessionsPrepare(t *testing.T) {
 			},
 			object: &Sessions{Sessions: []*Session{}},
 		},
+		{
+			name:    "prepareSessionsQuery terminated session",
+			prepare: prepareSessionsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedSessionsQuery,
+					sessionsCols,
+					[][]driver.Value{
+						{
+							"session-id",
+							testNow,
+							testNow,
+							uint64(20211109),
+							domain.SessionStateTerminated,
+							"ro",
+							"creator",
+							"user-id",
+							"resourceOwner",
+							testNow,
+							"login-name",
+							"display-name",
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							testNow,
+							"logout",
+						},
+					},
+				),
+			},
+			object: &Sessions{
+				SearchResponse: SearchResponse{
+					Count: 1,
+				},
+				Sessions: []*Session{
+					{
+						ID:            "session-id",
+						CreationDate:  testNow,
+						ChangeDate:    testNow,
+						Sequence:      20211109,
+						State:         domain.SessionStateTerminated,
+						ResourceOwner: "ro",
+						Creator:       "creator",
+						UserFactor: SessionUserFactor{
+							UserID:        "user-id",
+							UserCheckedAt: testNow,
+							LoginName:     "login-name",
+							DisplayName:   "display-name",
+							ResourceOwner: "resourceOwner",
+						},
+						TerminatedAt:      testNow,
+						TerminationReason: domain.SessionTerminationReasonLogout,
+					},
+				},
+			},
+		},
 		{
 			name:    "prepareSessionQuery",
 			prepare: prepareSessionsQuery,
@@ -183,10 +259,13 @@ func Test_SessionsPrepare(t *testing.T) {
 							testNow,
 							true,
 							testNow,
+							true,
 							testNow,
 							testNow,
 							[]byte(`{"key": "dmFsdWU="}`),
 							testNow,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -223,6 +302,7 @@ func Test_SessionsPrepare(t *testing.T) {
 						},
 						TOTPFactor: SessionTOTPFactor{
 							TOTPCheckedAt: testNow,
+							UserVerified:  true,
 						},
 						OTPSMSFactor: SessionOTPFactor{
 							OTPCheckedAt: testNow,
@@ -264,10 +344,13 @@ func Test_SessionsPrepare(t *testing.T) {
 							testNow,
 							true,
 							testNow,
+							true,
 							testNow,
 							testNow,
 							[]byte(`{"key": "dmFsdWU="}`),
 							testNow,
+							nil,
+							nil,
 						},
 						{
 							"session-id2",
@@ -287,10 +370,13 @@ func Test_SessionsPrepare(t *testing.T) {
 							testNow,
 							false,
 							testNow,
+							false,
 							testNow,
 							testNow,
 							[]byte(`{"key": "dmFsdWU="}`),
 							testNow,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -327,6 +413,7 @@ func Test_SessionsPrepare(t *testing.T) {
 						},
 						TOTPFactor: SessionTOTPFactor{
 							TOTPCheckedAt: testNow,
+							UserVerified:  true,
 						},
 						OTPSMSFactor: SessionOTPFactor{
 							OTPCheckedAt: testNow,
@@ -366,6 +453,7 @@ func Test_SessionsPrepare(t *testing.T) {
 						},
 						TOTPFactor: SessionTOTPFactor{
 							TOTPCheckedAt: testNow,
+							UserVerified:  false,
 						},
 						OTPSMSFactor: SessionOTPFactor{
 							OTPCheckedAt: testNow,
@@ -460,6 +548,7 @@ func Test_SessionPrepare(t *testing.T) {
 						testNow,
 						true,
 						testNow,
+						true,
 						testNow,
 						testNow,
 						[]byte(`{"key": "dmFsdWU="}`),
@@ -499,6 +588,7 @@ func Test_SessionPrepare(t *testing.T) {
 				},
 				TOTPFactor: SessionTOTPFactor{
 					TOTPCheckedAt: testNow,
+					UserVerified:  true,
 				},
 				OTPSMSFactor: SessionOTPFactor{
 					OTPCheckedAt: testNow,
@@ -553,3 +643,491 @@ func prepareSessionQueryTesting(t *testing.T, token string) func(context.Context
 		}
 	}
 }
+
+func TestQueries_SessionsByCreator(t *testing.T) {
+	expectedQuery := expectedSessionsQuery +
+		regexp.QuoteMeta(` WHERE projections.sessions9.creator = $1 AND projections.sessions9.instance_id = $2 AND projections.sessions9.state <> $3 ORDER BY projections.sessions9.creation_date DESC`)
+	sessionRow := func(id, creator string) []driver.Value {
+		return []driver.Value{
+			id,
+			testNow,
+			testNow,
+			uint64(20211109),
+			domain.SessionStateActive,
+			"ro",
+			creator,
+			"user-id",
+			"resourceOwner",
+			testNow,
+			"login-name",
+			"display-name",
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		}
+	}
+	session := func(id, creator string) *Session {
+		return &Session{
+			ID:            id,
+			CreationDate:  testNow,
+			ChangeDate:    testNow,
+			Sequence:      20211109,
+			State:         domain.SessionStateActive,
+			ResourceOwner: "ro",
+			Creator:       creator,
+			UserFactor: SessionUserFactor{
+				UserID:        "user-id",
+				UserCheckedAt: testNow,
+				LoginName:     "login-name",
+				DisplayName:   "display-name",
+				ResourceOwner: "resourceOwner",
+			},
+		}
+	}
+	type args struct {
+		creator string
+		limit   int
+	}
+	type want struct {
+		sqlExpectations sqlExpectation
+		sessions        []*Session
+		err             func(error) bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "no creator",
+			args: args{
+				creator: "",
+			},
+			want: want{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "sessions of creator",
+			args: args{
+				creator: "creator1",
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedQuery,
+					sessionsCols,
+					[][]driver.Value{
+						sessionRow("session-1", "creator1"),
+						sessionRow("session-3", "creator1"),
+					},
+					"creator1", "instance-id", domain.SessionStateTerminated,
+				),
+				sessions: []*Session{
+					session("session-1", "creator1"),
+					session("session-3", "creator1"),
+				},
+			},
+		},
+		{
+			name: "other creator with limit",
+			args: args{
+				creator: "creator2",
+				limit:   1,
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedQuery+regexp.QuoteMeta(` LIMIT 1`),
+					sessionsCols,
+					[][]driver.Value{
+						sessionRow("session-2", "creator2"),
+					},
+					"creator2", "instance-id", domain.SessionStateTerminated,
+				),
+				sessions: []*Session{
+					session("session-2", "creator2"),
+				},
+			},
+		},
+		{
+			name: "sql error",
+			args: args{
+				creator: "creator1",
+			},
+			want: want{
+				sqlExpectations: mockQueryErr(
+					expectedQuery,
+					sql.ErrConnDone,
+					"creator1", "instance-id", domain.SessionStateTerminated,
+				),
+				err: zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+			if tt.want.sqlExpectations != nil {
+				tt.want.sqlExpectations(mock)
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			sessions, err := q.SessionsByCreator(authz.WithInstanceID(context.Background(), "instance-id"), tt.args.creator, tt.args.limit)
+			if tt.want.err != nil {
+				require.True(t, tt.want.err(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.sessions, sessions)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueries_SessionByTokenID(t *testing.T) {
+	expectedQuery := expectedSessionQuery +
+		regexp.QuoteMeta(` WHERE projections.sessions9.instance_id = $1 AND projections.sessions9.token_id = $2 AND projections.sessions9.state <> $3`)
+	sessionRow := []driver.Value{
+		"session-id",
+		testNow,
+		testNow,
+		uint64(20211109),
+		domain.SessionStateActive,
+		"ro",
+		"creator",
+		"user-id",
+		"resourceOwner",
+		testNow,
+		"login-name",
+		"display-name",
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		"rotated-token-id",
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	}
+	type want struct {
+		sqlExpectations sqlExpectation
+		session         *Session
+		err             func(error) bool
+	}
+	tests := []struct {
+		name    string
+		tokenID string
+		want    want
+	}{
+		{
+			name:    "no token id",
+			tokenID: "",
+			want: want{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name:    "current token",
+			tokenID: "rotated-token-id",
+			want: want{
+				sqlExpectations: mockQuery(
+					expectedQuery,
+					sessionCols,
+					sessionRow,
+					"instance-id", "rotated-token-id", domain.SessionStateTerminated,
+				),
+				session: &Session{
+					ID:            "session-id",
+					CreationDate:  testNow,
+					ChangeDate:    testNow,
+					Sequence:      20211109,
+					State:         domain.SessionStateActive,
+					ResourceOwner: "ro",
+					Creator:       "creator",
+					UserFactor: SessionUserFactor{
+						UserID:        "user-id",
+						UserCheckedAt: testNow,
+						LoginName:     "login-name",
+						DisplayName:   "display-name",
+						ResourceOwner: "resourceOwner",
+					},
+				},
+			},
+		},
+		{
+			name:    "rotated token",
+			tokenID: "old-token-id",
+			want: want{
+				sqlExpectations: mockQueryScanErr(
+					expectedQuery,
+					sessionCols,
+					nil,
+					"instance-id", "old-token-id", domain.SessionStateTerminated,
+				),
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			name:    "sql error",
+			tokenID: "rotated-token-id",
+			want: want{
+				sqlExpectations: mockQueryErr(
+					expectedQuery,
+					sql.ErrConnDone,
+					"instance-id", "rotated-token-id", domain.SessionStateTerminated,
+				),
+				err: zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+			if tt.want.sqlExpectations != nil {
+				tt.want.sqlExpectations(mock)
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			session, err := q.SessionByTokenID(authz.WithInstanceID(context.Background(), "instance-id"), tt.tokenID)
+			if tt.want.err != nil {
+				require.True(t, tt.want.err(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.session, session)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNewAuthFactorsQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		match   SessionAuthFactorsMatch
+		checks  []SessionAuthFactorCheck
+		wantErr error
+	}{
+		{
+			name:    "no checks",
+			match:   SessionAuthFactorsAllOf,
+			wantErr: ErrEmptyValues,
+		},
+		{
+			name:    "unknown match",
+			match:   SessionAuthFactorsAnyOf + 1,
+			checks:  []SessionAuthFactorCheck{{Factor: SessionAuthFactorPassword}},
+			wantErr: ErrInvalidCompare,
+		},
+		{
+			name:    "unknown factor",
+			match:   SessionAuthFactorsAllOf,
+			checks:  []SessionAuthFactorCheck{{Factor: SessionAuthFactorOTPEmail + 1}},
+			wantErr: ErrMissingColumn,
+		},
+		{
+			name:   "ok",
+			match:  SessionAuthFactorsAnyOf,
+			checks: []SessionAuthFactorCheck{{Factor: SessionAuthFactorPassword}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthFactorsQuery(tt.match, tt.checks...)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuthFactorsQuery_toQuery(t *testing.T) {
+	checkedAfter := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		match     SessionAuthFactorsMatch
+		checks    []SessionAuthFactorCheck
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:  "all of",
+			match: SessionAuthFactorsAllOf,
+			checks: []SessionAuthFactorCheck{
+				{Factor: SessionAuthFactorPassword},
+				{Factor: SessionAuthFactorWebAuthN},
+			},
+			wantQuery: "SELECT id FROM projections.sessions9 WHERE (projections.sessions9.password_checked_at IS NOT NULL AND projections.sessions9.webauthn_checked_at IS NOT NULL)",
+		},
+		{
+			name:  "all of checked after",
+			match: SessionAuthFactorsAllOf,
+			checks: []SessionAuthFactorCheck{
+				{Factor: SessionAuthFactorPassword, CheckedAfter: checkedAfter},
+				{Factor: SessionAuthFactorWebAuthN, CheckedAfter: checkedAfter},
+			},
+			wantQuery: "SELECT id FROM projections.sessions9 WHERE (projections.sessions9.password_checked_at > ? AND projections.sessions9.webauthn_checked_at > ?)",
+			wantArgs:  []any{checkedAfter, checkedAfter},
+		},
+		{
+			name:  "any of",
+			match: SessionAuthFactorsAnyOf,
+			checks: []SessionAuthFactorCheck{
+				{Factor: SessionAuthFactorTOTP, CheckedAfter: checkedAfter},
+				{Factor: SessionAuthFactorOTPSMS},
+				{Factor: SessionAuthFactorOTPEmail},
+			},
+			wantQuery: "SELECT id FROM projections.sessions9 WHERE (projections.sessions9.totp_checked_at > ? OR projections.sessions9.otp_sms_checked_at IS NOT NULL OR projections.sessions9.otp_email_checked_at IS NOT NULL)",
+			wantArgs:  []any{checkedAfter},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewAuthFactorsQuery(tt.match, tt.checks...)
+			require.NoError(t, err)
+
+			query, args, err := q.toQuery(sq.Select("id").From(sessionsTable.identifier())).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantQuery, query)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestSessionsSearchQueries_toQuery(t *testing.T) {
+	tests := []struct {
+		name              string
+		includeTerminated bool
+		wantQuery         string
+		wantArgs          []any
+	}{
+		{
+			name:      "terminated sessions excluded by default",
+			wantQuery: "SELECT id FROM projections.sessions9 WHERE projections.sessions9.state <> ?",
+			wantArgs:  []any{domain.SessionStateTerminated},
+		},
+		{
+			name:              "terminated sessions included",
+			includeTerminated: true,
+			wantQuery:         "SELECT id FROM projections.sessions9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &SessionsSearchQueries{IncludeTerminated: tt.includeTerminated}
+
+			query, args, err := q.toQuery(sq.Select("id").From(sessionsTable.identifier())).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantQuery, query)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestQueries_CountSessionsByUser(t *testing.T) {
+	expectedQuery := regexp.QuoteMeta(`SELECT projections.sessions9.user_id, COUNT(*)` +
+		` FROM projections.sessions9` +
+		` WHERE projections.sessions9.instance_id = $1 AND projections.sessions9.state = $2 AND projections.sessions9.user_id IN ($3,$4,$5)` +
+		` GROUP BY projections.sessions9.user_id`)
+	countCols := []string{"user_id", "sessions"}
+	type want struct {
+		sqlExpectations sqlExpectation
+		counts          map[string]uint64
+		err             func(error) bool
+	}
+	tests := []struct {
+		name    string
+		userIDs []string
+		want    want
+	}{
+		{
+			name: "no users",
+			want: want{
+				counts: map[string]uint64{},
+			},
+		},
+		{
+			name:    "users with none, one and multiple sessions",
+			userIDs: []string{"user-none", "user-one", "user-multiple"},
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedQuery,
+					countCols,
+					[][]driver.Value{
+						{"user-one", uint64(1)},
+						{"user-multiple", uint64(3)},
+					},
+					"instance-id", domain.SessionStateActive, "user-none", "user-one", "user-multiple",
+				),
+				counts: map[string]uint64{
+					"user-none":     0,
+					"user-one":      1,
+					"user-multiple": 3,
+				},
+			},
+		},
+		{
+			name:    "sql error",
+			userIDs: []string{"user-none", "user-one", "user-multiple"},
+			want: want{
+				sqlExpectations: mockQueryErr(
+					expectedQuery,
+					sql.ErrConnDone,
+					"instance-id", domain.SessionStateActive, "user-none", "user-one", "user-multiple",
+				),
+				err: zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+			if tt.want.sqlExpectations != nil {
+				tt.want.sqlExpectations(mock)
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			counts, err := q.CountSessionsByUser(authz.WithInstanceID(context.Background(), "instance-id"), tt.userIDs)
+			if tt.want.err != nil {
+				require.True(t, tt.want.err(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.counts, counts)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
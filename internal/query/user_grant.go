@@ -215,6 +215,7 @@ var (
 	}
 	GrantedOrgsTable = table{
 		name:          projection.OrgProjectionTable,
+		active:        projection.OrgsTable,
 		alias:         "granted_orgs",
 		instanceIDCol: projection.OrgColumnInstanceID,
 	}
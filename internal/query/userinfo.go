@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	_ "embed"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
@@ -36,11 +37,16 @@ func TriggerOIDCUserInfoProjections(ctx context.Context) {
 //go:embed embed/userinfo_by_id.sql
 var oidcUserInfoQuery string
 
+// userInfoQuery returns the user info query reading the active orgs table, see [projection.OrgsTable]
+func userInfoQuery() string {
+	return fmt.Sprintf(oidcUserInfoQuery, projection.OrgsTable.Name())
+}
+
 func (q *Queries) GetOIDCUserInfo(ctx context.Context, userID string, roleAudience []string) (_ *OIDCUserInfo, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
 
-	userInfo, err := database.QueryJSONObject[OIDCUserInfo](ctx, q.client, oidcUserInfoQuery,
+	userInfo, err := database.QueryJSONObject[OIDCUserInfo](ctx, q.client, userInfoQuery(),
 		userID, authz.GetInstance(ctx).InstanceID(), database.TextArray[string](roleAudience),
 	)
 	if errors.Is(err, sql.ErrNoRows) {
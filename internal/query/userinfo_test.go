@@ -48,7 +48,7 @@ var (
 )
 
 func TestQueries_GetOIDCUserInfo(t *testing.T) {
-	expQuery := regexp.QuoteMeta(oidcUserInfoQuery)
+	expQuery := regexp.QuoteMeta(userInfoQuery())
 	type args struct {
 		userID       string
 		roleAudience []string
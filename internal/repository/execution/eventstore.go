@@ -5,4 +5,6 @@ import "github.com/zitadel/zitadel/internal/eventstore"
 func init() {
 	eventstore.RegisterFilterEventMapper(AggregateType, SetEventType, eventstore.GenericEventMapper[SetEvent])
 	eventstore.RegisterFilterEventMapper(AggregateType, RemovedEventType, eventstore.GenericEventMapper[RemovedEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, EnabledEventType, eventstore.GenericEventMapper[EnabledEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, DisabledEventType, eventstore.GenericEventMapper[DisabledEvent])
 }
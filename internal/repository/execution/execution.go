@@ -7,9 +7,11 @@ import (
 )
 
 const (
-	eventTypePrefix  eventstore.EventType = "execution."
-	SetEventType                          = eventTypePrefix + "set"
-	RemovedEventType                      = eventTypePrefix + "removed"
+	eventTypePrefix   eventstore.EventType = "execution."
+	SetEventType                           = eventTypePrefix + "set"
+	RemovedEventType                       = eventTypePrefix + "removed"
+	EnabledEventType                       = eventTypePrefix + "enabled"
+	DisabledEventType                      = eventTypePrefix + "disabled"
 )
 
 type SetEvent struct {
@@ -67,3 +69,47 @@ func NewRemovedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *Remo
 		eventstore.NewBaseEventForPush(ctx, aggregate, RemovedEventType),
 	}
 }
+
+type EnabledEvent struct {
+	*eventstore.BaseEvent `json:"-"`
+}
+
+func (e *EnabledEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = b
+}
+
+func (e *EnabledEvent) Payload() any {
+	return e
+}
+
+func (e *EnabledEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewEnabledEvent(ctx context.Context, aggregate *eventstore.Aggregate) *EnabledEvent {
+	return &EnabledEvent{
+		eventstore.NewBaseEventForPush(ctx, aggregate, EnabledEventType),
+	}
+}
+
+type DisabledEvent struct {
+	*eventstore.BaseEvent `json:"-"`
+}
+
+func (e *DisabledEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = b
+}
+
+func (e *DisabledEvent) Payload() any {
+	return e
+}
+
+func (e *DisabledEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewDisabledEvent(ctx context.Context, aggregate *eventstore.Aggregate) *DisabledEvent {
+	return &DisabledEvent{
+		eventstore.NewBaseEventForPush(ctx, aggregate, DisabledEventType),
+	}
+}
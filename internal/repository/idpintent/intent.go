@@ -18,8 +18,33 @@ const (
 	FailedEventType        = instanceEventTypePrefix + "failed"
 )
 
+// PayloadVersion is the version of the payload layout written by the events of this package.
+// Payloads without version were written before the version was introduced and are decoded as version 0.
+const PayloadVersion uint16 = 1
+
+type payloadVersion struct {
+	Version uint16 `json:"version,omitempty"`
+}
+
+// unmarshalPayload decodes the payload of the event into e depending on the version of the payload.
+// The layout of version 0 equals version 1, a decoder for the legacy layout must be added here
+// if the layout of the payload changes.
+func unmarshalPayload(event eventstore.Event, e any) error {
+	var version payloadVersion
+	if err := event.Unmarshal(&version); err != nil {
+		return err
+	}
+	switch version.Version {
+	case 0, PayloadVersion:
+		return event.Unmarshal(e)
+	default:
+		return zerrors.ThrowInternalf(nil, "IDP-Pv0sn", "unsupported payload version %d", version.Version)
+	}
+}
+
 type StartedEvent struct {
 	eventstore.BaseEvent `json:"-"`
+	Version              uint16 `json:"version,omitempty"`
 
 	SuccessURL *url.URL `json:"successURL"`
 	FailureURL *url.URL `json:"failureURL"`
@@ -39,6 +64,7 @@ func NewStartedEvent(
 			aggregate,
 			StartedEventType,
 		),
+		Version:    PayloadVersion,
 		SuccessURL: successURL,
 		FailureURL: failureURL,
 		IDPID:      idpID,
@@ -58,7 +84,7 @@ func StartedEventMapper(event eventstore.Event) (eventstore.Event, error) {
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}
 
-	err := event.Unmarshal(e)
+	err := unmarshalPayload(event, e)
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "IDP-Sf3f1", "unable to unmarshal event")
 	}
@@ -68,6 +94,7 @@ func StartedEventMapper(event eventstore.Event) (eventstore.Event, error) {
 
 type SucceededEvent struct {
 	eventstore.BaseEvent `json:"-"`
+	Version              uint16 `json:"version,omitempty"`
 
 	IDPUser     []byte `json:"idpUser"`
 	IDPUserID   string `json:"idpUserId,omitempty"`
@@ -94,6 +121,7 @@ func NewSucceededEvent(
 			aggregate,
 			SucceededEventType,
 		),
+		Version:        PayloadVersion,
 		IDPUser:        idpUser,
 		IDPUserID:      idpUserID,
 		IDPUserName:    idpUserName,
@@ -116,7 +144,7 @@ func SucceededEventMapper(event eventstore.Event) (eventstore.Event, error) {
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}
 
-	err := event.Unmarshal(e)
+	err := unmarshalPayload(event, e)
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "IDP-HBreq", "unable to unmarshal event")
 	}
@@ -126,6 +154,7 @@ func SucceededEventMapper(event eventstore.Event) (eventstore.Event, error) {
 
 type SAMLSucceededEvent struct {
 	eventstore.BaseEvent `json:"-"`
+	Version              uint16 `json:"version,omitempty"`
 
 	IDPUser     []byte `json:"idpUser"`
 	IDPUserID   string `json:"idpUserId,omitempty"`
@@ -150,6 +179,7 @@ func NewSAMLSucceededEvent(
 			aggregate,
 			SAMLSucceededEventType,
 		),
+		Version:     PayloadVersion,
 		IDPUser:     idpUser,
 		IDPUserID:   idpUserID,
 		IDPUserName: idpUserName,
@@ -171,7 +201,7 @@ func SAMLSucceededEventMapper(event eventstore.Event) (eventstore.Event, error)
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}
 
-	err := event.Unmarshal(e)
+	err := unmarshalPayload(event, e)
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "IDP-l4tw23y6lq", "unable to unmarshal event")
 	}
@@ -181,6 +211,7 @@ func SAMLSucceededEventMapper(event eventstore.Event) (eventstore.Event, error)
 
 type SAMLRequestEvent struct {
 	eventstore.BaseEvent `json:"-"`
+	Version              uint16 `json:"version,omitempty"`
 
 	RequestID string `json:"requestId"`
 }
@@ -196,6 +227,7 @@ func NewSAMLRequestEvent(
 			aggregate,
 			SAMLRequestEventType,
 		),
+		Version:   PayloadVersion,
 		RequestID: requestID,
 	}
 }
@@ -213,7 +245,7 @@ func SAMLRequestEventMapper(event eventstore.Event) (eventstore.Event, error) {
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}
 
-	err := event.Unmarshal(e)
+	err := unmarshalPayload(event, e)
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "IDP-l85678vwlf", "unable to unmarshal event")
 	}
@@ -223,6 +255,7 @@ func SAMLRequestEventMapper(event eventstore.Event) (eventstore.Event, error) {
 
 type LDAPSucceededEvent struct {
 	eventstore.BaseEvent `json:"-"`
+	Version              uint16 `json:"version,omitempty"`
 
 	IDPUser     []byte `json:"idpUser"`
 	IDPUserID   string `json:"idpUserId,omitempty"`
@@ -247,6 +280,7 @@ func NewLDAPSucceededEvent(
 			aggregate,
 			LDAPSucceededEventType,
 		),
+		Version:         PayloadVersion,
 		IDPUser:         idpUser,
 		IDPUserID:       idpUserID,
 		IDPUserName:     idpUserName,
@@ -268,7 +302,7 @@ func LDAPSucceededEventMapper(event eventstore.Event) (eventstore.Event, error)
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}
 
-	err := event.Unmarshal(e)
+	err := unmarshalPayload(event, e)
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "IDP-HBreq", "unable to unmarshal event")
 	}
@@ -278,6 +312,7 @@ func LDAPSucceededEventMapper(event eventstore.Event) (eventstore.Event, error)
 
 type FailedEvent struct {
 	eventstore.BaseEvent `json:"-"`
+	Version              uint16 `json:"version,omitempty"`
 
 	Reason string `json:"reason,omitempty"`
 }
@@ -293,7 +328,8 @@ func NewFailedEvent(
 			aggregate,
 			FailedEventType,
 		),
-		Reason: reason,
+		Version: PayloadVersion,
+		Reason:  reason,
 	}
 }
 
@@ -310,7 +346,7 @@ func FailedEventMapper(event eventstore.Event) (eventstore.Event, error) {
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}
 
-	err := event.Unmarshal(e)
+	err := unmarshalPayload(event, e)
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "IDP-Sfer3", "unable to unmarshal event")
 	}
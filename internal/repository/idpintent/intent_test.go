@@ -0,0 +1,149 @@
+package idpintent
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestEventMappers_payloadVersion(t *testing.T) {
+	successURL, err := url.Parse("https://example.com/success")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		mapper    func(eventstore.Event) (eventstore.Event, error)
+		eventType eventstore.EventType
+		payload   string
+		want      eventstore.Event
+		wantErr   bool
+	}{
+		{
+			name:      "started v0",
+			mapper:    StartedEventMapper,
+			eventType: StartedEventType,
+			payload:   `{"successURL":{"Scheme":"https","Host":"example.com","Path":"/success"},"idpId":"idp"}`,
+			want:      &StartedEvent{SuccessURL: successURL, IDPID: "idp"},
+		},
+		{
+			name:      "started v1",
+			mapper:    StartedEventMapper,
+			eventType: StartedEventType,
+			payload:   `{"version":1,"successURL":{"Scheme":"https","Host":"example.com","Path":"/success"},"idpId":"idp"}`,
+			want:      &StartedEvent{Version: 1, SuccessURL: successURL, IDPID: "idp"},
+		},
+		{
+			name:      "succeeded v0",
+			mapper:    SucceededEventMapper,
+			eventType: SucceededEventType,
+			payload:   `{"idpUserId":"idpUser","userId":"user","idpAccessToken":{"keyId":"key"}}`,
+			want:      &SucceededEvent{IDPUserID: "idpUser", UserID: "user", IDPAccessToken: &crypto.CryptoValue{KeyID: "key"}},
+		},
+		{
+			name:      "succeeded v1",
+			mapper:    SucceededEventMapper,
+			eventType: SucceededEventType,
+			payload:   `{"version":1,"idpUserId":"idpUser","userId":"user","idpAccessToken":{"keyId":"key"}}`,
+			want:      &SucceededEvent{Version: 1, IDPUserID: "idpUser", UserID: "user", IDPAccessToken: &crypto.CryptoValue{KeyID: "key"}},
+		},
+		{
+			name:      "saml succeeded v0",
+			mapper:    SAMLSucceededEventMapper,
+			eventType: SAMLSucceededEventType,
+			payload:   `{"idpUserId":"idpUser","assertion":{"keyId":"key"}}`,
+			want:      &SAMLSucceededEvent{IDPUserID: "idpUser", Assertion: &crypto.CryptoValue{KeyID: "key"}},
+		},
+		{
+			name:      "saml succeeded v1",
+			mapper:    SAMLSucceededEventMapper,
+			eventType: SAMLSucceededEventType,
+			payload:   `{"version":1,"idpUserId":"idpUser","assertion":{"keyId":"key"}}`,
+			want:      &SAMLSucceededEvent{Version: 1, IDPUserID: "idpUser", Assertion: &crypto.CryptoValue{KeyID: "key"}},
+		},
+		{
+			name:      "saml request v0",
+			mapper:    SAMLRequestEventMapper,
+			eventType: SAMLRequestEventType,
+			payload:   `{"requestId":"request"}`,
+			want:      &SAMLRequestEvent{RequestID: "request"},
+		},
+		{
+			name:      "saml request v1",
+			mapper:    SAMLRequestEventMapper,
+			eventType: SAMLRequestEventType,
+			payload:   `{"version":1,"requestId":"request"}`,
+			want:      &SAMLRequestEvent{Version: 1, RequestID: "request"},
+		},
+		{
+			name:      "ldap succeeded v0",
+			mapper:    LDAPSucceededEventMapper,
+			eventType: LDAPSucceededEventType,
+			payload:   `{"idpUserId":"idpUser","user":{"cn":["user"]}}`,
+			want:      &LDAPSucceededEvent{IDPUserID: "idpUser", EntryAttributes: map[string][]string{"cn": {"user"}}},
+		},
+		{
+			name:      "ldap succeeded v1",
+			mapper:    LDAPSucceededEventMapper,
+			eventType: LDAPSucceededEventType,
+			payload:   `{"version":1,"idpUserId":"idpUser","user":{"cn":["user"]}}`,
+			want:      &LDAPSucceededEvent{Version: 1, IDPUserID: "idpUser", EntryAttributes: map[string][]string{"cn": {"user"}}},
+		},
+		{
+			name:      "failed v0",
+			mapper:    FailedEventMapper,
+			eventType: FailedEventType,
+			payload:   `{"reason":"reason"}`,
+			want:      &FailedEvent{Reason: "reason"},
+		},
+		{
+			name:      "failed v1",
+			mapper:    FailedEventMapper,
+			eventType: FailedEventType,
+			payload:   `{"version":1,"reason":"reason"}`,
+			want:      &FailedEvent{Version: 1, Reason: "reason"},
+		},
+		{
+			name:      "unsupported version",
+			mapper:    FailedEventMapper,
+			eventType: FailedEventType,
+			payload:   `{"version":2,"reason":"reason"}`,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &eventstore.BaseEvent{
+				Agg:       &eventstore.Aggregate{ID: "intent", Type: AggregateType},
+				EventType: tt.eventType,
+				Data:      []byte(tt.payload),
+			}
+			got, err := tt.mapper(event)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// the base event is not part of the comparison
+			switch e := tt.want.(type) {
+			case *StartedEvent:
+				e.BaseEvent = *eventstore.BaseEventFromRepo(event)
+			case *SucceededEvent:
+				e.BaseEvent = *eventstore.BaseEventFromRepo(event)
+			case *SAMLSucceededEvent:
+				e.BaseEvent = *eventstore.BaseEventFromRepo(event)
+			case *SAMLRequestEvent:
+				e.BaseEvent = *eventstore.BaseEventFromRepo(event)
+			case *LDAPSucceededEvent:
+				e.BaseEvent = *eventstore.BaseEventFromRepo(event)
+			case *FailedEvent:
+				e.BaseEvent = *eventstore.BaseEventFromRepo(event)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
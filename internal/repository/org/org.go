@@ -17,6 +17,7 @@ const (
 	OrgDeactivatedEventType = orgEventTypePrefix + "deactivated"
 	OrgReactivatedEventType = orgEventTypePrefix + "reactivated"
 	OrgRemovedEventType     = orgEventTypePrefix + "removed"
+	OrgParentSetEventType   = orgEventTypePrefix + "parent.set"
 )
 
 func NewAddOrgNameUniqueConstraint(orgName string) *eventstore.UniqueConstraint {
@@ -167,6 +168,45 @@ func OrgReactivatedEventMapper(event eventstore.Event) (eventstore.Event, error)
 	}, nil
 }
 
+// OrgParentSetEvent places the org below the org with ParentID,
+// an empty ParentID makes it a top level org again.
+type OrgParentSetEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	ParentID string `json:"parentId,omitempty"`
+}
+
+func (e *OrgParentSetEvent) Payload() interface{} {
+	return e
+}
+
+func (e *OrgParentSetEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewOrgParentSetEvent(ctx context.Context, aggregate *eventstore.Aggregate, parentID string) *OrgParentSetEvent {
+	return &OrgParentSetEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			OrgParentSetEventType,
+		),
+		ParentID: parentID,
+	}
+}
+
+func OrgParentSetEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	parentSet := &OrgParentSetEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := event.Unmarshal(parentSet)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "ORG-Pa3nt", "unable to unmarshal org parent set")
+	}
+
+	return parentSet, nil
+}
+
 type OrgRemovedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 	name                 string
@@ -282,7 +282,8 @@ func NewWebAuthNCheckedEvent(
 type TOTPCheckedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
-	CheckedAt time.Time `json:"checkedAt"`
+	CheckedAt    time.Time `json:"checkedAt"`
+	UserVerified bool      `json:"userVerified,omitempty"`
 }
 
 func (e *TOTPCheckedEvent) Payload() interface{} {
@@ -301,6 +302,7 @@ func NewTOTPCheckedEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
 	checkedAt time.Time,
+	userVerified bool,
 ) *TOTPCheckedEvent {
 	return &TOTPCheckedEvent{
 		BaseEvent: *eventstore.NewBaseEventForPush(
@@ -308,7 +310,8 @@ func NewTOTPCheckedEvent(
 			aggregate,
 			TOTPCheckedType,
 		),
-		CheckedAt: checkedAt,
+		CheckedAt:    checkedAt,
+		UserVerified: userVerified,
 	}
 }
 
@@ -646,6 +649,8 @@ func NewLifetimeSetEvent(
 
 type TerminateEvent struct {
 	eventstore.BaseEvent `json:"-"`
+
+	Reason domain.SessionTerminationReason `json:"reason,omitempty"`
 }
 
 func (e *TerminateEvent) Payload() interface{} {
@@ -659,6 +664,7 @@ func (e *TerminateEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
 func NewTerminateEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
+	reason domain.SessionTerminationReason,
 ) *TerminateEvent {
 	return &TerminateEvent{
 		BaseEvent: *eventstore.NewBaseEventForPush(
@@ -666,11 +672,18 @@ func NewTerminateEvent(
 			aggregate,
 			TerminateType,
 		),
+		Reason: reason,
 	}
 }
 
 func TerminateEventMapper(event eventstore.Event) (eventstore.Event, error) {
-	return &TerminateEvent{
+	terminated := &TerminateEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}, nil
+	}
+	err := event.Unmarshal(terminated)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SESSION-Tr7mn", "unable to unmarshal session terminated")
+	}
+
+	return terminated, nil
 }